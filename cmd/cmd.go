@@ -1,21 +1,27 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"image"
+	"image/png"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/shunk031/tcardgen/pkg/canvas"
 	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/card"
 	"github.com/shunk031/tcardgen/pkg/config"
 	"github.com/shunk031/tcardgen/pkg/hugo"
+	"github.com/shunk031/tcardgen/pkg/pdf"
 )
 
 const (
@@ -33,8 +39,54 @@ tcardgen --fontDir=font --output=example/featured.png --template=example/templat
 # Generate multiple images.
 tcardgen --template=example/template.png example/*.md
 
+# Generate images for all contents in the directory. Page bundles are named after their directory.
+tcardgen --template=example/template.png content/post
+
+# Generate images on a template fetched from a URL, or read from the standard input.
+# The templates in the front-matter are fetched from URLs only with --allowRemoteImages.
+tcardgen --template=https://assets.example.com/template.png example/*.md
+curl -s https://assets.example.com/template.png | tcardgen --template=- example/*.md
+
 # Genrate an image based on the drawing configuration.
-tcardgen --config=config.yaml example/*.md`
+tcardgen --config=config.yaml example/*.md
+
+# Generate square images filled in white without a template.
+tcardgen --preset=Square example/*.md
+
+# Generate images for Chinese posts that do not define "lang".
+tcardgen --lang=zh-hans example/*.md
+
+# Generate an image for each row of a CSV/TSV file. The columns are named after the front-matter keys.
+tcardgen --nameColumn=slug cards.csv
+
+# Regenerate the cards on every change of the posts while writing.
+tcardgen --watch content/post
+
+# Print the cards to be generated from the contents and whether they are created, updated, or skipped.
+tcardgen --dryRun --skipUnchanged content/post
+
+# Generate only the cards older than their posts, the configuration, or the template.
+tcardgen --skipUnchanged content/post
+
+# Report the texts of the cards of the posts which overflow their regions with the configuration.
+tcardgen --lint -c config.yaml content/post
+
+# Fail to write the cards larger than 5 MB, which Twitter rejects.
+tcardgen --maxBytes=5000000 example/*.md
+
+# Fail to generate the cards with characters which the fonts cannot draw, e.g. CJK characters with a Latin font.
+tcardgen --strictGlyphs example/*.md
+
+# Generate images skipping draft posts.
+tcardgen --skipDrafts example/*.md
+
+# Generate multiple images and export them into a PDF for review.
+tcardgen --pdf=cards.pdf example/*.md`
+)
+
+var (
+	errSkipDraft     = errors.New("draft post is skipped")
+	errSkipUnchanged = errors.New("card is unchanged")
 )
 
 var (
@@ -45,6 +97,7 @@ var (
 )
 
 type IOStreams struct {
+	In     io.Reader
 	Out    io.Writer
 	ErrOut io.Writer
 }
@@ -56,12 +109,38 @@ type RootCommandOption struct {
 	output  string
 	tplImg  string
 	config  string
+	pdf     string
+	lang    string
+	preset  string
+
+	nameColumn  string
+	compression string
+	maxPixels   int
+	maxBytes    int
+
+	skipDrafts    bool
+	skipUnchanged bool
+	embedMetadata bool
+	allowRemote   bool
+	watch         bool
+	dryRun        bool
+	lint          bool
+	verbose       bool
+	strictGlyphs  bool
+
+	postProcessors []card.PostProcessor
+
+	// defaultTemplates caches the default templates read from URLs or the standard input,
+	// so that they are read only once even if the drawing is reloaded.
+	defaultTemplates map[string]image.Image
 }
 
-func NewRootCmd() *cobra.Command {
-	opt := RootCommandOption{}
+// NewRootCmd creates the tcardgen command.
+// The specified post processors are applied to every card before it is saved.
+func NewRootCmd(pps ...card.PostProcessor) *cobra.Command {
+	opt := RootCommandOption{postProcessors: pps}
 	cmd := &cobra.Command{
-		Use:                   "tcardgen [-f <FONTDIR>] [-o <OUTPUT>] [-t <TEMPLATE>] [-c <CONFIG>] <FILE>...",
+		Use:                   "tcardgen [-f <FONTDIR>] [-o <OUTPUT>] [-t <TEMPLATE>] [-c <CONFIG>] <FILE|DIR|CSV>...",
 		Version:               version,
 		DisableFlagsInUseLine: true,
 		SilenceUsage:          true,
@@ -69,8 +148,11 @@ func NewRootCmd() *cobra.Command {
 		Short:                 "Generate TwitterCard(OGP) image for your Hugo posts.",
 		Long:                  longDesc,
 		Example:               example,
+		// the contents are arbitrary arguments besides the subcommands
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			streams := IOStreams{
+				In:     os.Stdin,
 				Out:    os.Stdout,
 				ErrOut: os.Stderr,
 			}
@@ -83,8 +165,25 @@ func NewRootCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&opt.fontDir, "fontDir", "f", defaultFontDir, "Set a font directory.")
 	cmd.Flags().StringVarP(&opt.outDir, "outDir", "", "", "(DEPRECATED) Set an output directory.")
 	cmd.Flags().StringVarP(&opt.output, "output", "o", defaultOutput, "Set an output directory or filename (only png format).")
-	cmd.Flags().StringVarP(&opt.tplImg, "template", "t", "", fmt.Sprintf("Set a template image file. (default %s)", config.DefaultTemplate))
+	cmd.Flags().StringVarP(&opt.tplImg, "template", "t", "", fmt.Sprintf("Set a template image file, an HTTP(S) URL, or \"-\" to read it from the standard input. (default %s)", config.DefaultTemplate))
 	cmd.Flags().StringVarP(&opt.config, "config", "c", "", "Set a drawing configuration file.")
+	cmd.Flags().StringVarP(&opt.pdf, "pdf", "", "", "Also export the generated cards into a PDF contact sheet. It cannot be used with --watch.")
+	cmd.Flags().StringVarP(&opt.preset, "preset", "", "", "Set the card size of a social platform: OGP, TwitterLarge, LinkedIn, or Square. The template is resized to it, or filled without it.")
+	cmd.Flags().StringVarP(&opt.lang, "lang", "", "", "Set the language of posts that do not define \"lang\". It selects the line breaking rules.")
+	cmd.Flags().StringVarP(&opt.nameColumn, "nameColumn", "", hugo.DefaultRecordNameColumn, "Set the column of a CSV/TSV file used to name the cards.")
+	cmd.Flags().StringVarP(&opt.compression, "compression", "", "best", "Set the PNG compression level. One of best, default, speed, or none.")
+	cmd.Flags().IntVarP(&opt.maxBytes, "maxBytes", "", 0, "Fail to write the cards larger than the bytes, which some social platforms reject. 0 writes any size.")
+	cmd.Flags().IntVarP(&opt.maxPixels, "maxPixels", "", canvas.DefaultMaxPixels, "Set the number of pixels of the largest template to load, or 0 to load any size.")
+	cmd.Flags().BoolVarP(&opt.skipDrafts, "skipDrafts", "", false, "Skip the draft posts instead of generating their cards.")
+	cmd.Flags().BoolVarP(&opt.skipUnchanged, "skipUnchanged", "", false, "Skip the cards newer than all the files they are generated from: the contents, the configuration, the templates, the images, and the fonts.")
+	cmd.Flags().BoolVarP(&opt.watch, "watch", "w", false, "Watch the contents, the template, and the configuration, and regenerate cards on change.")
+	cmd.Flags().BoolVarP(&opt.allowRemote, "allowRemoteImages", "", false, "Allow the front-matter of the posts to fetch their templates and hero images from HTTP(S) URLs.")
+	cmd.Flags().BoolVarP(&opt.embedMetadata, "embedMetadata", "", false, "Embed the source path and the generation time into the PNG metadata.")
+	cmd.Flags().BoolVarP(&opt.dryRun, "dryRun", "", false, "Print the cards to be generated without generating them.")
+	cmd.Flags().BoolVarP(&opt.lint, "lint", "", false, "Report the texts which overflow their regions or wrap into more lines than maxLines, without generating the cards.")
+	cmd.Flags().BoolVarP(&opt.verbose, "verbose", "v", false, "Print the characters of the cards which the fonts do not have, which are drawn as boxes (tofu).")
+	cmd.Flags().BoolVarP(&opt.strictGlyphs, "strictGlyphs", "", false, "Fail to generate the cards with characters which the fonts do not have.")
+	cmd.AddCommand(NewInspectFontCmd(), NewFrontMatterCmd())
 	return cmd
 }
 
@@ -101,133 +200,501 @@ func (o *RootCommandOption) Validate(cmd *cobra.Command, args []string) error {
 		o.output += "/"
 	}
 
+	if _, ok := compressionLevels[o.compression]; !ok {
+		return fmt.Errorf("unknown compression level %q", o.compression)
+	}
+	if _, _, ok := config.SizePreset(o.preset).Size(); o.preset != "" && !ok {
+		return fmt.Errorf("unknown size preset %q", o.preset)
+	}
+	if o.dryRun && o.watch {
+		return errors.New("cannot watch the contents in dry-run mode")
+	}
+	if o.pdf != "" && o.watch {
+		return errors.New("cannot export the contact sheet in watch mode")
+	}
+	if o.lint && (o.dryRun || o.watch) {
+		return errors.New("cannot lint the cards in dry-run or watch mode")
+	}
+
 	o.files = args
 	return nil
 }
 
+var compressionLevels = map[string]png.CompressionLevel{
+	"best":    png.BestCompression,
+	"default": png.DefaultCompression,
+	"speed":   png.BestSpeed,
+	"none":    png.NoCompression,
+}
+
+// saveOptions returns the options to save the card generated from the source.
+func (o *RootCommandOption) saveOptions(src string, currentTime time.Time) []canvas.SaveOption {
+	sos := []canvas.SaveOption{canvas.CompressionLevel(compressionLevels[o.compression]), canvas.MaxBytes(o.maxBytes)}
+	if o.embedMetadata {
+		sos = append(sos, canvas.SourceMetadata(src, currentTime))
+	}
+	return sos
+}
+
+// cardOptions returns the options to generate the cards. The characters which the fonts do not have are
+// printed as warnings with --verbose, and fail the cards with --strictGlyphs.
+func (o *RootCommandOption) cardOptions(streams IOStreams) []card.Option {
+	if !o.verbose && !o.strictGlyphs {
+		return nil
+	}
+	return []card.Option{card.MissingGlyphs(func(e *canvas.MissingGlyphError) error {
+		if o.strictGlyphs {
+			return e
+		}
+		fmt.Fprintf(streams.Out, "WARN: %v\n", e)
+		return nil
+	})}
+}
+
 func (o *RootCommandOption) Run(streams IOStreams, currentTime time.Time) error {
+	if o.dryRun {
+		return o.runDry(streams, currentTime)
+	}
+	if o.lint {
+		return o.runLint(streams, currentTime)
+	}
+
 	ffa, err := fontfamily.LoadFromDir(o.fontDir)
 	if err != nil {
 		return err
 	}
 	fmt.Fprintf(streams.Out, "Load fonts from %q\n", o.fontDir)
 
-	cnf := &config.DrawingConfig{}
-	if o.config != "" {
-		cnf, err = config.LoadConfig(o.config)
+	cnf, tpls, err := o.loadDrawing(streams, ffa)
+	if err != nil {
+		return err
+	}
+
+	outDir, outFilename := o.splitOutput(streams)
+	if _, err := os.Stat(outDir); os.IsNotExist(err) {
+		err := os.Mkdir(outDir, 0755)
 		if err != nil {
 			return err
 		}
 	}
-	config.Defaulting(cnf, o.tplImg)
 
-	tpl, err := canvas.LoadFromFile(cnf.Template)
+	contents, failures, err := o.findContents(streams, outFilename)
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(streams.Out, "Load template from %q directory\n", cnf.Template)
 
-	outDir, outFilename := filepath.Split(o.output)
+	r := &runner{
+		o:           o,
+		streams:     streams,
+		ffa:         ffa,
+		cnf:         cnf,
+		tpls:        tpls,
+		outDir:      outDir,
+		outFilename: outFilename,
+		errs:        failures,
+	}
+	for _, content := range contents {
+		if err := r.generateContent(content, currentTime); err != nil {
+			return err
+		}
+	}
+
+	if o.pdf != "" && len(r.cards) > 0 {
+		if err := writeContactSheet(o.pdf, r.cards); err != nil {
+			return err
+		}
+		fmt.Fprintf(streams.Out, "Success to export %d twitter cards into %v\n", len(r.cards), o.pdf)
+	}
+
+	if o.watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return r.watch(ctx)
+	}
+
+	if len(r.errs) != 0 {
+		printFailures(streams.ErrOut, r.errs)
+		return fmt.Errorf("failed to generate %d twitter cards", len(r.errs))
+	}
+	return nil
+}
+
+// printFailures lists the errors of the failed cards, which are prefixed with their sources.
+func printFailures(w io.Writer, errs []error) {
+	fmt.Fprintf(w, "\nFailed contents:\n")
+	for _, err := range errs {
+		fmt.Fprintf(w, "  %v\n", err)
+	}
+}
+
+// sourceError prefixes the error with the source of the card, unless it is FMError which has the file.
+func sourceError(src string, err error) error {
+	var fe *hugo.FMError
+	if errors.As(err, &fe) {
+		return err
+	}
+	return fmt.Errorf("%s: %w", src, err)
+}
+
+// splitOutput returns the output directory and the output filename, which is empty unless it is specified.
+func (o *RootCommandOption) splitOutput(streams IOStreams) (outDir, outFilename string) {
+	outDir, outFilename = filepath.Split(o.output)
 	if o.output == defaultOutput && o.outDir != "" {
 		fmt.Fprint(streams.Out, "\nWarning: This flag will be removed in the future. Please use \"--output\".\n\n")
 		outDir = o.outDir
 	}
+	return outDir, outFilename
+}
 
-	if _, err := os.Stat(outDir); os.IsNotExist(err) {
-		err := os.Mkdir(outDir, 0755)
+// findContents returns the contents of the files and directories to generate the cards, and the errors of
+// the paths which cannot be read. The errors are printed, and fail only those paths.
+func (o *RootCommandOption) findContents(streams IOStreams, outFilename string) ([]*hugo.Content, []error, error) {
+	contents, err := hugo.FindContents(o.files...)
+	failures := unjoin(err)
+	for _, err := range failures {
+		fmt.Fprintf(streams.ErrOut, "Failed to find contents: %v\n", err)
+	}
+	if outFilename != "" && len(contents) > 1 {
+		return nil, nil, errors.New("cannot accept multiple contents when you specify output filename")
+	}
+	return contents, failures, nil
+}
+
+// unjoin returns the errors joined by errors.Join, the error itself if it is not joined, or nil.
+func unjoin(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if je, ok := err.(interface{ Unwrap() []error }); ok {
+		return je.Unwrap()
+	}
+	return []error{err}
+}
+
+// loadConfig loads the drawing configuration and defaults it.
+func (o *RootCommandOption) loadConfig() (*config.DrawingConfig, error) {
+	cnf := &config.DrawingConfig{}
+	if o.config != "" {
+		var err error
+		cnf, err = config.LoadConfig(o.config)
 		if err != nil {
-			return err
+			return nil, err
+		}
+	}
+	if o.preset != "" {
+		if cnf.Size == nil {
+			cnf.Size = &config.SizeOption{}
 		}
+		cnf.Size.Preset = config.SizePreset(o.preset)
 	}
+	config.Defaulting(cnf, o.tplImg)
+	if o.lang != "" {
+		cnf.FrontMatter.DefaultLang = o.lang
+	}
+	return cnf, nil
+}
 
-	var errCnt int
-	for _, f := range o.files {
-		out := filepath.Join(outDir, outFilename)
-		if outFilename == "" {
-			base := filepath.Base(f)
-			out += fmt.Sprintf("/%s.png", base[:len(base)-len(filepath.Ext(base))])
+// loadDrawing loads the drawing configuration and the template, and validates them.
+func (o *RootCommandOption) loadDrawing(streams IOStreams, ffa *fontfamily.FontFamily) (*config.DrawingConfig, *templates, error) {
+	cnf, err := o.loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tpl image.Image
+	if cnf.Template == "" {
+		if tpl, err = card.Background(cnf.Size); err != nil {
+			return nil, nil, err
 		}
+		fmt.Fprintf(streams.Out, "Fill the %s background without a template\n", cnf.Size.Preset)
+	} else {
+		if tpl, err = o.loadDefaultTemplate(streams, cnf.Template); err != nil {
+			return nil, nil, err
+		}
+		fmt.Fprintf(streams.Out, "Load template from %q directory\n", cnf.Template)
+	}
 
-		if err := generateTCard(streams, f, out, tpl, ffa, cnf, currentTime); err != nil {
-			fmt.Fprintf(streams.ErrOut, "Failed to generate twitter card for %v: %v\n", out, err)
-			errCnt++
-			continue
+	if err := cnf.Validate(card.Bounds(tpl, cnf.Size), ffa); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	if tpl, err = card.Resize(tpl, cnf.Size); err != nil {
+		return nil, nil, fmt.Errorf("failed to resize template: %w", err)
+	}
+	tpls := newTemplates(tpl, o.loadOptions()...)
+	tpls.allowRemote = o.allowRemote
+	tpls.warn = streams.ErrOut
+	tpls.size = cnf.Size
+	if cnf.Dark != nil && cnf.Dark.Template != "" {
+		if tpls.dark, err = o.loadDefaultTemplate(streams, cnf.Dark.Template); err != nil {
+			return nil, nil, fmt.Errorf("failed to load the template of the dark variant: %w", err)
+		}
+		// the dark cards are laid out on their own template, which may be of another size without the size
+		if err := cnf.Variant(cnf.Dark).Validate(card.Bounds(tpls.dark, cnf.Size), ffa); err != nil {
+			return nil, nil, fmt.Errorf("invalid configuration of the dark variant:\n%w", err)
+		}
+		if tpls.dark, err = card.Resize(tpls.dark, cnf.Size); err != nil {
+			return nil, nil, fmt.Errorf("failed to resize the template of the dark variant: %w", err)
+		}
+	}
+	return cnf, tpls, nil
+}
+
+// loadDefaultTemplate loads the default template from a file, an HTTP(S) URL, or the standard input ("-").
+// The templates read from URLs or the standard input are cached.
+func (o *RootCommandOption) loadDefaultTemplate(streams IOStreams, src string) (image.Image, error) {
+	if img, ok := o.defaultTemplates[src]; ok {
+		return img, nil
+	}
+	var img image.Image
+	var err error
+	switch {
+	case src == stdinTemplate:
+		if streams.In == nil {
+			return nil, errors.New("standard input is not available to read the template")
 		}
-		fmt.Fprintf(streams.Out, "Success to generate twitter card into %v\n", out)
+		img, err = decodeTemplate(streams.In, o.loadOptions()...)
+	case isRemote(src):
+		img, err = fetchTemplate(src, o.loadOptions()...)
+	default:
+		img, err = canvas.LoadFromFile(src, o.loadOptions()...)
 	}
+	if errors.Is(err, canvas.ErrImageTooLarge) {
+		return nil, fmt.Errorf("%w: set --maxPixels to load a larger template", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !isRemote(src) && src != stdinTemplate {
+		return img, nil
+	}
+	if o.defaultTemplates == nil {
+		o.defaultTemplates = map[string]image.Image{}
+	}
+	o.defaultTemplates[src] = img
+	return img, nil
+}
 
-	if errCnt != 0 {
-		return fmt.Errorf("failed to generate %d twitter cards", errCnt)
+// loadOptions returns the options to decode the templates.
+func (o *RootCommandOption) loadOptions() []canvas.LoadOption {
+	return []canvas.LoadOption{canvas.MaxPixels(o.maxPixels)}
+}
+
+// runner generates the cards of contents with the loaded fonts, configuration, and templates.
+type runner struct {
+	o       *RootCommandOption
+	streams IOStreams
+	ffa     *fontfamily.FontFamily
+	cnf     *config.DrawingConfig
+	tpls    *templates
+
+	outDir      string
+	outFilename string
+
+	// errs are the errors of the failed cards.
+	errs  []error
+	cards []string
+	// sources are the sources of the cards written in this run by their paths, which detect the cards
+	// written to the same path from different sources.
+	sources map[string]string
+}
+
+// generateContent generates the cards of the content, which are the cards of all the records for
+// a CSV/TSV file. A failure of each card is counted and reported instead of being returned.
+func (r *runner) generateContent(content *hugo.Content, currentTime time.Time) error {
+	f := content.Path
+	if hugo.IsRecordFile(f) {
+		if r.outFilename != "" {
+			return errors.New("cannot accept a CSV/TSV file when you specify output filename")
+		}
+		records, err := hugo.ReadRecordFile(r.streams.Out, f, r.o.nameColumn, currentTime, card.ParseOptions(r.cnf)...)
+		if err != nil {
+			// the file which cannot be read fails as a whole, and the other contents are still used
+			fmt.Fprintf(r.streams.ErrOut, "Failed to read %v: %v\n", f, err)
+			r.errs = append(r.errs, sourceError(f, err))
+			return nil
+		}
+		for _, rec := range records {
+			r.generateCards(recordSource(f, rec), f, recordOutput(rec, r.outDir), rec.FrontMatter, rec.Err, currentTime)
+		}
+		return nil
 	}
+
+	fm, err := hugo.ParseFrontMatter(r.streams.Out, f, currentTime, card.ParseOptions(r.cnf)...)
+	r.generateCards(f, f, contentOutput(content, fm, r.outDir, r.outFilename), fm, err, currentTime)
 	return nil
 }
 
-func generateTCard(streams IOStreams, contentPath, outPath string, tpl image.Image, ffa *fontfamily.FontFamily, cnf *config.DrawingConfig, currentTime time.Time) error {
-	fm, err := hugo.ParseFrontMatter(streams.Out, contentPath, currentTime)
-	if err != nil {
-		return err
+// generateCards generates the card of the front-matter parsed from the content, and the card of the dark
+// variant besides it if the variant is configured and the card is generated. The error of parsing fails the card.
+func (r *runner) generateCards(src, contentPath, out string, fm *hugo.FrontMatter, fmErr error, currentTime time.Time) {
+	render := func(out string, tpls *templates, cnf *config.DrawingConfig) func() error {
+		return func() error {
+			if fmErr != nil {
+				return fmErr
+			}
+			if r.o.skipUnchanged && !(fm.Draft && r.o.skipDrafts) && r.o.isUnchanged(out, r.o.cardSources(fm, contentPath, cnf)) {
+				return errSkipUnchanged
+			}
+			return renderTCard(fm, contentPath, out, tpls, r.ffa, cnf, r.o.postProcessors, r.o.skipDrafts, r.o.cardOptions(r.streams), currentTime, r.o.saveOptions(contentPath, currentTime)...)
+		}
+	}
+	if !r.generate(src, out, render(out, r.tpls, r.cnf)) || r.cnf.Dark == nil {
+		return
+	}
+	dark := variantOutput(out, r.cnf.Dark.Suffix)
+	r.generate(src, dark, render(dark, r.tpls.darkVariant(), r.cnf.Variant(r.cnf.Dark)))
+}
+
+// contentOutput returns the path of the card of the content unless the output filename is specified.
+// The card is named after the slug or url of the front-matter, which may be nil, or the content.
+func contentOutput(content *hugo.Content, fm *hugo.FrontMatter, outDir, outFilename string) string {
+	out := filepath.Join(outDir, outFilename)
+	if outFilename == "" {
+		out += fmt.Sprintf("/%s.png", content.CardName(fm))
 	}
+	return out
+}
 
-	c, err := canvas.CreateCanvasFromImage(tpl)
+// variantOutput returns the path of the card of the variant with the suffix, which is appended to the name
+// of the card before its extension, e.g. "first-dark.png" of "first.png".
+func variantOutput(out, suffix string) string {
+	ext := filepath.Ext(out)
+	return strings.TrimSuffix(out, ext) + suffix + ext
+}
+
+// recordSource returns the source of the card of the CSV/TSV record, which is told apart from the other
+// records of the same name by its line.
+func recordSource(filename string, rec *hugo.Record) string {
+	return fmt.Sprintf("%s:%d (%s)", filename, rec.Line, rec.Name)
+}
+
+// recordOutput returns the path of the card of the CSV/TSV record.
+func recordOutput(rec *hugo.Record, outDir string) string {
+	return filepath.Join(outDir, rec.Name+".png")
+}
+
+// generate generates the card of the source into out, and reports whether it is generated or unchanged.
+// The card fails if another source has already generated a card into out, instead of overwriting it.
+func (r *runner) generate(src, out string, gen func() error) bool {
+	key := absPath(out)
+	var err error
+	if prev, ok := r.sources[key]; ok && prev != src {
+		err = fmt.Errorf("the card is already generated from %v", prev)
+	} else {
+		err = gen()
+	}
+	switch {
+	case errors.Is(err, errSkipDraft):
+		fmt.Fprintf(r.streams.Out, "Skip draft %v\n", src)
+		return false
+	case errors.Is(err, errSkipUnchanged):
+		fmt.Fprintf(r.streams.Out, "Skip unchanged twitter card %v\n", out)
+	case err != nil:
+		fmt.Fprintf(r.streams.ErrOut, "Failed to generate twitter card for %v: %v\n", out, err)
+		r.errs = append(r.errs, sourceError(src, err))
+		return false
+	default:
+		fmt.Fprintf(r.streams.Out, "Success to generate twitter card into %v\n", out)
+	}
+	r.cards = append(r.cards, out)
+	if r.sources == nil {
+		r.sources = map[string]string{}
+	}
+	r.sources[key] = src
+	return true
+}
+
+// isUnchanged reports whether the card at out is newer than all the files which it is generated from, which
+// are returned by cardSources. The files fetched from URLs or read from the standard input are not compared.
+func (o *RootCommandOption) isUnchanged(out string, srcs []string) bool {
+	fi, err := os.Stat(out)
 	if err != nil {
-		return err
+		return false
 	}
+	for _, src := range srcs {
+		if src == "" || src == stdinTemplate || isRemote(src) {
+			continue
+		}
+		si, err := os.Stat(src)
+		if err != nil || !si.ModTime().Before(fi.ModTime()) {
+			return false
+		}
+	}
+	return true
+}
 
-	var tags []string
-	lim := len(fm.Tags)
-	if l := cnf.Tags.Limit; l > 0 && l <= lim {
-		lim = l
+// cardSources returns the files which the card of the post is generated from: the content, the configuration,
+// the default template, the template of the post resolved as templates.forPost does, the avatar, the brand logo,
+// and the fonts. A resource which cannot be resolved is returned as is, so that the card is never unchanged.
+func (o *RootCommandOption) cardSources(fm *hugo.FrontMatter, contentPath string, cnf *config.DrawingConfig) []string {
+	content := hugo.NewContent(contentPath)
+	resource := func(name string) string {
+		if isRemote(name) {
+			return name
+		}
+		if p, err := content.Resource(name); err == nil {
+			return p
+		}
+		return name
 	}
 
-	for _, t := range fm.Tags[:lim] {
-		if *cnf.Tags.TitleCaseEnabled {
-			t = strings.Title(t)
+	srcs := []string{contentPath, o.config, cnf.Template}
+	if fm.Template != "" {
+		srcs = append(srcs, resource(fm.Template))
+	} else if p, ok := content.BundleResource(cnf.BundleTemplate); ok {
+		srcs = append(srcs, p)
+	} else if *cnf.HeroImage.Enabled && fm.Image != "" {
+		srcs = append(srcs, resource(fm.Image))
+	}
+	if *cnf.Avatar.Enabled {
+		if fm.Avatar != "" {
+			srcs = append(srcs, resource(fm.Avatar))
+		} else {
+			srcs = append(srcs, cnf.Avatar.Src)
 		}
-		tags = append(tags, t)
 	}
+	if *cnf.Brand.Enabled && *cnf.Brand.Logo.Enabled {
+		srcs = append(srcs, cnf.Brand.Logo.Src)
+	}
+	// the fonts are not compared if they cannot be read, e.g. in dry-run mode, where they are not loaded
+	if entries, err := os.ReadDir(o.fontDir); err == nil {
+		srcs = append(srcs, o.fontDir)
+		for _, e := range entries {
+			srcs = append(srcs, filepath.Join(o.fontDir, e.Name()))
+		}
+	}
+	return srcs
+}
 
-	/* Title */
-	if err := c.DrawTextAtPoint(
-		fm.Title,
-		*cnf.Title.Start,
-		canvas.MaxWidth(cnf.Title.MaxWidth),
-		canvas.LineSpacing(*cnf.Title.LineSpacing),
-		canvas.FgHexColor(cnf.Title.FgHexColor),
-		canvas.FontFaceFromFFA(ffa, cnf.Title.FontStyle, cnf.Title.FontSize),
-	); err != nil {
+// writeContactSheet writes the contact sheet of the cards into the file. The file is closed explicitly,
+// so that a failure to flush it is reported as well.
+func writeContactSheet(filename string, cards []string) error {
+	f, err := os.Create(filename)
+	if err != nil {
 		return err
 	}
-	if err := c.DrawTextAtPoint(
-		fm.Category,
-		*cnf.Category.Start,
-		canvas.FgHexColor(cnf.Category.FgHexColor),
-		canvas.FontFaceFromFFA(ffa, cnf.Category.FontStyle, cnf.Category.FontSize),
-	); err != nil {
+	if err := pdf.NewContactSheet().Write(f, cards); err != nil {
+		f.Close()
 		return err
 	}
-	if err := c.DrawTextAtPoint(
-		fmt.Sprintf("%s%s%s", fm.Authors, cnf.Info.Separator, fm.Date.Format("Jan 2")),
-		*cnf.Info.Start,
-		canvas.FgHexColor(cnf.Info.FgHexColor),
-		canvas.FontFaceFromFFA(ffa, cnf.Info.FontStyle, cnf.Info.FontSize),
-	); err != nil {
+	return f.Close()
+}
+
+// renderTCard draws the card of the front-matter on its template with the card options and saves it.
+// Relative dates are formatted against the current time.
+func renderTCard(fm *hugo.FrontMatter, contentPath, outPath string, tpls *templates, ffa *fontfamily.FontFamily, cnf *config.DrawingConfig, pps []card.PostProcessor, skipDrafts bool, cos []card.Option, currentTime time.Time, sos ...canvas.SaveOption) error {
+	if fm.Draft && skipDrafts {
+		return errSkipDraft
+	}
+
+	tpl, err := tpls.forPost(fm, contentPath, cnf)
+	if err != nil {
 		return err
 	}
-	/* Tags */
-	if *cnf.Tags.Enabled {
-		if err := c.DrawBoxTexts(
-			tags,
-			*cnf.Tags.Start,
-			canvas.FgHexColor(cnf.Tags.FgHexColor),
-			canvas.BgHexColor(cnf.Tags.BgHexColor),
-			canvas.BoxPadding(*cnf.Tags.BoxPadding),
-			canvas.BoxSpacing(*cnf.Tags.BoxSpacing),
-			canvas.BoxAlign(cnf.Tags.BoxAlign),
-			canvas.FontFaceFromFFA(ffa, cnf.Tags.FontStyle, cnf.Tags.FontSize),
-		); err != nil {
-			return err
-		}
+	cos = append([]card.Option{card.ContentPath(contentPath), card.Now(currentTime), card.ImageLoader(tpls.image)}, cos...)
+	c, err := card.Generate(card.Config{Drawing: cnf, Fonts: ffa, Template: tpl, PostProcessors: pps}, fm, cos...)
+	if err != nil {
+		return err
 	}
-
-	return c.SaveAsPNG(outPath)
+	return c.SaveAsPNG(outPath, sos...)
 }
@@ -0,0 +1,1185 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/card"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+const testPost = `---
+title: "First post"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["hugo"]
+categories: ["program"]
+---`
+
+func TestGenerateTCardDrawsBrand(t *testing.T) {
+	ffa := testfont.New(t)
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(tpl, tpl.Bounds(), image.White, image.Point{}, draw.Src)
+
+	posts := []string{testPost, `+++
+title = "Another post with a different title"
+authors = ["alice", "bob"]
+date = "2021-01-02T00:00:00Z"
+tags = ["go", "OGP", "test"]
+categories = ["misc"]
++++`}
+	brandRegion := image.Rect(0, 0, 600, 100)
+
+	testCases := []struct {
+		desc      string
+		brand     string
+		expectInk bool
+	}{
+		{desc: "Brand is drawn when text is set", brand: "example.com", expectInk: true},
+		{desc: "Brand is not drawn when text is empty", brand: "", expectInk: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var regions []*image.RGBA
+			for i, post := range posts {
+				cnf := &config.DrawingConfig{Brand: &config.BrandOption{Text: tc.brand}}
+				config.Defaulting(cnf, "")
+
+				img := mustGenerateTCard(t, ffa, tpl, cnf, nil, post, i)
+				region := img.SubImage(brandRegion).(*image.RGBA)
+				if got := hasInk(region); got != tc.expectInk {
+					t.Fatalf("post #%d: brand region has ink=%v, want=%v", i, got, tc.expectInk)
+				}
+				regions = append(regions, region)
+			}
+			if !sameRegion(regions[0], regions[1]) {
+				t.Fatal("brand region differs between posts")
+			}
+		})
+	}
+}
+
+func TestGenerateTCardRunsPostProcessors(t *testing.T) {
+	ffa := testfont.New(t)
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	cnf := &config.DrawingConfig{}
+	config.Defaulting(cnf, "")
+
+	var gotTitle string
+	red := color.RGBA{255, 0, 0, 255}
+	pp := card.PostProcessorFunc(func(dst *image.RGBA, fm *hugo.FrontMatter) error {
+		gotTitle = fm.Title
+		dst.SetRGBA(0, 0, red)
+		return nil
+	})
+
+	img := mustGenerateTCard(t, ffa, tpl, cnf, []card.PostProcessor{pp}, testPost, 0)
+	if gotTitle != "First post" {
+		t.Fatalf("post processor received unexpected front matter: title=%q", gotTitle)
+	}
+	if got := img.RGBAAt(0, 0); got != red {
+		t.Fatalf("pixel painted by post processor is not persisted: got=%v, want=%v", got, red)
+	}
+}
+
+func TestGenerateTCardDrawsDraftWatermark(t *testing.T) {
+	ffa := testfont.New(t)
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(tpl, tpl.Bounds(), image.White, image.Point{}, draw.Src)
+	draft := testPost[:len(testPost)-len("---")] + "draft: true\n---"
+
+	newConfig := func(enabled bool) *config.DrawingConfig {
+		cnf := &config.DrawingConfig{Draft: &config.WatermarkOption{
+			TextOption: config.TextOption{Enabled: &enabled},
+		}}
+		config.Defaulting(cnf, "")
+		return cnf
+	}
+
+	testCases := []struct {
+		desc            string
+		post            string
+		expectWatermark bool
+	}{
+		{desc: "Draft post gets the watermark", post: draft, expectWatermark: true},
+		{desc: "Published post does not get the watermark", post: testPost, expectWatermark: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			with := mustGenerateTCard(t, ffa, tpl, newConfig(true), nil, tc.post, 0)
+			without := mustGenerateTCard(t, ffa, tpl, newConfig(false), nil, tc.post, 0)
+			if got := !sameRegion(with, without); got != tc.expectWatermark {
+				t.Fatalf("watermark is drawn=%v, want=%v", got, tc.expectWatermark)
+			}
+		})
+	}
+}
+
+func TestGenerateTCardSkipsDrafts(t *testing.T) {
+	ffa := testfont.New(t)
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	cnf := &config.DrawingConfig{}
+	config.Defaulting(cnf, "")
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "post.md")
+	draft := testPost[:len(testPost)-len("---")] + "draft: true\n---"
+	if err := os.WriteFile(in, []byte(draft), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "post.png")
+	streams := IOStreams{Out: io.Discard, ErrOut: io.Discard}
+	fm, err := hugo.ParseFrontMatter(streams.Out, in, time.Now(), card.ParseOptions(cnf)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := renderTCard(fm, in, out, newTemplates(tpl), ffa, cnf, nil, true, nil, time.Now()); !errors.Is(err, errSkipDraft) {
+		t.Fatalf("draft is not skipped: %v", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Fatalf("card is generated for the skipped draft: %v", err)
+	}
+}
+
+func TestGenerateTCardDrawsTopBorder(t *testing.T) {
+	ffa := testfont.New(t)
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(tpl, tpl.Bounds(), image.White, image.Point{}, draw.Src)
+
+	testCases := []struct {
+		desc            string
+		border          config.BorderOption
+		expectColor     color.RGBA
+		expectThickness int
+	}{
+		{
+			desc:            "Category color is used",
+			border:          config.BorderOption{CategoryHexColors: map[string]string{"program": "#FF0000"}},
+			expectColor:     color.RGBA{255, 0, 0, 255},
+			expectThickness: 12,
+		},
+		{
+			desc:            "Default color is used for an unmapped category",
+			border:          config.BorderOption{HexColor: "#00FF00", CategoryHexColors: map[string]string{"misc": "#FF0000"}},
+			expectColor:     color.RGBA{0, 255, 0, 255},
+			expectThickness: 12,
+		},
+		{
+			desc:            "Thickness scales with the canvas height",
+			border:          config.BorderOption{HexColor: "#0000FF", ThicknessPercent: 2},
+			expectColor:     color.RGBA{0, 0, 255, 255},
+			expectThickness: 13,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			enabled := true
+			tc.border.Enabled = &enabled
+			cnf := &config.DrawingConfig{TopBorder: &tc.border}
+			config.Defaulting(cnf, "")
+
+			img := mustGenerateTCard(t, ffa, tpl, cnf, nil, testPost, 0)
+			for _, x := range []int{0, 600, 1199} {
+				if got := img.RGBAAt(x, tc.expectThickness-1); got != tc.expectColor {
+					t.Fatalf("unexpected border color at x=%d: got=%v, want=%v", x, got, tc.expectColor)
+				}
+				if got := img.RGBAAt(x, tc.expectThickness); got == tc.expectColor {
+					t.Fatalf("border is thicker than %dpx at x=%d", tc.expectThickness, x)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTCardPromotesFirstTagToCategory(t *testing.T) {
+	ffa := testfont.New(t)
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(tpl, tpl.Bounds(), image.White, image.Point{}, draw.Src)
+	cnf := &config.DrawingConfig{FrontMatter: &config.FrontMatterOption{CategoryFromFirstTag: true}}
+	config.Defaulting(cnf, "")
+
+	categoryless := `---
+title: "First post"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["program", "hugo"]
+---`
+	categoryRegion := image.Rect(0, 100, 1200, 170)
+
+	got := mustGenerateTCard(t, ffa, tpl, cnf, nil, categoryless, 0).SubImage(categoryRegion).(*image.RGBA)
+	want := mustGenerateTCard(t, ffa, tpl, cnf, nil, testPost, 1).SubImage(categoryRegion).(*image.RGBA)
+	if !hasInk(got) {
+		t.Fatal("category slot is empty")
+	}
+	if !sameRegion(got, want) {
+		t.Fatal("first tag is not drawn in the category slot")
+	}
+}
+
+func TestRunGeneratesCardsFromCSV(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	csv := filepath.Join(dir, "cards.csv")
+	if err := os.WriteFile(csv, []byte(`slug,title,authors,categories,tags,date
+first,"First, with a comma","alice;bob",program,hugo;go,2020-06-21
+second,"Second ""quoted"" title",carol,misc,ogp,2021-01-02
+third,Third title,dave,misc,ogp
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the file without the header fails without stopping the others
+	empty := filepath.Join(dir, "empty.csv")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(dir, "out")
+	o := &RootCommandOption{
+		files:      []string{empty, csv},
+		fontDir:    testfont.Dir(t),
+		output:     outDir + "/",
+		tplImg:     tpl,
+		nameColumn: "slug",
+	}
+	if err := o.Run(IOStreams{Out: io.Discard, ErrOut: io.Discard}, time.Now()); err == nil || err.Error() != "failed to generate 1 twitter cards" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if want := []string{"first.png", "second.png", "third.png"}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected cards: got=%q, want=%q", got, want)
+	}
+}
+
+func TestRunFailsCSVRowsOfSameName(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	csv := filepath.Join(dir, "cards.csv")
+	if err := os.WriteFile(csv, []byte(`name,title,authors,categories,tags
+hello,First,alice,misc,ogp
+hello,Second,bob,misc,ogp
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var errOut bytes.Buffer
+	o := &RootCommandOption{
+		files:      []string{csv},
+		fontDir:    testfont.Dir(t),
+		output:     filepath.Join(dir, "out") + "/",
+		tplImg:     tpl,
+		nameColumn: hugo.DefaultRecordNameColumn,
+	}
+	if err := o.Run(IOStreams{Out: io.Discard, ErrOut: &errOut}, time.Now()); err == nil || err.Error() != "failed to generate 1 twitter cards" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, summary, _ := strings.Cut(errOut.String(), "Failed contents:\n")
+	if want := "the card is already generated from " + csv + ":2 (hello)"; !strings.Contains(summary, csv+":3 (hello)") || !strings.Contains(summary, want) {
+		t.Fatalf("the later row is not failed: %q", summary)
+	}
+}
+
+func TestRunNamesCardsAfterSlug(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	posts := map[string]string{
+		"first.md":  testPost,
+		"second.md": strings.Replace(testPost, "---\n", "---\nslug: \"hello-world\"\n", 1),
+		"third.md":  strings.Replace(testPost, "---\n", "---\nurl: \"/posts/third-post/\"\n", 1),
+	}
+	for name, post := range posts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(post), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outDir := filepath.Join(dir, "out")
+	o := &RootCommandOption{
+		files:   []string{filepath.Join(dir, "first.md"), filepath.Join(dir, "second.md"), filepath.Join(dir, "third.md")},
+		fontDir: testfont.Dir(t),
+		output:  outDir + "/",
+		tplImg:  tpl,
+	}
+	if err := o.Run(IOStreams{Out: io.Discard, ErrOut: io.Discard}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if want := []string{"first.png", "hello-world.png", "third-post.png"}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected cards: got=%q, want=%q", got, want)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	dir := t.TempDir()
+	draft := strings.Replace(testPost, "---\ntitle", "---\ndraft: true\ntitle", 1)
+	for name, post := range map[string]string{"existing.md": testPost, "unchanged.md": testPost, "new.md": testPost, "draft.md": draft, "broken.md": "---\ntitle: [\n---"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(post), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outDir := filepath.Join(dir, "out")
+	if err := os.Mkdir(outDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	tpl := filepath.Join(dir, "template.png")
+	if err := os.WriteFile(tpl, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the card of the existing post is older than the post, and the unchanged one is newer
+	for name, mtime := range map[string]time.Time{"existing.png": time.Now().Add(-time.Hour), "unchanged.png": time.Now().Add(time.Hour)} {
+		if err := os.WriteFile(filepath.Join(outDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filepath.Join(outDir, name), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	o := &RootCommandOption{
+		files:         []string{dir},
+		fontDir:       filepath.Join(dir, "nofont"),
+		output:        outDir + "/",
+		tplImg:        tpl,
+		dryRun:        true,
+		skipDrafts:    true,
+		skipUnchanged: true,
+	}
+	// the failures are reported without failing the dry run
+	var errOut bytes.Buffer
+	if err := o.Run(IOStreams{Out: &out, ErrOut: &errOut}, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "broken.md") {
+		t.Fatalf("the failure is not reported: %s", errOut.String())
+	}
+
+	got := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n")[1:] {
+		fields := strings.Fields(line)
+		got[filepath.Base(fields[0])] = fields[len(fields)-1]
+	}
+	want := map[string]string{"broken.md": actionFail, "draft.md": actionSkip, "existing.md": actionUpdate, "unchanged.md": actionSkip, "new.md": actionCreate}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected actions: got=%v, want=%v\n%s", got, want, out.String())
+	}
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("cards are written in dry-run mode: %v", entries)
+	}
+}
+
+func TestRunSkipsUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	// the post is drawn on its own template
+	post := filepath.Join(dir, "post.md")
+	if err := os.WriteFile(post, []byte(strings.Replace(testPost, "---\n", "---\ntcardTemplate: post.png\n", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tpl, postTpl := filepath.Join(dir, "template.png"), filepath.Join(dir, "post.png")
+	for _, p := range []string{tpl, postTpl} {
+		if err := canvas.SaveAsPNG(p, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fontDir := testfont.Dir(t)
+	fonts, err := os.ReadDir(fontDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the sources are older than the cards generated from them
+	past := time.Now().Add(-time.Hour)
+	srcs := []string{post, tpl, postTpl, fontDir}
+	for _, f := range fonts {
+		srcs = append(srcs, filepath.Join(fontDir, f.Name()))
+	}
+	for _, p := range srcs {
+		if err := os.Chtimes(p, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outDir := filepath.Join(dir, "out")
+	o := &RootCommandOption{
+		files:         []string{post},
+		fontDir:       fontDir,
+		output:        outDir + "/",
+		tplImg:        tpl,
+		skipUnchanged: true,
+	}
+	run := func() string {
+		var out bytes.Buffer
+		if err := o.Run(IOStreams{Out: &out, ErrOut: io.Discard}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	if out := run(); !strings.Contains(out, "Success to generate") {
+		t.Fatalf("the new card is not generated: %s", out)
+	}
+	if out := run(); !strings.Contains(out, "Skip unchanged") {
+		t.Fatalf("the unchanged card is generated again: %s", out)
+	}
+	// each of the templates changed alone generates the card again
+	future := time.Now().Add(time.Hour)
+	for _, p := range []string{postTpl, tpl} {
+		if err := os.Chtimes(p, future, future); err != nil {
+			t.Fatal(err)
+		}
+		if out := run(); !strings.Contains(out, "Success to generate") {
+			t.Fatalf("the card of the changed template %s is not generated: %s", p, out)
+		}
+		if err := os.Chtimes(p, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRunListsFailures(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "post", "broken.md")
+	if err := os.MkdirAll(filepath.Dir(broken), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for p, post := range map[string]string{broken: "---\ntitle: [\"Title\"]\n---", filepath.Join(dir, "post", "ok.md"): testPost} {
+		if err := os.WriteFile(p, []byte(post), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var errOut bytes.Buffer
+	o := &RootCommandOption{
+		files:   []string{filepath.Join(dir, "post")},
+		fontDir: testfont.Dir(t),
+		output:  filepath.Join(dir, "out") + "/",
+		tplImg:  tpl,
+	}
+	err := o.Run(IOStreams{Out: io.Discard, ErrOut: &errOut}, time.Now())
+	if err == nil || err.Error() != "failed to generate 1 twitter cards" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, summary, _ := strings.Cut(errOut.String(), "Failed contents:\n")
+	if want := fmt.Sprintf("  %s:2: %q type of []interface {} can not convert to string\n", broken, "title"); summary != want {
+		t.Fatalf("unexpected summary: got=%q, want=%q", summary, want)
+	}
+}
+
+func TestRunContinuesAfterFailedPaths(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	// the bundles in different sections are named after the same directory
+	for _, section := range []string{"news", "post"} {
+		index := filepath.Join(dir, "content", section, "hello", "index.md")
+		if err := os.MkdirAll(filepath.Dir(index), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(index, []byte(testPost), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	missing := filepath.Join(dir, "missing.md")
+
+	var errOut bytes.Buffer
+	outDir := filepath.Join(dir, "out")
+	o := &RootCommandOption{
+		files:   []string{missing, filepath.Join(dir, "content")},
+		fontDir: testfont.Dir(t),
+		output:  outDir + "/",
+		tplImg:  tpl,
+	}
+	err := o.Run(IOStreams{Out: io.Discard, ErrOut: &errOut}, time.Now())
+	if err == nil || err.Error() != "failed to generate 2 twitter cards" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, summary, _ := strings.Cut(errOut.String(), "Failed contents:\n")
+	for _, want := range []string{missing, "the card is already generated from " + filepath.Join(dir, "content", "news", "hello", "index.md")} {
+		if !strings.Contains(summary, want) {
+			t.Fatalf("failure is not listed: %q in %q", want, summary)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "hello.png")); err != nil {
+		t.Fatalf("card of the first bundle is not generated: %v", err)
+	}
+}
+
+func TestRunFailsCardsOfSameSlug(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	// the posts in different sections are named after the same slug
+	post := strings.Replace(testPost, "---\n", "---\nslug: hello\n", 1)
+	for _, section := range []string{"news", "post"} {
+		p := filepath.Join(dir, "content", section, section+".md")
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(post), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, later := filepath.Join(dir, "content", "news", "news.md"), filepath.Join(dir, "content", "post", "post.md")
+
+	for _, dryRun := range []bool{true, false} {
+		t.Run(fmt.Sprintf("dryRun=%v", dryRun), func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			outDir := filepath.Join(t.TempDir(), "out")
+			o := &RootCommandOption{
+				files:   []string{filepath.Join(dir, "content")},
+				fontDir: testfont.Dir(t),
+				output:  outDir + "/",
+				tplImg:  tpl,
+				dryRun:  dryRun,
+			}
+			err := o.Run(IOStreams{Out: &out, ErrOut: &errOut}, time.Now())
+			if dryRun {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := map[string]string{first: actionCreate, later: actionFail}
+				for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n")[1:] {
+					fields := strings.Fields(line)
+					if fields[len(fields)-1] != want[fields[0]] {
+						t.Fatalf("unexpected action of %v:\n%s", fields[0], out.String())
+					}
+				}
+			} else if err == nil || err.Error() != "failed to generate 1 twitter cards" {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, summary, _ := strings.Cut(errOut.String(), "Failed contents:\n")
+			if !strings.Contains(summary, later) || !strings.Contains(summary, "from "+first) {
+				t.Fatalf("the later card is not failed: %q", summary)
+			}
+		})
+	}
+}
+
+func TestRunUsesBundleTemplate(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	cover := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(cover, cover.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+	for name, post := range map[string]string{"bundle": testPost, "plain": testPost} {
+		if err := os.MkdirAll(filepath.Join(dir, "post", name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "post", name, "index.md"), []byte(post), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := canvas.SaveAsPNG(filepath.Join(dir, "post", "bundle", "cover.png"), cover); err != nil {
+		t.Fatal(err)
+	}
+	cnf := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cnf, []byte("bundleTemplate: cover.png\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(dir, "out")
+	o := &RootCommandOption{
+		files:   []string{filepath.Join(dir, "post")},
+		fontDir: testfont.Dir(t),
+		output:  outDir + "/",
+		tplImg:  tpl,
+		config:  cnf,
+	}
+	if err := o.Run(IOStreams{Out: io.Discard, ErrOut: io.Discard}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]color.RGBA{"bundle": red, "plain": {0, 0, 0, 0}} {
+		img, err := canvas.LoadFromFile(filepath.Join(outDir, name+".png"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := color.RGBAModel.Convert(img.At(5, 625)); got != want {
+			t.Fatalf("%s: unexpected background: got=%v, want=%v", name, got, want)
+		}
+	}
+}
+
+func TestRunMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	post := filepath.Join(dir, "post.md")
+	if err := os.WriteFile(post, []byte(testPost), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "card.png")
+	var errOut bytes.Buffer
+	o := &RootCommandOption{
+		files:       []string{post},
+		fontDir:     testfont.Dir(t),
+		output:      out,
+		tplImg:      tpl,
+		compression: "best",
+		maxBytes:    100,
+	}
+	if err := o.Run(IOStreams{Out: io.Discard, ErrOut: &errOut}, time.Now()); err == nil {
+		t.Fatal("expected an error for the card larger than maxBytes")
+	}
+	if !strings.Contains(errOut.String(), "encoded image is too large") {
+		t.Fatalf("unexpected error output: %q", errOut.String())
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Fatalf("card larger than maxBytes is written: %v", err)
+	}
+}
+
+func TestRunMissingGlyphs(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	post := filepath.Join(dir, "post.md")
+	if err := os.WriteFile(post, []byte(strings.Replace(testPost, "First post", "最初の投稿", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fontDir := testfont.Dir(t)
+
+	testCases := []struct {
+		desc         string
+		verbose      bool
+		strictGlyphs bool
+		expectOut    string
+		expectErr    bool
+	}{
+		{desc: "Silent", expectOut: ""},
+		{desc: "Verbose", verbose: true, expectOut: "WARN: the font does not have the glyph of U+6700 '最'"},
+		{desc: "Strict", strictGlyphs: true, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			o := &RootCommandOption{
+				files:        []string{post},
+				fontDir:      fontDir,
+				output:       filepath.Join(t.TempDir(), "card.png"),
+				tplImg:       tpl,
+				compression:  "best",
+				verbose:      tc.verbose,
+				strictGlyphs: tc.strictGlyphs,
+			}
+			err := o.Run(IOStreams{Out: &out, ErrOut: &errOut}, time.Now())
+			if tc.expectErr {
+				if err == nil || !strings.Contains(errOut.String(), "does not have the glyph") {
+					t.Fatalf("expected an error for the missing glyphs: %v, %q", err, errOut.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.expectOut == "" && strings.Contains(out.String(), "WARN") {
+				t.Fatalf("unexpected warnings: %q", out.String())
+			}
+			if !strings.Contains(out.String(), tc.expectOut) {
+				t.Fatalf("unexpected output: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestRunSizePreset(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	img := image.NewRGBA(image.Rect(0, 0, 2400, 1260))
+	draw.Draw(img, img.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+	if err := canvas.SaveAsPNG(tpl, img); err != nil {
+		t.Fatal(err)
+	}
+	post := filepath.Join(dir, "post.md")
+	if err := os.WriteFile(post, []byte(testPost), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cnf := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cnf, []byte("size:\n  fillHexColors: [\"#102030\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc         string
+		preset       string
+		tplImg       string
+		expectBounds image.Rectangle
+		expectBg     color.RGBA
+	}{
+		{
+			desc:         "Template is resized to the preset",
+			preset:       "TwitterLarge",
+			tplImg:       tpl,
+			expectBounds: image.Rect(0, 0, 1200, 600),
+			expectBg:     red,
+		},
+		{
+			desc:         "Preset without a template is filled",
+			preset:       "Square",
+			expectBounds: image.Rect(0, 0, 1200, 1200),
+			expectBg:     color.RGBA{0x10, 0x20, 0x30, 0xFF},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "card.png")
+			o := &RootCommandOption{
+				files:   []string{post},
+				fontDir: testfont.Dir(t),
+				output:  out,
+				tplImg:  tc.tplImg,
+				config:  cnf,
+				preset:  tc.preset,
+			}
+			if err := o.Run(IOStreams{Out: io.Discard, ErrOut: io.Discard}, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			img, err := canvas.LoadFromFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.Bounds() != tc.expectBounds {
+				t.Fatalf("unexpected size: got=%v, want=%v", img.Bounds(), tc.expectBounds)
+			}
+			if got := color.RGBAModel.Convert(img.At(5, tc.expectBounds.Max.Y-5)); got != tc.expectBg {
+				t.Fatalf("unexpected background: got=%v, want=%v", got, tc.expectBg)
+			}
+		})
+	}
+}
+
+func TestRunDarkVariant(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	dir := t.TempDir()
+	for name, c := range map[string]color.RGBA{"template.png": red, "dark.png": blue} {
+		img := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+		draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+		if err := canvas.SaveAsPNG(filepath.Join(dir, name), img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	post := filepath.Join(dir, "post.md")
+	if err := os.WriteFile(post, []byte(testPost), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cnf := filepath.Join(dir, "config.yaml")
+	dark := fmt.Sprintf("dark:\n  template: %s\n  titleColor: \"#FFFFFF\"\n", filepath.Join(dir, "dark.png"))
+	if err := os.WriteFile(cnf, []byte(dark), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := t.TempDir()
+	o := &RootCommandOption{
+		files:   []string{post},
+		fontDir: testfont.Dir(t),
+		output:  outDir + string(filepath.Separator),
+		tplImg:  filepath.Join(dir, "template.png"),
+		config:  cnf,
+	}
+	if err := o.Run(IOStreams{Out: io.Discard, ErrOut: io.Discard}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	for name, expectBg := range map[string]color.RGBA{"post.png": red, "post-dark.png": blue} {
+		img, err := canvas.LoadFromFile(filepath.Join(outDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := color.RGBAModel.Convert(img.At(5, 625)); got != expectBg {
+			t.Fatalf("unexpected background of %s: got=%v, want=%v", name, got, expectBg)
+		}
+	}
+
+	// the texts laid out out of the smaller template of the dark variant are rejected
+	if err := canvas.SaveAsPNG(filepath.Join(dir, "dark.png"), image.NewRGBA(image.Rect(0, 0, 600, 315))); err != nil {
+		t.Fatal(err)
+	}
+	err := o.Run(IOStreams{Out: io.Discard, ErrOut: io.Discard}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "invalid configuration of the dark variant") {
+		t.Fatalf("expected an error for the smaller template of the dark variant: %v", err)
+	}
+}
+
+func TestTemplatesForPost(t *testing.T) {
+	dir := t.TempDir()
+	def := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	release := filepath.Join(dir, "templates", "release.png")
+	if err := os.MkdirAll(filepath.Dir(release), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := canvas.SaveAsPNG(release, image.NewRGBA(image.Rect(0, 0, 20, 20))); err != nil {
+		t.Fatal(err)
+	}
+	contentPath := filepath.Join(dir, "post.md")
+	cnf := &config.DrawingConfig{}
+	config.Defaulting(cnf, "")
+
+	tpls := newTemplates(def)
+	got, err := tpls.forPost(&hugo.FrontMatter{}, contentPath, cnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != image.Image(def) {
+		t.Fatal("the default template is not used for the post without override")
+	}
+
+	fm := &hugo.FrontMatter{Template: "templates/release.png"}
+	first, err := tpls.forPost(fm, contentPath, cnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Bounds().Dx() != 20 {
+		t.Fatalf("the template in the front-matter is not used: %v", first.Bounds())
+	}
+	second, err := tpls.forPost(fm, contentPath, cnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("the template shared by posts is decoded again")
+	}
+
+	// the template is resized to the cards once
+	sized := newTemplates(def)
+	sized.size = &config.SizeOption{Width: 10, Height: 10}
+	first, err = sized.forPost(fm, contentPath, cnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Bounds() != image.Rect(0, 0, 10, 10) {
+		t.Fatalf("the template is not resized: %v", first.Bounds())
+	}
+	if second, err = sized.forPost(fm, contentPath, cnf); err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("the template shared by posts is resized again")
+	}
+
+	if _, err := tpls.forPost(&hugo.FrontMatter{Template: "missing.png"}, contentPath, cnf); err == nil {
+		t.Fatal("expected an error for a missing template")
+	}
+}
+
+func TestTemplatesForPostRemote(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 30, 20))); err != nil {
+		t.Fatal(err)
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	def := image.NewRGBA(image.Rect(0, 0, 30, 20))
+	contentPath := filepath.Join(t.TempDir(), "post.md")
+	enabled := true
+	cnf := &config.DrawingConfig{HeroImage: &config.HeroImageOption{Enabled: &enabled}}
+	config.Defaulting(cnf, "")
+
+	tpls := newTemplates(def)
+	if _, err := tpls.forPost(&hugo.FrontMatter{Template: srv.URL + "/template.png"}, contentPath, cnf); err == nil {
+		t.Fatal("expected an error for the template URL in the front-matter")
+	}
+	got, err := tpls.forPost(&hugo.FrontMatter{Image: srv.URL + "/hero.png"}, contentPath, cnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != image.Image(def) || requests != 0 {
+		t.Fatalf("the hero image URL is fetched without being allowed: requests=%d", requests)
+	}
+
+	tpls.allowRemote = true
+	got, err = tpls.forPost(&hugo.FrontMatter{Template: srv.URL + "/template.png"}, contentPath, cnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == image.Image(def) || requests != 1 {
+		t.Fatalf("the allowed template URL is not fetched: requests=%d", requests)
+	}
+}
+
+func TestTemplatesImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 10, 10))); err != nil {
+		t.Fatal(err)
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	tpls := newTemplates(image.NewRGBA(image.Rect(0, 0, 30, 20)))
+	first, err := tpls.image(srv.URL + "/avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := tpls.image(srv.URL + "/avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second || requests != 1 {
+		t.Fatalf("the image shared by the cards is fetched %d times", requests)
+	}
+}
+
+func TestTemplatesForPostHeroImage(t *testing.T) {
+	dir := t.TempDir()
+	def := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	hero := image.NewRGBA(image.Rect(0, 0, 80, 80))
+	draw.Draw(hero, hero.Bounds(), image.NewUniform(color.RGBA{200, 100, 50, 255}), image.Point{}, draw.Src)
+	if err := os.MkdirAll(filepath.Join(dir, "images"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := canvas.SaveAsPNG(filepath.Join(dir, "images", "hero.png"), hero); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "images", "broken.png"), []byte("not an image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	contentPath := filepath.Join(dir, "post.md")
+
+	testCases := []struct {
+		desc      string
+		enabled   bool
+		image     string
+		expectDef bool
+		// expectWarn is warned when the image falls back to the default template
+		expectWarn string
+	}{
+		{desc: "Featured image is resized and darkened", enabled: true, image: "images/hero.png"},
+		{desc: "Default template without an image", enabled: true, expectDef: true},
+		{desc: "Default template for a missing image", enabled: true, image: "images/missing.png", expectDef: true, expectWarn: "images/missing.png"},
+		{desc: "Default template for an image which cannot be loaded", enabled: true, image: "images/broken.png", expectDef: true, expectWarn: "broken.png"},
+		{desc: "Default template unless enabled", image: "images/hero.png", expectDef: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cnf := &config.DrawingConfig{HeroImage: &config.HeroImageOption{Enabled: &tc.enabled}}
+			config.Defaulting(cnf, "")
+			darken := 0.5
+			cnf.HeroImage.Darken = &darken
+
+			var warn strings.Builder
+			tpls := newTemplates(def)
+			tpls.warn = &warn
+			got, err := tpls.forPost(&hugo.FrontMatter{Image: tc.image}, contentPath, cnf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.expectWarn == "" {
+				if warn.Len() > 0 {
+					t.Fatalf("unexpected warning: %q", warn.String())
+				}
+			} else if !strings.HasPrefix(warn.String(), "WARN: featured image ") || !strings.Contains(warn.String(), tc.expectWarn) {
+				t.Fatalf("the fallback is not warned: %q", warn.String())
+			}
+			if tc.expectDef {
+				if got != image.Image(def) {
+					t.Fatal("the default template is not used")
+				}
+				return
+			}
+			if got.Bounds() != def.Bounds() {
+				t.Fatalf("the image is not resized to the card: %v", got.Bounds())
+			}
+			// the black overlay of the opacity 0.5 is quantized to 128/255
+			if c := color.RGBAModel.Convert(got.At(20, 10)); c != (color.RGBA{99, 49, 24, 255}) {
+				t.Fatalf("the image is not darkened: %v", c)
+			}
+		})
+	}
+}
+
+func TestLoadDefaultTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 30, 20))); err != nil {
+		t.Fatal(err)
+	}
+	pngData := buf.Bytes()
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/template.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngData)
+		case "/index.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("URL is fetched once", func(t *testing.T) {
+		o := &RootCommandOption{}
+		for i := 0; i < 2; i++ {
+			img, err := o.loadDefaultTemplate(IOStreams{}, srv.URL+"/template.png")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.Bounds().Dx() != 30 {
+				t.Fatalf("unexpected template: %v", img.Bounds())
+			}
+		}
+		if requests != 1 {
+			t.Fatalf("the template is fetched %d times", requests)
+		}
+	})
+	t.Run("URL of non-image content", func(t *testing.T) {
+		o := &RootCommandOption{}
+		if _, err := o.loadDefaultTemplate(IOStreams{}, srv.URL+"/index.html"); err == nil || !strings.Contains(err.Error(), "text/html") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := o.loadDefaultTemplate(IOStreams{}, srv.URL+"/missing.png"); err == nil {
+			t.Fatal("expected an error for a missing template")
+		}
+	})
+	t.Run("Standard input is read once", func(t *testing.T) {
+		o := &RootCommandOption{}
+		streams := IOStreams{In: bytes.NewReader(pngData)}
+		for i := 0; i < 2; i++ {
+			img, err := o.loadDefaultTemplate(streams, "-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if img.Bounds().Dx() != 30 {
+				t.Fatalf("unexpected template: %v", img.Bounds())
+			}
+		}
+	})
+	t.Run("Standard input of non-image content", func(t *testing.T) {
+		o := &RootCommandOption{}
+		if _, err := o.loadDefaultTemplate(IOStreams{In: strings.NewReader("text")}, "-"); err == nil {
+			t.Fatal("expected an error for a non-image template")
+		}
+	})
+	t.Run("Template larger than the limit", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "template.png")
+		if err := os.WriteFile(file, pngData, 0644); err != nil {
+			t.Fatal(err)
+		}
+		for _, src := range []string{file, srv.URL + "/template.png", "-"} {
+			o := &RootCommandOption{maxPixels: 599}
+			_, err := o.loadDefaultTemplate(IOStreams{In: bytes.NewReader(pngData)}, src)
+			if !errors.Is(err, canvas.ErrImageTooLarge) || !strings.Contains(err.Error(), "--maxPixels") {
+				t.Fatalf("%s: unexpected error: %v", src, err)
+			}
+		}
+	})
+}
+
+func mustGenerateTCard(t *testing.T, ffa *fontfamily.FontFamily, tpl image.Image, cnf *config.DrawingConfig, pps []card.PostProcessor, post string, idx int) *image.RGBA {
+	t.Helper()
+	dir := t.TempDir()
+	in := filepath.Join(dir, "post.md")
+	if err := os.WriteFile(in, []byte(post), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "post.png")
+	streams := IOStreams{Out: io.Discard, ErrOut: io.Discard}
+	fm, err := hugo.ParseFrontMatter(streams.Out, in, time.Now(), card.ParseOptions(cnf)...)
+	if err != nil {
+		t.Fatalf("post #%d: %v", idx, err)
+	}
+	if err := renderTCard(fm, in, out, newTemplates(tpl), ffa, cnf, pps, false, nil, time.Now()); err != nil {
+		t.Fatalf("post #%d: %v", idx, err)
+	}
+	img, err := canvas.LoadFromFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
+	return rgba
+}
+
+func hasInk(img *image.RGBA) bool {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y) != (color.RGBA{255, 255, 255, 255}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameRegion(a, b *image.RGBA) bool {
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if a.RGBAAt(x, y) != b.RGBAAt(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shunk031/tcardgen/pkg/card"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+// The actions planned for the cards in dry-run mode.
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionSkip   = "skip"
+	actionFail   = "fail"
+)
+
+// runDry parses the front-matters of the contents and prints the cards to be generated, and the actions
+// for them, without loading the fonts and the template. Nothing is written. The failures are reported,
+// but do not fail the dry run, which plans the other cards all the same.
+func (o *RootCommandOption) runDry(streams IOStreams, currentTime time.Time) error {
+	cnf, err := o.loadConfig()
+	if err != nil {
+		return err
+	}
+	outDir, outFilename := o.splitOutput(streams)
+	contents, failures, err := o.findContents(streams, outFilename)
+	if err != nil {
+		return err
+	}
+
+	p := &planner{o: o, streams: streams, cnf: cnf, errs: failures}
+	for _, content := range contents {
+		if err := p.planContent(content, outDir, outFilename, currentTime); err != nil {
+			return err
+		}
+	}
+
+	tw := tabwriter.NewWriter(streams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tDESTINATION\tACTION")
+	for _, pl := range p.plans {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", pl.src, pl.out, pl.action)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(p.errs) != 0 {
+		printFailures(streams.ErrOut, p.errs)
+	}
+	return nil
+}
+
+// planner plans the cards of contents in dry-run mode.
+type planner struct {
+	o       *RootCommandOption
+	streams IOStreams
+	cnf     *config.DrawingConfig
+
+	plans []plan
+	errs  []error
+	// sources are the sources of the cards planned by their paths, which detect the cards planned into the
+	// same path from different sources as the run does.
+	sources map[string]string
+}
+
+type plan struct {
+	src    string
+	out    string
+	action string
+}
+
+// planContent plans the cards of the content, which are the cards of all the records for a CSV/TSV file.
+func (p *planner) planContent(content *hugo.Content, outDir, outFilename string, currentTime time.Time) error {
+	f := content.Path
+	if hugo.IsRecordFile(f) {
+		if outFilename != "" {
+			return errors.New("cannot accept a CSV/TSV file when you specify output filename")
+		}
+		records, err := hugo.ReadRecordFile(p.streams.Out, f, p.o.nameColumn, currentTime, card.ParseOptions(p.cnf)...)
+		if err != nil {
+			fmt.Fprintf(p.streams.ErrOut, "Failed to read %v: %v\n", f, err)
+			p.errs = append(p.errs, sourceError(f, err))
+			return nil
+		}
+		for _, rec := range records {
+			p.plan(recordSource(f, rec), f, recordOutput(rec, outDir), rec.FrontMatter, rec.Err)
+		}
+		return nil
+	}
+
+	fm, err := hugo.ParseFrontMatter(p.streams.Out, f, currentTime, card.ParseOptions(p.cnf)...)
+	p.plan(f, f, contentOutput(content, fm, outDir, outFilename), fm, err)
+	return nil
+}
+
+// plan decides the action for the card: drafts are skipped with --skipDrafts, unchanged cards are skipped
+// with --skipUnchanged, and the other existing cards are updated. A failure of parsing is counted and reported.
+// The card of the dark variant is planned besides the card if the variant is configured.
+func (p *planner) plan(src, contentPath, out string, fm *hugo.FrontMatter, err error) {
+	action := actionCreate
+	switch {
+	case err != nil:
+		fmt.Fprintf(p.streams.ErrOut, "Failed to parse %v: %v\n", src, err)
+		p.errs = append(p.errs, sourceError(src, err))
+		action = actionFail
+	case fm.Draft && p.o.skipDrafts:
+		action = actionSkip
+	default:
+		action = p.output(src, out, p.o.cardSources(fm, contentPath, p.cnf))
+	}
+	p.plans = append(p.plans, plan{src: src, out: out, action: action})
+
+	if p.cnf.Dark != nil && action != actionFail && action != actionSkip {
+		dark := variantOutput(out, p.cnf.Dark.Suffix)
+		srcs := p.o.cardSources(fm, contentPath, p.cnf.Variant(p.cnf.Dark))
+		p.plans = append(p.plans, plan{src: src, out: dark, action: p.output(src, dark, srcs)})
+	}
+}
+
+// output decides whether the card is created, updated, or skipped as unchanged by the existing one at out.
+// The card fails if another source has already planned a card into out, as the run does.
+func (p *planner) output(src, out string, srcs []string) string {
+	key := absPath(out)
+	if prev, ok := p.sources[key]; ok && prev != src {
+		err := fmt.Errorf("the card is already planned from %v", prev)
+		fmt.Fprintf(p.streams.ErrOut, "Failed to plan %v: %v\n", out, err)
+		p.errs = append(p.errs, sourceError(src, err))
+		return actionFail
+	}
+	if p.sources == nil {
+		p.sources = map[string]string{}
+	}
+	action := actionCreate
+	_, err := os.Stat(out)
+	switch {
+	case err == nil && p.o.skipUnchanged && p.o.isUnchanged(out, srcs):
+		action = actionSkip
+	case err == nil:
+		action = actionUpdate
+	case !errors.Is(err, fs.ErrNotExist):
+		fmt.Fprintf(p.streams.ErrOut, "Failed to check %v: %v\n", out, err)
+		p.errs = append(p.errs, sourceError(src, err))
+		return actionFail
+	}
+	p.sources[key] = src
+	return action
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shunk031/tcardgen/pkg/card"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+// FrontMatterCommandOption is the options of the frontmatter command.
+type FrontMatterCommandOption struct {
+	config string
+	lang   string
+}
+
+// NewFrontMatterCmd creates the frontmatter command, which prints the front-matter parsed from a content
+// as the cards are drawn with it, to find out why a card is rendered wrong.
+func NewFrontMatterCmd() *cobra.Command {
+	opt := FrontMatterCommandOption{}
+	cmd := &cobra.Command{
+		Use:   "frontmatter [-c <CONFIG>] <FILE>",
+		Short: "Print the front-matter parsed from a content as JSON.",
+		Long: `Print the front-matter parsed from a content as JSON, after the truncation, the limit of the tags,
+and the concatenation of the authors as configured. The date is formatted in RFC3339.`,
+		Example: `# Print the front-matter of a post parsed with the configuration.
+tcardgen frontmatter --config=config.yaml example/blog-post.md`,
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opt.Run(IOStreams{Out: cmd.OutOrStdout(), ErrOut: cmd.ErrOrStderr()}, args[0], time.Now())
+		},
+	}
+	cmd.Flags().StringVarP(&opt.config, "config", "c", "", "Set a drawing configuration file.")
+	cmd.Flags().StringVarP(&opt.lang, "lang", "", "", "Set the language of posts that do not define \"lang\". It selects the line breaking rules.")
+	return cmd
+}
+
+// Run parses the front-matter of the content, and prints it to the output. The warnings are printed to
+// the error output, so that the output is valid JSON.
+func (o *FrontMatterCommandOption) Run(streams IOStreams, filename string, currentTime time.Time) error {
+	cnf := &config.DrawingConfig{}
+	if o.config != "" {
+		var err error
+		if cnf, err = config.LoadConfig(o.config); err != nil {
+			return err
+		}
+	}
+	config.Defaulting(cnf, "")
+	if o.lang != "" {
+		cnf.FrontMatter.DefaultLang = o.lang
+	}
+
+	fm, err := hugo.ParseFrontMatter(streams.ErrOut, filename, currentTime, card.ParseOptions(cnf)...)
+	if err != nil {
+		return err
+	}
+	return printFrontMatter(streams.Out, fm)
+}
+
+// printFrontMatter prints the front-matter as indented JSON with the date in RFC3339.
+func printFrontMatter(w io.Writer, fm *hugo.FrontMatter) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(struct {
+		*hugo.FrontMatter
+		Date string `json:"date"`
+	}{fm, fm.Date.Format(time.RFC3339)})
+}
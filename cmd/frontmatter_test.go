@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+func TestFrontMatterCommand(t *testing.T) {
+	dir := t.TempDir()
+	post := filepath.Join(dir, "post.md")
+	if err := os.WriteFile(post, []byte(`---
+title: "A <b>bold</b> title"
+authors: [alice, bob, carol]
+tags: [go, hugo]
+categories: tech
+date: 2024-05-01T10:00:00+09:00
+---
+The body.
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "broken.md")
+	if err := os.WriteFile(broken, []byte("---\ntitle: \"Title\"\ntags: 1\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"frontmatter", post})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("the output is not JSON: %v\n%s", err, out.String())
+	}
+	want := map[string]any{
+		"title":      "A <b>bold</b> title",
+		"category":   "tech",
+		"categories": []any{"tech"},
+		"tags":       []any{"go", "hugo"},
+		"date":       "2024-05-01T10:00:00+09:00",
+		"wordCount":  float64(2),
+	}
+	for key, v := range want {
+		if !reflect.DeepEqual(got[key], v) {
+			t.Errorf("unexpected %q: got=%v, want=%v", key, got[key], v)
+		}
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"frontmatter", broken})
+	var fe *hugo.FMError
+	if err := cmd.Execute(); !errors.As(err, &fe) || fe.File != broken {
+		t.Fatalf("expected the error of the front-matter, got=%v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+)
+
+// defaultInspectFontSize is the font size of the title by default.
+const defaultInspectFontSize = 72
+
+// InspectFontCommandOption is the options of the inspect-font command.
+type InspectFontCommandOption struct {
+	fontDir  string
+	fontSize float64
+}
+
+// NewInspectFontCmd creates the inspect-font command, which prints the styles of the font family and
+// their metrics to place the texts in the configuration.
+func NewInspectFontCmd() *cobra.Command {
+	opt := InspectFontCommandOption{}
+	cmd := &cobra.Command{
+		Use:   "inspect-font [--fontSize <SIZE>] [<FONTDIR>]",
+		Short: "Print the styles of a font family and their metrics.",
+		Long: `Print the styles of a font family and their metrics(px) in the font size.
+A text drawn at "start" has the baseline of its first line at LINE HEIGHT below it.`,
+		Example: `# Print the metrics of the fonts in the font directory at 72pt.
+tcardgen inspect-font font
+
+# Print the metrics at the font size of the tags.
+tcardgen inspect-font --fontSize=22 font`,
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opt.fontDir = args[0]
+			}
+			return opt.Run(cmd.OutOrStdout())
+		},
+	}
+	opt.fontDir = defaultFontDir
+	cmd.Flags().Float64VarP(&opt.fontSize, "fontSize", "", defaultInspectFontSize, "Set the font size to measure the metrics in.")
+	return cmd
+}
+
+func (o *InspectFontCommandOption) Run(out io.Writer) error {
+	if o.fontSize <= 0 {
+		return fmt.Errorf("font size must be positive: %v", o.fontSize)
+	}
+	ffa, err := fontfamily.LoadFromDir(o.fontDir)
+	if err != nil {
+		return err
+	}
+	styles := ffa.Styles()
+	if len(styles) == 0 {
+		return fmt.Errorf("no fonts are found in %q", o.fontDir)
+	}
+
+	fmt.Fprintf(out, "Font family %q at %vpt\n\n", ffa.Name, o.fontSize)
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STYLE\tASCENT\tDESCENT\tLINE HEIGHT\tCAP HEIGHT\tX HEIGHT")
+	for _, style := range styles {
+		m, err := ffa.Metrics(style, o.fontSize)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\n", style, m.Ascent, m.Descent, m.LineHeight, m.CapHeight, m.XHeight)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+)
+
+func TestInspectFont(t *testing.T) {
+	var out bytes.Buffer
+	cmd := NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"inspect-font", "--fontSize=22", testfont.Dir(t)})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var styles []string
+	for _, line := range lines[3:] {
+		fields := strings.Fields(line)
+		if len(fields) != 6 {
+			t.Fatalf("unexpected row: %q\n%s", line, out.String())
+		}
+		styles = append(styles, fields[0])
+	}
+	if want := []string{fontfamily.Regular, fontfamily.Medium, fontfamily.Bold}; strings.Join(styles, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected styles: got=%v, want=%v\n%s", styles, want, out.String())
+	}
+	if !strings.Contains(lines[0], "22pt") {
+		t.Fatalf("the font size is not printed: %q", lines[0])
+	}
+}
+
+func TestInspectFontRejectsInvalidSize(t *testing.T) {
+	o := &InspectFontCommandOption{fontDir: testfont.Dir(t), fontSize: 0}
+	if err := o.Run(&bytes.Buffer{}); err == nil {
+		t.Fatal("an error is expected for the font size 0")
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/card"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+// runLint lays out the cards of the contents without generating them, and prints the texts which do not fit
+// in their regions per field and post. Nothing is written.
+func (o *RootCommandOption) runLint(streams IOStreams, currentTime time.Time) error {
+	ffa, err := fontfamily.LoadFromDir(o.fontDir)
+	if err != nil {
+		return err
+	}
+	cnf, tpls, err := o.loadDrawing(streams, ffa)
+	if err != nil {
+		return err
+	}
+	_, outFilename := o.splitOutput(streams)
+	contents, failures, err := o.findContents(streams, outFilename)
+	if err != nil {
+		return err
+	}
+
+	l := &linter{o: o, streams: streams, ffa: ffa, cnf: cnf, tpls: tpls, errs: failures}
+	for _, content := range contents {
+		if err := l.lintContent(content, currentTime); err != nil {
+			return err
+		}
+	}
+
+	if len(l.findings) == 0 {
+		fmt.Fprintf(streams.Out, "All the texts of %d twitter cards fit\n", l.posts)
+	} else {
+		tw := tabwriter.NewWriter(streams.Out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SOURCE\tFIELD\tPROBLEM")
+		for _, f := range l.findings {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", f.src, f.Field, f.Message)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(l.errs) != 0 {
+		printFailures(streams.ErrOut, l.errs)
+		return fmt.Errorf("failed to lint %d twitter cards", len(l.errs))
+	}
+	if len(l.findings) != 0 {
+		return fmt.Errorf("found %d texts which do not fit in their regions", len(l.findings))
+	}
+	return nil
+}
+
+// linter lints the cards of contents with the loaded fonts, configuration, and templates.
+type linter struct {
+	o       *RootCommandOption
+	streams IOStreams
+	ffa     *fontfamily.FontFamily
+	cnf     *config.DrawingConfig
+	tpls    *templates
+
+	// posts is the number of the linted cards.
+	posts    int
+	findings []finding
+	errs     []error
+}
+
+// finding is a problem of the card of the source.
+type finding struct {
+	src string
+	card.Problem
+}
+
+// lintContent lints the cards of the content, which are the cards of all the records for a CSV/TSV file.
+func (l *linter) lintContent(content *hugo.Content, currentTime time.Time) error {
+	f := content.Path
+	if hugo.IsRecordFile(f) {
+		records, err := hugo.ReadRecordFile(l.streams.Out, f, l.o.nameColumn, currentTime, card.ParseOptions(l.cnf)...)
+		if err != nil {
+			fmt.Fprintf(l.streams.ErrOut, "Failed to read %v: %v\n", f, err)
+			l.errs = append(l.errs, sourceError(f, err))
+			return nil
+		}
+		for _, rec := range records {
+			l.lint(recordSource(f, rec), f, rec.FrontMatter, rec.Err, currentTime)
+		}
+		return nil
+	}
+
+	fm, err := hugo.ParseFrontMatter(l.streams.Out, f, currentTime, card.ParseOptions(l.cnf)...)
+	l.lint(f, f, fm, err, currentTime)
+	return nil
+}
+
+// lint lints the card of the front-matter on its template. Drafts are skipped with --skipDrafts,
+// and a failure is counted and reported.
+func (l *linter) lint(src, contentPath string, fm *hugo.FrontMatter, err error, currentTime time.Time) {
+	if err == nil {
+		if fm.Draft && l.o.skipDrafts {
+			return
+		}
+		err = l.lintCard(src, contentPath, fm, currentTime)
+	}
+	if err != nil {
+		fmt.Fprintf(l.streams.ErrOut, "Failed to lint %v: %v\n", src, err)
+		l.errs = append(l.errs, sourceError(src, err))
+	}
+}
+
+// lintCard lints the card of the front-matter, and collects its problems.
+func (l *linter) lintCard(src, contentPath string, fm *hugo.FrontMatter, currentTime time.Time) error {
+	tpl, err := l.tpls.forPost(fm, contentPath, l.cnf)
+	if err != nil {
+		return err
+	}
+	problems, err := card.Lint(card.Config{Drawing: l.cnf, Fonts: l.ffa, Template: tpl}, fm, card.ContentPath(contentPath), card.Now(currentTime))
+	if err != nil {
+		return err
+	}
+	l.posts++
+	for _, p := range problems {
+		l.findings = append(l.findings, finding{src: src, Problem: p})
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas"
+)
+
+func TestRunLint(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	long := strings.Replace(testPost, "First post", strings.Repeat("A very long title ", 30), 1)
+	for name, post := range map[string]string{"fits.md": testPost, "long.md": long} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(post), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		desc        string
+		files       []string
+		expectErr   bool
+		expectLines []string
+	}{
+		{
+			desc:        "Texts which fit are not reported",
+			files:       []string{filepath.Join(dir, "fits.md")},
+			expectErr:   false,
+			expectLines: []string{"All the texts of 1 twitter cards fit"},
+		},
+		{
+			desc:        "Overflowing title is reported per post",
+			files:       []string{dir},
+			expectErr:   true,
+			expectLines: []string{"SOURCE", "long.md  title  wraps into 4 lines, more than maxLines 3"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var out bytes.Buffer
+			o := &RootCommandOption{
+				files:   tc.files,
+				fontDir: testfont.Dir(t),
+				output:  filepath.Join(dir, "out") + "/",
+				tplImg:  tpl,
+				lint:    true,
+			}
+			err := o.Run(IOStreams{Out: &out, ErrOut: io.Discard}, time.Now())
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, l := range tc.expectLines {
+				if !strings.Contains(out.String(), l) {
+					t.Fatalf("output does not contain %q:\n%s", l, out.String())
+				}
+			}
+			if strings.Contains(out.String(), "fits.md") {
+				t.Fatalf("the post which fits is reported:\n%s", out.String())
+			}
+			if _, err := os.Stat(filepath.Join(dir, "out")); !os.IsNotExist(err) {
+				t.Fatalf("cards are written in lint mode: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/card"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+const (
+	// stdinTemplate is the template which is read from the standard input.
+	stdinTemplate = "-"
+	// templateFetchTimeout is the timeout to fetch a template from a URL.
+	templateFetchTimeout = 30 * time.Second
+)
+
+// templateContentTypes are the content types of the templates which can be fetched.
+var templateContentTypes = []string{"image/png", "image/jpeg"}
+
+// templates provides the template image of each card. The images other than the default one are
+// decoded on demand and cached, so that the posts sharing a template decode it only once.
+type templates struct {
+	def   image.Image
+	cache map[string]image.Image
+	opts  []canvas.LoadOption
+
+	// images caches the images drawn on the cards, such as the avatars, so that each of them is loaded once.
+	images map[string]image.Image
+
+	// size is the size of the cards, to which the templates are resized once and cached in resized.
+	size    *config.SizeOption
+	resized map[string]image.Image
+
+	// dark is the default template of the dark variant, or nil if it uses the default one.
+	dark image.Image
+	// warn is where the featured images falling back to the default template are warned.
+	warn io.Writer
+	// allowRemote allows the front-matter of the posts to fetch their templates and hero images from URLs.
+	// Otherwise only the default templates are fetched, so that the contents cannot make any requests.
+	allowRemote bool
+}
+
+func newTemplates(def image.Image, opts ...canvas.LoadOption) *templates {
+	return &templates{
+		def:     def,
+		cache:   map[string]image.Image{},
+		resized: map[string]image.Image{},
+		images:  map[string]image.Image{},
+		opts:    opts,
+		warn:    io.Discard,
+	}
+}
+
+// darkVariant returns the templates of the dark variant, whose default template is replaced with its own.
+// The loaded templates are shared.
+func (ts *templates) darkVariant() *templates {
+	if ts.dark == nil {
+		return ts
+	}
+	nt := *ts
+	nt.def, nt.dark = ts.dark, nil
+	return &nt
+}
+
+// forPost returns the template of the post in this order: the one in the front-matter, the bundle
+// template in the page bundle, the featured image of the post if the hero image is enabled, and the default one.
+// The template in the front-matter is resolved from the content directory, then the working directory,
+// or a URL if the remote templates are allowed.
+func (ts *templates) forPost(fm *hugo.FrontMatter, contentPath string, cnf *config.DrawingConfig) (image.Image, error) {
+	if isRemote(fm.Template) {
+		if !ts.allowRemote {
+			return nil, fmt.Errorf("template %s in the front-matter is not fetched without --allowRemoteImages", fm.Template)
+		}
+		return ts.template(fm.Template)
+	}
+	if fm.Template != "" {
+		p, err := hugo.NewContent(contentPath).Resource(fm.Template)
+		if err != nil {
+			if _, serr := os.Stat(fm.Template); filepath.IsAbs(fm.Template) || serr != nil {
+				return nil, fmt.Errorf("failed to find template: %w", err)
+			}
+			p = fm.Template
+		}
+		return ts.template(p)
+	}
+	if p, ok := hugo.NewContent(contentPath).BundleResource(cnf.BundleTemplate); ok {
+		return ts.template(p)
+	}
+	if *cnf.HeroImage.Enabled && fm.Image != "" {
+		return ts.hero(fm.Image, contentPath, cnf)
+	}
+	return ts.def, nil
+}
+
+// hero returns the featured image of the post resized to the card and darkened, which is resolved from the
+// content directory, or a URL if the remote templates are allowed. The default template is returned if the
+// image is not allowed, or cannot be found or loaded, wherever it is, and the failure is warned.
+func (ts *templates) hero(src, contentPath string, cnf *config.DrawingConfig) (image.Image, error) {
+	if isRemote(src) && !ts.allowRemote {
+		return ts.def, nil
+	}
+	if !isRemote(src) {
+		p, err := hugo.NewContent(contentPath).Resource(src)
+		if err != nil {
+			return ts.fallback(src, err), nil
+		}
+		src = p
+	}
+	img, err := ts.load(src)
+	if err != nil {
+		return ts.fallback(src, err), nil
+	}
+	return card.HeroBackground(img, card.Bounds(ts.def, cnf.Size), *cnf.HeroImage.Darken)
+}
+
+// fallback warns the error of the featured image and returns the default template instead.
+func (ts *templates) fallback(src string, err error) image.Image {
+	fmt.Fprintf(ts.warn, "WARN: featured image %s is not used, falling back to the default template: %v\n", src, err)
+	return ts.def
+}
+
+// template returns the template loaded from the path and resized to the cards.
+func (ts *templates) template(path string) (image.Image, error) {
+	if img, ok := ts.resized[path]; ok {
+		return img, nil
+	}
+	img, err := ts.load(path)
+	if err != nil {
+		return nil, err
+	}
+	if img, err = card.Resize(img, ts.size); err != nil {
+		return nil, fmt.Errorf("failed to resize template: %w", err)
+	}
+	ts.resized[path] = img
+	return img, nil
+}
+
+func (ts *templates) load(path string) (image.Image, error) {
+	if img, ok := ts.cache[path]; ok {
+		return img, nil
+	}
+	img, err := loadTemplate(path, ts.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+	ts.cache[path] = img
+	return img, nil
+}
+
+// image returns the image drawn on the cards loaded from the path or URL, which is loaded once and cached.
+func (ts *templates) image(src string) (image.Image, error) {
+	if img, ok := ts.images[src]; ok {
+		return img, nil
+	}
+	img, err := canvas.LoadImage(src)
+	if err != nil {
+		return nil, err
+	}
+	ts.images[src] = img
+	return img, nil
+}
+
+// loadTemplate loads the template from a file or an HTTP(S) URL.
+func loadTemplate(src string, opts ...canvas.LoadOption) (image.Image, error) {
+	if isRemote(src) {
+		return fetchTemplate(src, opts...)
+	}
+	return canvas.LoadFromFile(src, opts...)
+}
+
+// fetchTemplate fetches the template from the URL. It fails unless the response is a PNG or JPEG image.
+func fetchTemplate(url string, opts ...canvas.LoadOption) (image.Image, error) {
+	client := &http.Client{Timeout: templateFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", url, resp.Status)
+	}
+	ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !slices.Contains(templateContentTypes, ct) {
+		return nil, fmt.Errorf("%s is not a PNG or JPEG image: the content type is %q", url, ct)
+	}
+	return decodeTemplate(resp.Body, opts...)
+}
+
+// decodeTemplate decodes the PNG or JPEG template.
+func decodeTemplate(r io.Reader, opts ...canvas.LoadOption) (image.Image, error) {
+	img, format, err := canvas.Decode(r, opts...)
+	if errors.Is(err, canvas.ErrImageTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("template is not a PNG or JPEG image: %w", err)
+	}
+	if format != "png" && format != "jpeg" {
+		return nil, fmt.Errorf("template is not a PNG or JPEG image: %s", format)
+	}
+	return img, nil
+}
+
+// isRemote reports whether the template is fetched from a URL.
+func isRemote(src string) bool {
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+// watchDebounce is the quiet period after the last change before regenerating,
+// so that a burst of writes by an editor triggers a single regeneration.
+const watchDebounce = 200 * time.Millisecond
+
+// watch regenerates the cards of the changed contents until the context is done.
+// A change of the template or the configuration reloads them and regenerates all the cards.
+// The fonts are loaded once and reused across the regenerations.
+func (r *runner) watch(ctx context.Context) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := r.addWatches(w); err != nil {
+		return err
+	}
+	fmt.Fprintln(r.streams.Out, "Watching for changes. Press Ctrl+C to stop.")
+
+	changed := map[string]bool{}
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(r.streams.Out, "Stop watching")
+			return nil
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(r.streams.ErrOut, "Failed to watch: %v\n", err)
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) {
+				continue
+			}
+			if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() {
+				if err := addWatchDirs(w, ev.Name); err != nil {
+					fmt.Fprintf(r.streams.ErrOut, "Failed to watch: %v\n", err)
+				}
+				continue
+			}
+			p := absPath(ev.Name)
+			if r.isOutput(p) {
+				continue
+			}
+			changed[p] = true
+			debounce = time.After(watchDebounce)
+		case <-debounce:
+			r.regenerate(changed)
+			changed = map[string]bool{}
+			debounce = nil
+		}
+	}
+}
+
+// regenerate generates the cards of the contents affected by the changed files.
+func (r *runner) regenerate(changed map[string]bool) {
+	start := time.Now()
+	// the cards may be renamed since the last generation, e.g. by changing their slugs
+	r.errs, r.cards, r.sources = nil, nil, map[string]string{}
+
+	all := r.isDrawingFile(changed)
+	if all {
+		cnf, tpls, err := r.o.loadDrawing(r.streams, r.ffa)
+		if err != nil {
+			fmt.Fprintf(r.streams.ErrOut, "Failed to reload: %v\n", err)
+			return
+		}
+		r.cnf, r.tpls = cnf, tpls
+	}
+
+	contents, failures, err := r.o.findContents(r.streams, r.outFilename)
+	if err != nil {
+		fmt.Fprintf(r.streams.ErrOut, "Failed to find contents: %v\n", err)
+		return
+	}
+	r.errs = failures
+	var n int
+	for _, content := range contents {
+		if !all && !isAffected(content, changed) {
+			continue
+		}
+		if err := r.generateContent(content, time.Now()); err != nil {
+			fmt.Fprintf(r.streams.ErrOut, "Failed to generate twitter card for %v: %v\n", content.Path, err)
+		}
+		n++
+	}
+	if n > 0 {
+		fmt.Fprintf(r.streams.Out, "Regenerate %d contents in %v\n", n, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// addWatches watches the content directories recursively, and the directories of the content files,
+// the local templates, and the configuration.
+func (r *runner) addWatches(w *fsnotify.Watcher) error {
+	var dirs []string
+	for _, tpl := range r.localTemplates() {
+		dirs = append(dirs, filepath.Dir(tpl))
+	}
+	if r.o.config != "" {
+		dirs = append(dirs, filepath.Dir(r.o.config))
+	}
+	for _, f := range r.o.files {
+		if fi, err := os.Stat(f); err == nil && fi.IsDir() {
+			if err := addWatchDirs(w, f); err != nil {
+				return err
+			}
+			continue
+		}
+		dirs = append(dirs, filepath.Dir(f))
+	}
+	for _, dir := range dirs {
+		if err := w.Add(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localTemplates returns the default templates read from files: the one of the cards and the one of
+// the dark variant.
+func (r *runner) localTemplates() []string {
+	var tpls []string
+	for _, tpl := range []string{r.cnf.Template, r.darkTemplate()} {
+		if tpl != "" && tpl != stdinTemplate && !isRemote(tpl) {
+			tpls = append(tpls, tpl)
+		}
+	}
+	return tpls
+}
+
+// darkTemplate returns the default template of the dark variant, or "" if it uses the default one.
+func (r *runner) darkTemplate() string {
+	if r.cnf.Dark == nil {
+		return ""
+	}
+	return r.cnf.Dark.Template
+}
+
+// isDrawingFile reports whether the configuration, any of the templates, or any of the cached images is
+// changed, all of which are reloaded then.
+func (r *runner) isDrawingFile(changed map[string]bool) bool {
+	if r.o.config != "" && changed[absPath(r.o.config)] {
+		return true
+	}
+	for _, tpl := range r.localTemplates() {
+		if changed[absPath(tpl)] {
+			return true
+		}
+	}
+	for _, cache := range []map[string]image.Image{r.tpls.cache, r.tpls.images} {
+		for p := range cache {
+			if changed[absPath(p)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isOutput reports whether the file is a card written to the output directory, which must not
+// trigger another regeneration.
+func (r *runner) isOutput(p string) bool {
+	return filepath.Ext(p) == ".png" && filepath.Dir(p) == absPath(r.outDir)
+}
+
+// isAffected reports whether the content file or a resource in its page bundle is changed.
+func isAffected(content *hugo.Content, changed map[string]bool) bool {
+	if changed[absPath(content.Path)] {
+		return true
+	}
+	if content.Bundle == "" {
+		return false
+	}
+	bundle := absPath(content.Bundle) + string(filepath.Separator)
+	for p := range changed {
+		if strings.HasPrefix(p, bundle) {
+			return true
+		}
+	}
+	return false
+}
+
+// addWatchDirs watches the directory and all its subdirectories.
+func addWatchDirs(w *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		return w.Add(path)
+	})
+}
+
+func absPath(p string) string {
+	if a, err := filepath.Abs(p); err == nil {
+		return a
+	}
+	return filepath.Clean(p)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestWatchRegeneratesChangedContent(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	content := filepath.Join(dir, "post")
+	for _, name := range []string{"first", "second"} {
+		if err := os.MkdirAll(content, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(content, name+".md"), []byte(testPost), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outDir := filepath.Join(dir, "out")
+	o := &RootCommandOption{
+		files:   []string{content},
+		fontDir: testfont.Dir(t),
+		output:  outDir + "/",
+		tplImg:  tpl,
+	}
+	if err := o.Run(IOStreams{Out: io.Discard, ErrOut: io.Discard}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	modTime := func(name string) time.Time {
+		fi, err := os.Stat(filepath.Join(outDir, name+".png"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi.ModTime()
+	}
+	first, second := modTime("first"), modTime("second")
+
+	ffa := testfont.New(t)
+	cnf, tpls, err := o.loadDrawing(IOStreams{Out: io.Discard, ErrOut: io.Discard}, ffa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &runner{o: o, streams: IOStreams{Out: io.Discard, ErrOut: io.Discard}, ffa: ffa, cnf: cnf, tpls: tpls, outDir: outDir + "/"}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- r.watch(ctx)
+	}()
+
+	// keep editing until the watcher picks up the change
+	deadline := time.Now().Add(5 * time.Second)
+	for modTime("first").Equal(first) {
+		if time.Now().After(deadline) {
+			t.Fatal("the changed content is not regenerated")
+		}
+		if err := os.WriteFile(filepath.Join(content, "first.md"), []byte(testPost), 0644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * watchDebounce)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !modTime("second").Equal(second) {
+		t.Fatal("the content which is not changed is regenerated")
+	}
+}
+
+func TestRegenerateForgetsPreviousCards(t *testing.T) {
+	dir := t.TempDir()
+	tpl := filepath.Join(dir, "template.png")
+	if err := canvas.SaveAsPNG(tpl, image.NewRGBA(image.Rect(0, 0, 1200, 630))); err != nil {
+		t.Fatal(err)
+	}
+	post := filepath.Join(dir, "post", "hello.md")
+	if err := os.MkdirAll(filepath.Dir(post), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(post, []byte(testPost), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(dir, "out")
+	if err := os.Mkdir(outDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	o := &RootCommandOption{files: []string{filepath.Dir(post)}, output: outDir + "/", tplImg: tpl}
+	ffa := testfont.New(t)
+	cnf, tpls, err := o.loadDrawing(IOStreams{Out: io.Discard, ErrOut: io.Discard}, ffa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &runner{o: o, streams: IOStreams{Out: io.Discard, ErrOut: io.Discard}, ffa: ffa, cnf: cnf, tpls: tpls, outDir: outDir + "/"}
+	// the card was generated from another post, which is renamed to this one since
+	r.sources = map[string]string{absPath(filepath.Join(outDir, "hello.png")): filepath.Join(dir, "post", "old.md")}
+
+	r.regenerate(map[string]bool{absPath(post): true})
+	if len(r.errs) != 0 || len(r.cards) != 1 {
+		t.Fatalf("the card of the renamed post is not generated: errs=%v, cards=%v", r.errs, r.cards)
+	}
+}
+
+func TestIsDrawingFile(t *testing.T) {
+	dir := t.TempDir()
+	tpl, dark, cnf := filepath.Join(dir, "template.png"), filepath.Join(dir, "dark.png"), filepath.Join(dir, "config.yaml")
+	r := &runner{
+		o:    &RootCommandOption{config: cnf},
+		cnf:  &config.DrawingConfig{Template: tpl, Dark: &config.VariantOption{Template: dark}},
+		tpls: newTemplates(image.NewRGBA(image.Rect(0, 0, 10, 10))),
+	}
+	r.tpls.cache[filepath.Join(dir, "release.png")] = image.NewRGBA(image.Rect(0, 0, 10, 10))
+
+	testCases := []struct {
+		desc    string
+		changed string
+		expect  bool
+	}{
+		{desc: "Configuration", changed: cnf, expect: true},
+		{desc: "Default template", changed: tpl, expect: true},
+		{desc: "Default template of the dark variant", changed: dark, expect: true},
+		{desc: "Template in the front-matter", changed: filepath.Join(dir, "release.png"), expect: true},
+		{desc: "Content", changed: filepath.Join(dir, "post.md"), expect: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := r.isDrawingFile(map[string]bool{absPath(tc.changed): true}); got != tc.expect {
+				t.Fatalf("isDrawingFile() = %v, want=%v", got, tc.expect)
+			}
+		})
+	}
+}
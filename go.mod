@@ -5,12 +5,15 @@ go 1.23.0
 toolchain go1.24.0
 
 require (
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/ghodss/yaml v1.0.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/gohugoio/hugo v0.140.1
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
-	github.com/mattn/go-runewidth v0.0.9
+	github.com/rivo/uniseg v0.4.7
 	github.com/spf13/cobra v1.8.1
 	golang.org/x/image v0.23.0
+	golang.org/x/text v0.23.0
 )
 
 require (
@@ -30,7 +33,6 @@ require (
 	github.com/yuin/goldmark v1.7.8 // indirect
 	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
-	golang.org/x/text v0.23.0 // indirect
 	google.golang.org/protobuf v1.35.2 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
@@ -0,0 +1,44 @@
+// Package testfont provides the Go fonts as a font family for the tests.
+package testfont
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomedium"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+)
+
+// Dir writes the regular, medium and bold Go fonts into a temporary directory of the test, and returns it.
+// The directory is named "Go", which is the family name of the fonts loaded from it.
+func Dir(t testing.TB) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "Go")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for style, ttf := range map[string][]byte{
+		fontfamily.Regular: goregular.TTF,
+		fontfamily.Medium:  gomedium.TTF,
+		fontfamily.Bold:    gobold.TTF,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "Go-"+style+fontfamily.TrueTypeFontExt), ttf, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// New returns the font family of the Go fonts written by Dir.
+func New(t testing.TB) *fontfamily.FontFamily {
+	t.Helper()
+	ffa, err := fontfamily.LoadFromDir(Dir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ffa
+}
@@ -0,0 +1,93 @@
+package canvas
+
+import (
+	"image"
+	"slices"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+// Block is a text block drawn on a canvas with its own options and position. As with the draw methods of
+// the canvas, the options are applied only while the block is drawn (e.g. MaxWidth of the title does not
+// wrap a subtitle drawn without it), and the blocks can be laid out below each other.
+//
+//	title := c.Block(FontFace(face), MaxWidth(900)).At(config.Point{X: 120, Y: 110})
+//	if err := title.DrawText(fm.Title); err != nil {
+//		return err
+//	}
+//	err := c.Block(FontFace(small)).Below(title, 20).DrawText(fm.Description)
+type Block struct {
+	c      *Canvas
+	opts   []TextDrawOption
+	start  config.Point
+	bottom int
+}
+
+// Block returns a text block drawn on this canvas with the options at the origin.
+func (c *Canvas) Block(opts ...TextDrawOption) *Block {
+	return &Block{c: c, opts: opts}
+}
+
+// With returns a copy of the block with the options added, which take precedence over the ones of the block.
+func (b *Block) With(opts ...TextDrawOption) *Block {
+	nb := *b
+	nb.opts = append(slices.Clip(b.opts), opts...)
+	return &nb
+}
+
+// At returns a copy of the block to be drawn at the point, which is the start point of DrawTextAtPoint
+// and DrawBoxTexts.
+func (b *Block) At(start config.Point) *Block {
+	nb := *b
+	nb.start = start
+	nb.bottom = start.Y
+	return &nb
+}
+
+// Below returns a copy of the block to be drawn the gap(px) below the bottom of the last draw of prev,
+// starting from the same x as prev.
+func (b *Block) Below(prev *Block, gap int) *Block {
+	return b.At(config.Point{X: prev.start.X, Y: prev.bottom + gap})
+}
+
+// Bottom returns the y(px) of the bottom of the last draw of the block, or the start if it is not drawn yet.
+func (b *Block) Bottom() int {
+	return b.bottom
+}
+
+// DrawText draws the text as DrawTextAtPoint does at the start point of the block.
+func (b *Block) DrawText(text string) error {
+	if err := b.c.DrawTextAtPoint(text, b.start, b.opts...); err != nil {
+		return err
+	}
+	d, err := b.c.TextLayout(text, b.start, b.opts...)
+	if err != nil {
+		return err
+	}
+	b.bottom = d.Bottom
+	return nil
+}
+
+// DrawBoxTexts draws the texts in boxes as DrawBoxTexts of the canvas does at the start point of the block.
+func (b *Block) DrawBoxTexts(texts []string) error {
+	if err := b.c.DrawBoxTexts(texts, b.start, b.opts...); err != nil {
+		return err
+	}
+	bounds, _, err := b.c.BoxTextsBounds(texts, b.start, b.opts...)
+	if err != nil {
+		return err
+	}
+	b.bottom = bounds.Max.Y
+	return nil
+}
+
+// DrawTextInBox draws the text as DrawTextInBox of the canvas does in the region, regardless of the start point
+// of the block. The bottom of the block is the bottom of the region.
+func (b *Block) DrawTextInBox(text string, region image.Rectangle) error {
+	if err := b.c.DrawTextInBox(text, region, b.opts...); err != nil {
+		return err
+	}
+	b.start = config.Point{X: region.Min.X, Y: region.Min.Y}
+	b.bottom = region.Max.Y
+	return nil
+}
@@ -0,0 +1,68 @@
+package canvas
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestBlockDoesNotLeakOptions(t *testing.T) {
+	face := newTestFace(t, 22)
+	c := newTestCanvas(t, 400, 200)
+	title := c.Block(FontFace(face), FgColor(image.NewUniform(black)), MaxWidth(60), LineSpacing(10)).At(config.Point{X: 10, Y: 10})
+	if err := title.DrawText("a title wrapped in lines"); err != nil {
+		t.Fatal(err)
+	}
+	if c.maxWidth != 0 || c.lineSpace != 0 || c.fdr.Face != nil {
+		t.Fatalf("draw state leaks from the block: maxWidth=%d, lineSpace=%d, face=%v", c.maxWidth, c.lineSpace, c.fdr.Face)
+	}
+
+	_, _, lines, err := c.MeasureText("a subtitle in a line", FontFace(face))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("subtitle is wrapped by the width of the title: %q", lines)
+	}
+
+	t.Run("State is restored on errors", func(t *testing.T) {
+		errOpt := errors.New("invalid option")
+		err := c.Block(MaxWidth(60), func(*Canvas) error { return errOpt }).DrawText("text")
+		if !errors.Is(err, errOpt) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.maxWidth != 0 {
+			t.Fatalf("draw state leaks from the failed block: maxWidth=%d", c.maxWidth)
+		}
+	})
+}
+
+func TestBlockBelow(t *testing.T) {
+	face := newTestFace(t, 22)
+	c := newTestCanvas(t, 400, 300)
+	title := c.Block(FontFace(face), MaxWidth(60)).At(config.Point{X: 10, Y: 10})
+	if err := title.DrawText("a title wrapped in lines"); err != nil {
+		t.Fatal(err)
+	}
+	_, h, _, err := c.MeasureText("a title wrapped in lines", FontFace(face), MaxWidth(60))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := title.Bottom(), 10+h; got != want {
+		t.Fatalf("unexpected bottom of the title: got=%d, want=%d", got, want)
+	}
+
+	tags := c.Block(FontFace(face), BgColor(image.NewUniform(black)), BoxPadding(config.Padding{Top: 4, Bottom: 4})).Below(title, 8)
+	if err := tags.DrawBoxTexts([]string{"go", "hugo"}); err != nil {
+		t.Fatal(err)
+	}
+	if tags.start != (config.Point{X: 10, Y: 10 + h + 8}) {
+		t.Fatalf("tags are not placed below the title: %v", tags.start)
+	}
+	m := face.Metrics()
+	if got, want := tags.Bottom(), tags.start.Y+4+m.Height.Round()+m.Descent.Round()+4; got != want {
+		t.Fatalf("unexpected bottom of the tags: got=%d, want=%d", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package canvas
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/vector"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+// DrawTopBorder fills a full-width bar of the thickness(px) at the top of this canvas.
+func (c *Canvas) DrawTopBorder(color *image.Uniform, thickness int) error {
+	if thickness < 0 {
+		return fmt.Errorf("border thickness must not be negative: %d", thickness)
+	}
+	b := c.dst.Bounds()
+	r := image.Rect(b.Min.X, b.Min.Y, b.Max.X, min(b.Min.Y+thickness, b.Max.Y))
+	draw.Draw(c.dst, r, color, image.Point{}, draw.Src)
+	return nil
+}
+
+// DrawTopBorderPercent fills a full-width bar at the top of this canvas.
+// The thickness is a percentage of the canvas height, so that it scales with the canvas.
+func (c *Canvas) DrawTopBorderPercent(color *image.Uniform, percent float64) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("border thickness percentage must be between 0 and 100: %v", percent)
+	}
+	return c.DrawTopBorder(color, int(float64(c.dst.Bounds().Dy())*percent/100+0.5))
+}
+
+// DrawRule draws a straight rule of the thickness(px) from the point to the other point, e.g. a divider
+// between the title and the metadata row. The rule is centered on the line between the points.
+// A horizontal or vertical rule is aligned to the pixels, so that it is sharp in any thickness, and the
+// other rules are anti-aliased.
+func (c *Canvas) DrawRule(from, to config.Point, color *image.Uniform, thickness int) error {
+	if thickness <= 0 {
+		return fmt.Errorf("rule thickness must be positive: %d", thickness)
+	}
+	switch {
+	case from.Y == to.Y:
+		top := from.Y - thickness/2
+		r := image.Rect(from.X, top, to.X, top+thickness)
+		draw.Draw(c.dst, r, color, image.Point{}, draw.Over)
+	case from.X == to.X:
+		left := from.X - thickness/2
+		r := image.Rect(left, from.Y, left+thickness, to.Y)
+		draw.Draw(c.dst, r, color, image.Point{}, draw.Over)
+	default:
+		c.drawSlantedRule(from, to, color, float64(thickness))
+	}
+	return nil
+}
+
+// drawSlantedRule draws the anti-aliased rule as a quadrilateral around the line between the points.
+func (c *Canvas) drawSlantedRule(from, to config.Point, color *image.Uniform, thickness float64) {
+	dx, dy := float64(to.X-from.X), float64(to.Y-from.Y)
+	l := math.Hypot(dx, dy)
+	// the normal of the line of the half of the thickness
+	nx, ny := -dy/l*thickness/2, dx/l*thickness/2
+	xs := []float64{float64(from.X) + nx, float64(to.X) + nx, float64(to.X) - nx, float64(from.X) - nx}
+	ys := []float64{float64(from.Y) + ny, float64(to.Y) + ny, float64(to.Y) - ny, float64(from.Y) - ny}
+
+	bounds := image.Rect(
+		int(math.Floor(min(xs[0], xs[1], xs[2], xs[3]))), int(math.Floor(min(ys[0], ys[1], ys[2], ys[3]))),
+		int(math.Ceil(max(xs[0], xs[1], xs[2], xs[3]))), int(math.Ceil(max(ys[0], ys[1], ys[2], ys[3]))),
+	)
+	z := vector.NewRasterizer(bounds.Dx(), bounds.Dy())
+	for i := range xs {
+		x, y := float32(xs[i]-float64(bounds.Min.X)), float32(ys[i]-float64(bounds.Min.Y))
+		if i == 0 {
+			z.MoveTo(x, y)
+		} else {
+			z.LineTo(x, y)
+		}
+	}
+	z.ClosePath()
+	mask := image.NewAlpha(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	z.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+
+	draw.DrawMask(c.dst, bounds, color, image.Point{}, mask, image.Point{}, draw.Over)
+}
@@ -0,0 +1,90 @@
+package canvas
+
+import (
+	"image"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestDrawRule(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		from, to  config.Point
+		thickness int
+		// expect is the rectangle filled in black, and the pixels around it are white
+		expect image.Rectangle
+	}{
+		{
+			desc:      "Horizontal rule",
+			from:      config.Point{X: 10, Y: 20},
+			to:        config.Point{X: 90, Y: 20},
+			thickness: 3,
+			expect:    image.Rect(10, 19, 90, 22),
+		},
+		{
+			desc:      "Horizontal rule from right to left",
+			from:      config.Point{X: 90, Y: 20},
+			to:        config.Point{X: 10, Y: 20},
+			thickness: 2,
+			expect:    image.Rect(10, 19, 90, 21),
+		},
+		{
+			desc:      "Vertical rule",
+			from:      config.Point{X: 50, Y: 5},
+			to:        config.Point{X: 50, Y: 35},
+			thickness: 1,
+			expect:    image.Rect(50, 5, 51, 35),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 100, 40)
+			if err := c.DrawRule(tc.from, tc.to, image.Black, tc.thickness); err != nil {
+				t.Fatal(err)
+			}
+			b := c.Bounds()
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X; x++ {
+					want := white
+					if image.Pt(x, y).In(tc.expect) {
+						want = black
+					}
+					if got := c.Image().RGBAAt(x, y); got != want {
+						t.Fatalf("unexpected color at (%d, %d): got=%v, want=%v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDrawRuleSlanted(t *testing.T) {
+	c := newTestCanvas(t, 100, 100)
+	if err := c.DrawRule(config.Point{X: 10, Y: 10}, config.Point{X: 90, Y: 90}, image.Black, 4); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Image().RGBAAt(50, 50); got != black {
+		t.Fatalf("the center of the rule is not drawn: %v", got)
+	}
+	if got := c.Image().RGBAAt(90, 10); got != white {
+		t.Fatalf("the outside of the rule is drawn: %v", got)
+	}
+	// the edges are anti-aliased
+	var partial bool
+	for x := 40; x < 60; x++ {
+		if p := c.Image().RGBAAt(x, 50); p != white && p != black {
+			partial = true
+		}
+	}
+	if !partial {
+		t.Fatal("the rule is not anti-aliased")
+	}
+}
+
+func TestDrawRuleInvalidThickness(t *testing.T) {
+	c := newTestCanvas(t, 10, 10)
+	if err := c.DrawRule(config.Point{X: 0, Y: 5}, config.Point{X: 10, Y: 5}, image.Black, 0); err == nil {
+		t.Fatal("expected an error for the thickness of 0")
+	}
+}
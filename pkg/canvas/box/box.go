@@ -6,3 +6,35 @@ const (
 	AlignLeft  = Align("Left")
 	AlignRight = Align("Right")
 )
+
+// VAlign is the vertical alignment of a text block within a region.
+type VAlign string
+
+const (
+	VAlignTop    = VAlign("Top")
+	VAlignMiddle = VAlign("Middle")
+	VAlignBottom = VAlign("Bottom")
+)
+
+// Overflow is how a text block taller or wider than its region is handled.
+type Overflow string
+
+const (
+	// OverflowError reports the overflow as an error without drawing.
+	OverflowError = Overflow("Error")
+	// OverflowClip draws the text block clipped to the region.
+	OverflowClip = Overflow("Clip")
+)
+
+// Direction is the base direction in which text is laid out.
+type Direction string
+
+const (
+	// DirectionLTR lays out text from left to right.
+	DirectionLTR = Direction("LTR")
+	// DirectionRTL lays out text from right to left.
+	DirectionRTL = Direction("RTL")
+	// DirectionAuto lays out text from right to left if the language is written in
+	// a right-to-left script or the text starts with a right-to-left character.
+	DirectionAuto = Direction("Auto")
+)
@@ -1,17 +1,26 @@
 package canvas
 
 import (
-	"bytes"
+	"errors"
+	"fmt"
 	"image"
+	"image/color"
 	"image/draw"
+	"io"
+	"math"
+	"slices"
 	"strings"
+	"unicode"
 
+	"github.com/rivo/uniseg"
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
 
 	"github.com/shunk031/tcardgen/pkg/canvas/box"
+	"github.com/shunk031/tcardgen/pkg/canvas/emoji"
 	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
 	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/text"
 )
 
 func CreateCanvasFromImage(tpl image.Image) (*Canvas, error) {
@@ -20,156 +29,727 @@ func CreateCanvasFromImage(tpl image.Image) (*Canvas, error) {
 	draw.Draw(dst, dst.Bounds(), tpl, image.Point{}, draw.Src)
 
 	return &Canvas{
-		dst: dst,
-		fdr: &font.Drawer{Dst: dst, Src: image.Black, Dot: fixed.Point26_6{}},
+		dst:         dst,
+		fdr:         &font.Drawer{Dst: dst, Src: image.Black, Dot: fixed.Point26_6{}},
+		textOpacity: 1,
 	}, nil
 }
 
+// Canvas draws texts and images on an RGBA image.
+// A Canvas is not safe for concurrent use: the draw methods share its font.Drawer while they are called.
+// To render many cards from one template concurrently, decode the template once and give each goroutine
+// its own Canvas by CreateCanvasFromImage or Clone. The template, the FontFamily and the options can be shared.
 type Canvas struct {
 	dst *image.RGBA
 	fdr *font.Drawer
 
-	bgColor    *image.Uniform
-	maxWidth   int
-	lineSpace  int
-	boxPadding config.Padding
-	boxSpace   int
-	boxAlign   box.Align
+	bgColor     *image.Uniform
+	maxWidth    int
+	lineSpace   int
+	lineHeight  float64
+	boxPadding  config.Padding
+	boxSpace    int
+	boxAlign    box.Align
+	boxVAlign   box.VAlign
+	boxRadius   int
+	boxMaxWidth int
+	boxRowWidth int
+	boxRowGap   int
+
+	boxBorderColor *image.Uniform
+	boxBorderWidth int
+	boxColor       BoxColorResolver
+
+	textOpacity float64
+	strokeColor *image.Uniform
+	strokeWidth int
+
+	shadowColor  *image.Uniform
+	shadowOffset image.Point
+	shadowBlur   int
+
+	autoFit    *autoFit
+	fontSize   float64
+	fontFamily string
+	fontStyle  fontfamily.Style
+
+	measureBounds bool
+	lang          string
+	segment       text.SegmentFunc
+	scrim         *scrim
+	hyphenate     bool
+	letterSpacing int
+	noKerning     bool
+
+	vAlign   box.VAlign
+	overflow box.Overflow
+
+	underline     bool
+	strikethrough bool
+
+	direction   box.Direction
+	visualOrder bool
+	emoji       *emoji.Font
+	subpixel    bool
+
+	gammaCorrect bool
+
+	vertical  bool
+	maxHeight int
+
+	hinting fontfamily.Hinting
+	clip    image.Rectangle
+
+	layout *Layout
+
+	// ffa is the font family of the font face set by FontFaceFromFFA, which looks up the glyphs.
+	ffa          *fontfamily.FontFamily
+	missingGlyph func(*MissingGlyphError) error
+}
+
+// Clone returns an independent copy of this canvas with its own image and drawer, which can be drawn
+// on in another goroutine. The pixels are copied as they are, so that a canvas of a template can be
+// cloned for each card without decoding or converting the template again. The font face is not
+// shared because it is not safe for concurrent use: set it by the options of each draw call.
+// The clone does not record into the layout of this canvas.
+// Clone must not be called while this canvas is drawn on.
+func (c *Canvas) Clone() *Canvas {
+	dst := &image.RGBA{
+		Pix:    slices.Clone(c.dst.Pix),
+		Stride: c.dst.Stride,
+		Rect:   c.dst.Rect,
+	}
+	cc := *c
+	cc.dst = dst
+	cc.fdr = &font.Drawer{Dst: dst, Src: c.fdr.Src}
+	cc.layout = nil
+	return &cc
+}
+
+// ErrOutOfBounds is returned when the start point to draw at is outside the canvas.
+var ErrOutOfBounds = errors.New("start point is out of the canvas bounds")
+
+// Bounds returns the bounds of this canvas.
+func (c *Canvas) Bounds() image.Rectangle {
+	return c.dst.Bounds()
+}
+
+// Width returns the width(px) of this canvas.
+func (c *Canvas) Width() int {
+	return c.dst.Bounds().Dx()
+}
+
+// Height returns the height(px) of this canvas.
+func (c *Canvas) Height() int {
+	return c.dst.Bounds().Dy()
+}
+
+// Image returns the image drawn on this canvas.
+func (c *Canvas) Image() *image.RGBA {
+	return c.dst
+}
+
+// checkBounds returns ErrOutOfBounds if the point is outside this canvas.
+func (c *Canvas) checkBounds(p config.Point) error {
+	if !image.Pt(p.X, p.Y).In(c.dst.Bounds()) {
+		return fmt.Errorf("%w: (%d, %d) is not in %v", ErrOutOfBounds, p.X, p.Y, c.dst.Bounds())
+	}
+	return nil
 }
 
 // SaveAsPNG saves this canvas as a PNG file into the specified path.
-func (c *Canvas) SaveAsPNG(filename string) error {
-	return SaveAsPNG(filename, c.dst)
+func (c *Canvas) SaveAsPNG(filename string, opts ...SaveOption) error {
+	return SaveAsPNG(filename, c.dst, opts...)
+}
+
+// Encode writes this canvas to w as a PNG image, or a JPEG image with JPEGQuality.
+func (c *Canvas) Encode(w io.Writer, opts ...SaveOption) error {
+	return Encode(w, c.dst, opts...)
 }
 
-// DrawTextAtPoint draws text on this canvas at the specified point.
-func (c *Canvas) DrawTextAtPoint(text string, start config.Point, opts ...textDrawOption) error {
+// withOptions applies the options to this canvas only while fn is called. The draw state is restored
+// afterward whether fn succeeds or not, so that the options of a call never leak into the later calls.
+func (c *Canvas) withOptions(opts []TextDrawOption, fn func() error) error {
+	saved, fdr := *c, *c.fdr
+	defer func() {
+		*c = saved
+		*c.fdr = fdr
+	}()
 	for _, f := range opts {
 		if err := f(c); err != nil {
 			return err
 		}
 	}
+	return fn()
+}
+
+// DrawTextAtPoint draws text on this canvas at the specified point, which is the top right corner
+// of the text block for right-to-left text. It returns ErrOutOfBounds if the point is outside this canvas.
+func (c *Canvas) DrawTextAtPoint(text string, start config.Point, opts ...TextDrawOption) error {
+	if err := c.checkBounds(start); err != nil {
+		return err
+	}
+	return c.withOptions(opts, func() error {
+		return c.drawTextAt(text, fixed.P(start.X, start.Y))
+	})
+}
+
+func (c *Canvas) drawTextAt(text string, start fixed.Point26_6) error {
+	if err := c.checkGlyphs(text); err != nil {
+		return err
+	}
+	p, w, h, lines, err := c.layoutText(text, start)
+	if err != nil {
+		return err
+	}
+	rtl := c.isRTL(text)
+	c.clipped(func() {
+		c.drawTextBlock(p, w, h, lines, rtl)
+	})
+	return nil
+}
+
+// layoutText measures the text, and returns the top left corner of the text block at the start point.
+func (c *Canvas) layoutText(text string, start fixed.Point26_6) (p fixed.Point26_6, w, h fixed.Int26_6, lines []string, err error) {
+	w, h, lines, err = c.measureText(text)
+	if err != nil {
+		return p, 0, 0, nil, err
+	}
+	p = c.snapPoint(start)
+	if c.isRTL(text) || c.vertical {
+		p.X -= w
+	}
+	return p, w, h, lines, nil
+}
+
+// TextBounds returns the bounds and the lines of the text laid out at the point as DrawTextAtPoint does,
+// without drawing it. The bounds may be outside of this canvas if the text overflows it.
+func (c *Canvas) TextBounds(text string, start config.Point, opts ...TextDrawOption) (bounds image.Rectangle, lines []string, err error) {
+	err = c.withOptions(opts, func() error {
+		p, w, h, ls, err := c.layoutText(text, fixed.P(start.X, start.Y))
+		bounds, lines = image.Rect(p.X.Floor(), p.Y.Floor(), (p.X+w).Ceil(), (p.Y+h).Ceil()), ls
+		return err
+	})
+	if err != nil {
+		return image.Rectangle{}, nil, err
+	}
+	return bounds, lines, nil
+}
+
+// drawTextBlock draws the measured lines with the top left corner at the point.
+// Right-to-left lines are aligned to the right of the block.
+func (c *Canvas) drawTextBlock(p fixed.Point26_6, w, h fixed.Int26_6, lines []string, rtl bool) {
+	if c.scrim != nil {
+		c.drawScrim(image.Rect(p.X.Floor(), p.Y.Floor(), (p.X + w).Ceil(), (p.Y + h).Ceil()))
+	}
 
 	// dot.y points baseline of text
-	c.fdr.Dot.Y = fixed.I(start.Y) + c.fdr.Face.Metrics().Height
-	c.fdr.Dot.X = fixed.I(start.X)
+	c.fdr.Dot.Y = p.Y + c.fdr.Face.Metrics().Height
+	c.fdr.Dot.X = p.X
 
-	if c.maxWidth == 0 {
-		c.fdr.DrawString(text)
-		return nil
+	if c.vertical {
+		c.drawColumns(p, w, lines)
+	} else {
+		c.drawLines(lines, w, rtl)
 	}
+}
 
-	c.drawMultiLineText(text)
-	return nil
+// MeasureText returns the size(px) of the bounding box and the lines of the text laid out
+// as DrawTextAtPoint does, without drawing it. The height is measured from the top of the
+// first line to the descent of the last line. The lines are the columns of vertical text.
+func (c *Canvas) MeasureText(text string, opts ...TextDrawOption) (width, height int, lines []string, err error) {
+	err = c.withOptions(opts, func() error {
+		w, h, ls, err := c.measureText(text)
+		width, height, lines = w.Ceil(), h.Ceil(), ls
+		return err
+	})
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return width, height, lines, nil
 }
 
-func (c *Canvas) drawMultiLineText(text string) {
-	var (
-		x      = c.fdr.Dot.X
-		rtext  = []rune(text)
-		length = len(rtext)
+// measureText measures the text as MeasureText does with the current options. The size is rounded up to
+// whole pixels unless the subpixel positioning is enabled.
+func (c *Canvas) measureText(text string) (width, height fixed.Int26_6, lines []string, err error) {
+	if c.autoFit != nil && c.lineLimit() > 0 {
+		if err := c.fitFontFace(text); err != nil {
+			return 0, 0, nil, err
+		}
+	}
 
-		lbuf bytes.Buffer
-		wbuf bytes.Buffer
-	)
-	for i := 0; i < length; i++ {
-		r := rtext[i]
+	if c.lineLimit() == 0 {
+		lines = splitLines(text)
+	} else {
+		lines = c.wrapText(text)
+	}
+
+	var w, h fixed.Int26_6
+	if c.vertical {
+		w, h = c.measureColumns(lines)
+	} else {
+		fm := c.fdr.Face.Metrics()
+		for _, line := range lines {
+			w = max(w, c.advance(line))
+		}
+		h = fm.Descent
+		if n := len(lines); n > 0 {
+			h += fm.Height + c.linePitch()*fixed.Int26_6(n-1)
+		}
+	}
+	if !c.subpixel {
+		w, h = fixed.I(w.Ceil()), fixed.I(h.Ceil())
+	}
+	return w, h, lines, nil
+}
+
+func (c *Canvas) drawLines(lines []string, w fixed.Int26_6, rtl bool) {
+	x := c.fdr.Dot.X
+	for i, line := range lines {
+		if i > 0 {
+			c.fdr.Dot.Y += c.linePitch()
+		}
+		adv := c.advance(line)
+		c.fdr.Dot.X = x
+		if rtl {
+			c.fdr.Dot.X += w - adv
+		}
+		dot := c.fdr.Dot
+		c.drawString(c.visual(line, rtl))
+		c.drawDecorations(dot, adv)
+	}
+}
+
+// wrapText breaks the text into lines that fit in the maximum width with the current font face,
+// or into columns that fit in the maximum height of vertical text. Explicit newlines always break
+// the line, and each of the forced lines is wrapped independently.
+func (c *Canvas) wrapText(text string) []string {
+	var lines []string
+	for _, line := range splitLines(text) {
+		if line == "" {
+			lines = append(lines, line)
+			continue
+		}
+		lines = append(lines, c.wrapLine(line)...)
+	}
+	return lines
+}
 
-		wbuf.WriteRune(r)
+// splitLines splits the text at explicit newlines, trimming the spaces around the breaks.
+func splitLines(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := range lines {
+		if i > 0 {
+			lines[i] = strings.TrimLeftFunc(lines[i], unicode.IsSpace)
+		}
+		if i < len(lines)-1 {
+			lines[i] = strings.TrimRightFunc(lines[i], unicode.IsSpace)
+		}
+	}
+	return lines
+}
 
-		switch {
-		case spaceChar(r):
-			// noop
-		case oneByteChar(r) || startBracket(r):
-			if (i + 1) < length {
+// wrapLine breaks the line into lines that fit in the maximum width with the current font face.
+// Latin texts are broken between words, and the others between the segments of the text language.
+// A segment wider than the maximum width is broken between grapheme clusters.
+// The spaces at the end of the lines and at the start of the wrapped lines are trimmed, and the fit is
+// tested without them, so that a segment is not wrapped because of the spaces which are not drawn.
+func (c *Canvas) wrapLine(line string) []string {
+	var (
+		lines []string
+		buf   string
+	)
+	flush := func() {
+		if buf = trimRightSpace(buf); buf != "" {
+			lines = append(lines, buf)
+		}
+		buf = ""
+	}
+	for _, seg := range text.SegmentForWrappingFunc(line, c.lang, c.segment) {
+		if buf == "" && len(lines) > 0 {
+			if seg = strings.TrimLeftFunc(seg, unicode.IsSpace); seg == "" {
 				continue
 			}
-		case (i+1) < length && endChar(rtext[i+1]):
-			wbuf.WriteRune(rtext[i+1])
-			i++
 		}
+		if c.fits(trimRightSpace(buf + seg)) {
+			buf += seg
+			continue
+		}
+		flush()
+		if seg = strings.TrimLeftFunc(seg, unicode.IsSpace); seg == "" {
+			continue
+		}
+		if c.fits(trimRightSpace(seg)) {
+			buf = seg
+			continue
+		}
+		if c.hyphenate && !c.vertical {
+			var hyphenated []string
+			hyphenated, seg = c.hyphenateSegment(seg)
+			lines = append(lines, hyphenated...)
+			if c.fits(seg) {
+				buf = seg
+				continue
+			}
+		}
+		// hard-break the segment that does not fit in a line by itself
+		gr := uniseg.NewGraphemes(seg)
+		for gr.Next() {
+			if buf != "" && !c.fits(trimRightSpace(buf+gr.Str())) {
+				flush()
+			}
+			buf += gr.Str()
+		}
+	}
+	flush()
+	return lines
+}
 
-		lbuf.Write(wbuf.Bytes())
+func trimRightSpace(s string) string {
+	return strings.TrimRightFunc(s, unicode.IsSpace)
+}
 
-		adv := c.fdr.MeasureBytes(lbuf.Bytes())
-		if adv <= fixed.I(c.maxWidth) {
-			wbuf.Reset()
-			if (i + 1) < length {
-				continue
+// hyphenateSegment breaks the segment at the hyphenation points into lines ending with a hyphen,
+// as long as the rest of the segment does not fit in a line. It returns the lines and the rest.
+func (c *Canvas) hyphenateSegment(seg string) ([]string, string) {
+	var (
+		lines  []string
+		points = text.HyphenationPoints(seg)
+		start  = 0
+	)
+	for !c.fits(seg[start:]) {
+		end := -1
+		for _, p := range points {
+			if p > start && c.fits(seg[start:p]+hyphen) {
+				end = p
 			}
 		}
+		if end < 0 {
+			break
+		}
+		lines = append(lines, seg[start:end]+hyphen)
+		start = end
+	}
+	return lines, seg[start:]
+}
 
-		c.fdr.DrawBytes(lbuf.Bytes()[:lbuf.Len()-wbuf.Len()])
-		c.fdr.Dot.X = x
-		c.fdr.Dot.Y += c.fdr.Face.Metrics().Height + fixed.I(c.lineSpace)
+// fits reports whether the string fits in the maximum width, or in the maximum height of vertical text.
+func (c *Canvas) fits(s string) bool {
+	if c.vertical {
+		return c.verticalAdvance(s) <= fixed.I(c.maxHeight)
+	}
+	return c.advance(s) <= fixed.I(c.maxWidth)
+}
 
-		lbuf.Reset()
-		lbuf.Write(wbuf.Bytes())
-		wbuf.Reset()
+// DrawBoxTexts draws texts in boxes side by side from the specified point, or up to it if the boxes are
+// aligned to the right. Right-to-left texts are mirrored: the boxes are laid from the point to the left,
+// or from it if they are aligned to the right. The boxes are wrapped into rows below the point by
+// BoxRowMaxWidth, each of which is aligned as well. It returns ErrOutOfBounds if the point is outside this canvas.
+func (c *Canvas) DrawBoxTexts(texts []string, start config.Point, opts ...TextDrawOption) error {
+	if err := c.checkBounds(start); err != nil {
+		return err
 	}
+	return c.withOptions(opts, func() error {
+		for _, s := range texts {
+			if err := c.checkGlyphs(s); err != nil {
+				return err
+			}
+		}
+		c.drawBoxTexts(texts, start)
+		return nil
+	})
+}
+
+func (c *Canvas) drawBoxTexts(texts []string, start config.Point) {
+	// the colors are resolved by the texts before truncated
+	origs := texts
+	texts = c.boxTexts(texts)
 
-	if len(lbuf.Bytes()) != 0 {
-		c.fdr.DrawBytes(lbuf.Bytes()[:lbuf.Len()-wbuf.Len()])
+	rtl := c.isRTL(strings.Join(texts, " "))
+	fg := c.fdr.Src
+	c.clipped(func() {
+		for _, r := range c.boxRows(texts) {
+			c.drawBoxRow(texts[r[0]:r[1]], origs[r[0]:r[1]], start, rtl, fg)
+			start.Y += c.boxRowHeight()
+		}
+	})
+}
+
+// drawBoxRow draws a row of the boxes of the texts from the start point. The boxes and the texts are colored
+// by the original texts before truncated, or in the background color and fg.
+func (c *Canvas) drawBoxRow(texts, origs []string, start config.Point, rtl bool, fg image.Image) {
+	// x is kept in 26.6 fixed point, so that the boxes do not accumulate the rounding errors with subpixel positioning
+	x, _ := c.boxesSpan(texts, start, rtl)
+	if rtl {
+		texts, origs = slices.Clone(texts), slices.Clone(origs)
+		slices.Reverse(texts)
+		slices.Reverse(origs)
+	}
+
+	height := c.boxContentHeight()
+	rect := image.Rect(0, start.Y, 0, start.Y+c.boxPadding.Top+height+c.boxPadding.Bottom)
+	baseline := c.boxBaseline(start.Y+c.boxPadding.Top, height, c.fdr.Face.Metrics())
+
+	for i, s := range texts {
+		bg := c.bgColor
+		c.fdr.Src = fg
+		if c.boxColor != nil {
+			b, f := c.boxColor(origs[i])
+			if b != nil {
+				bg = b
+			}
+			if f != nil {
+				c.fdr.Src = f
+			}
+		}
+
+		w := c.boxTextWidth(s) + fixed.I(c.boxPadding.Left+c.boxPadding.Right)
+		rect.Min.X = x.Round()
+		rect.Max.X = (x + w).Round()
+		c.fillBox(rect, bg)
+		c.strokeBox(rect)
+
+		c.fdr.Dot.X = x + fixed.I(c.boxPadding.Left)
+		c.fdr.Dot.Y = baseline
+		c.drawString(c.visual(s, rtl))
+
+		x += w + fixed.I(c.boxSpace)
 	}
 }
 
-func (c *Canvas) DrawBoxTexts(texts []string, start config.Point, opts ...textDrawOption) error {
-	for _, f := range opts {
-		if err := f(c); err != nil {
-			return err
+// boxRows returns the ranges of the indices of the texts in the rows of boxes, which are wrapped before the
+// boxes exceeding the row max width. A box wider than it is laid in a row by itself, and all the boxes are
+// laid in a row if it is not set.
+func (c *Canvas) boxRows(texts []string) [][2]int {
+	if c.boxRowWidth <= 0 {
+		return [][2]int{{0, len(texts)}}
+	}
+	var rows [][2]int
+	first := 0
+	var width fixed.Int26_6
+	for i, s := range texts {
+		w := c.boxTextWidth(s) + fixed.I(c.boxPadding.Left+c.boxPadding.Right)
+		if i > first {
+			if width+fixed.I(c.boxSpace)+w > fixed.I(c.boxRowWidth) {
+				rows = append(rows, [2]int{first, i})
+				first, width = i, 0
+			} else {
+				width += fixed.I(c.boxSpace)
+			}
 		}
+		width += w
 	}
+	return append(rows, [2]int{first, len(texts)})
+}
 
-	p := image.Pt(start.X, start.Y)
-	if c.boxAlign == box.AlignRight {
-		n := len(texts)
-		p.X -= c.boxPadding.Left*n + c.boxPadding.Right*n + c.boxSpace*(n-1) +
-			c.fdr.MeasureString(strings.Join(texts, "")).Round()
+// boxRowHeight returns the distance(px) between the tops of the rows of boxes.
+func (c *Canvas) boxRowHeight() int {
+	return c.boxPadding.Top + c.boxContentHeight() + c.boxPadding.Bottom + c.boxRowGap
+}
+
+// boxTexts returns the texts drawn in boxes, which are truncated to fit in the box max width.
+func (c *Canvas) boxTexts(texts []string) []string {
+	if c.boxMaxWidth > 0 {
+		return c.truncateTexts(texts, c.boxMaxWidth-c.boxPadding.Left-c.boxPadding.Right)
 	}
+	return texts
+}
 
+// boxesSpan returns the left edge and the total width of the boxes of the texts laid out from the start point.
+func (c *Canvas) boxesSpan(texts []string, start config.Point, rtl bool) (x, width fixed.Int26_6) {
+	n := len(texts)
+	width = fixed.I(c.boxPadding.Left*n+c.boxPadding.Right*n+c.boxSpace*max(n-1, 0)) + c.measureTexts(texts)
+	x = fixed.I(start.X)
+	if (c.boxAlign == box.AlignRight) != rtl {
+		x -= width
+		// the boxes wider than the space on the left of start are laid from the left edge instead,
+		// so that the first boxes are not cut off
+		x = max(x, fixed.I(c.dst.Bounds().Min.X))
+	}
+	return x, width
+}
+
+// BoxTextsBounds returns the bounds of the boxes and the texts in them laid out from the point as DrawBoxTexts
+// does, without drawing them, which include all the rows of the boxes. The texts are truncated with an ellipsis
+// if they are wider than the box max width. The bounds may be outside of this canvas if the boxes overflow it.
+func (c *Canvas) BoxTextsBounds(texts []string, start config.Point, opts ...TextDrawOption) (bounds image.Rectangle, boxed []string, err error) {
+	err = c.withOptions(opts, func() error {
+		boxed = c.boxTexts(texts)
+		rtl := c.isRTL(strings.Join(boxed, " "))
+		height := c.boxPadding.Top + c.boxContentHeight() + c.boxPadding.Bottom
+		for i, r := range c.boxRows(boxed) {
+			x, w := c.boxesSpan(boxed[r[0]:r[1]], start, rtl)
+			row := image.Rect(x.Round(), start.Y, (x + w).Round(), start.Y+height)
+			if i == 0 {
+				bounds = row
+			} else {
+				bounds = bounds.Union(row)
+			}
+			start.Y += c.boxRowHeight()
+		}
+		return nil
+	})
+	if err != nil {
+		return image.Rectangle{}, nil, err
+	}
+	return bounds, boxed, nil
+}
+
+// boxContentHeight returns the height(px) of the content of boxes within the padding, which is the height of
+// the face plus the descent.
+func (c *Canvas) boxContentHeight() int {
 	fm := c.fdr.Face.Metrics()
-	fh := fm.Height
-	rect := image.Rect(0, start.Y, 0, start.Y+fh.Round()+c.boxPadding.Top+c.boxPadding.Bottom+fm.Descent.Round())
+	return fm.Height.Round() + fm.Descent.Round()
+}
 
+// boxBaseline returns the baseline of the texts in boxes whose content is the height(px) from the top(px).
+// The glyphs from the ascent to the descent are aligned vertically in the content by BoxTextVAlign.
+func (c *Canvas) boxBaseline(top, height int, m font.Metrics) fixed.Int26_6 {
+	y := fixed.I(top) + m.Ascent
+	switch c.boxVAlign {
+	case box.VAlignTop:
+	case box.VAlignBottom:
+		y += fixed.I(height) - m.Ascent - m.Descent
+	default:
+		y += (fixed.I(height) - m.Ascent - m.Descent) / 2
+	}
+	if c.subpixel {
+		return y
+	}
+	return fixed.I(y.Round())
+}
+
+const (
+	// ellipsis is appended to truncated texts.
+	ellipsis = "…"
+	// hyphen is appended to hyphenated lines.
+	hyphen = "-"
+)
+
+// truncateTexts truncates each text to fit in the width(px), appending an ellipsis to truncated texts.
+func (c *Canvas) truncateTexts(texts []string, width int) []string {
+	truncated := make([]string, len(texts))
+	for i, s := range texts {
+		truncated[i] = c.truncate(s, width)
+	}
+	return truncated
+}
+
+func (c *Canvas) truncate(s string, width int) string {
+	w := fixed.I(width)
+	if c.measureString(s) <= w {
+		return s
+	}
+	var buf string
+	gr := uniseg.NewGraphemes(s)
+	for gr.Next() {
+		if c.measureString(buf+gr.Str()+ellipsis) > w {
+			break
+		}
+		buf += gr.Str()
+	}
+	return strings.TrimRightFunc(buf, unicode.IsSpace) + ellipsis
+}
+
+// measureString returns the width of the string used for layout.
+// It is the advance width, or the width to the right edge of the drawn glyphs if the bounds measuring is enabled.
+func (c *Canvas) measureString(s string) fixed.Int26_6 {
+	if !c.measureBounds {
+		return c.advance(s)
+	}
+	b, _ := font.BoundString(c.fdr.Face, s)
+	return b.Max.X + c.advance(s) - c.fdr.MeasureString(s)
+}
+
+// measureTexts returns the total width of the texts drawn separately in boxes.
+func (c *Canvas) measureTexts(texts []string) fixed.Int26_6 {
+	if !c.measureBounds && c.letterSpacing == 0 && !c.noKerning && c.emoji == nil && !c.subpixel {
+		return fixed.I(c.fdr.MeasureString(strings.Join(texts, "")).Round())
+	}
+	var w fixed.Int26_6
 	for _, s := range texts {
-		fw := c.fdr.MeasureString(s)
-		rect.Min.X = p.X
-		rect.Max.X = p.X + fw.Round() + c.boxPadding.Left + c.boxPadding.Right
-		draw.Draw(c.dst, rect, c.bgColor, p, draw.Src)
+		w += c.boxTextWidth(s)
+	}
+	return w
+}
 
-		c.fdr.Dot.X = fixed.I(p.X + c.boxPadding.Left)
-		c.fdr.Dot.Y = fixed.I(p.Y+c.boxPadding.Top-1) + fh
-		c.fdr.DrawString(s)
+// boxTextWidth returns the width of the text in a box, which is rounded to whole pixels
+// unless the subpixel positioning is enabled.
+func (c *Canvas) boxTextWidth(s string) fixed.Int26_6 {
+	w := c.measureString(s)
+	if c.subpixel {
+		return w
+	}
+	return fixed.I(w.Round())
+}
 
-		p.X = rect.Max.X + c.boxSpace
+// fillBox fills the box background in the color. Corners are rounded if the box radius is set.
+func (c *Canvas) fillBox(rect image.Rectangle, bg *image.Uniform) {
+	if c.layout != nil {
+		c.recordBox(rect, bg)
+		return
 	}
-	return nil
+	if c.boxRadius <= 0 {
+		draw.Draw(c.dst, rect, bg, image.Point{}, draw.Src)
+		return
+	}
+	mask := roundedRectMask(rect, c.boxRadius)
+	draw.DrawMask(c.dst, rect, bg, image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// strokeBox draws the box border inside the box bounds so that it does not shift the layout.
+func (c *Canvas) strokeBox(rect image.Rectangle) {
+	if c.boxBorderWidth <= 0 || c.boxBorderColor == nil || c.layout != nil {
+		return
+	}
+	mask := roundedRingMask(rect, c.boxRadius, c.boxBorderWidth)
+	draw.DrawMask(c.dst, rect, c.boxBorderColor, image.Point{}, mask, image.Point{}, draw.Over)
 }
 
-type textDrawOption func(*Canvas) error
+// TextDrawOption configures how the text is drawn on the canvas. The options apply only to the call they
+// are given to, and the unspecified ones are the defaults.
+type TextDrawOption func(*Canvas) error
 
 // FontFace sets font face.
-func FontFace(ff font.Face) textDrawOption {
+func FontFace(ff font.Face) TextDrawOption {
 	return func(c *Canvas) error {
 		c.fdr.Face = ff
+		c.fontSize = 0
+		c.fontFamily, c.fontStyle = "", ""
+		c.ffa = nil
+		c.autoFit = nil
 		return nil
 	}
 }
 
 // FontFaceFromFFA sets font face from FontFamily.
-func FontFaceFromFFA(ffa *fontfamily.FontFamily, style fontfamily.Style, size float64) textDrawOption {
+func FontFaceFromFFA(ffa *fontfamily.FontFamily, style fontfamily.Style, size float64) TextDrawOption {
 	return func(c *Canvas) error {
-		ff, err := ffa.NewFace(style, size)
+		ff, err := ffa.NewFace(style, size, fontfamily.FaceHinting(c.hinting))
 		if err != nil {
 			return err
 		}
 		c.fdr.Face = ff
+		c.fontSize = size
+		c.fontFamily, c.fontStyle = ffa.Name, style
+		c.ffa = ffa
+		c.emoji = ffa.Emoji()
+		c.autoFit = nil
+		return nil
+	}
+}
+
+// FontHinting sets the hinting of the font faces created by FontFaceFromFFA and AutoFit, which must follow it.
+// The default is fontfamily.HintingNone.
+func FontHinting(h fontfamily.Hinting) TextDrawOption {
+	return func(c *Canvas) error {
+		c.hinting = h
 		return nil
 	}
 }
 
 // FgColor sets foreground color.
-func FgColor(color *image.Uniform) textDrawOption {
+func FgColor(color *image.Uniform) TextDrawOption {
 	return func(c *Canvas) error {
 		c.fdr.Src = color
 		return nil
@@ -177,15 +757,25 @@ func FgColor(color *image.Uniform) textDrawOption {
 }
 
 // BgColor sets background color.
-func BgColor(color *image.Uniform) textDrawOption {
+func BgColor(color *image.Uniform) TextDrawOption {
 	return func(c *Canvas) error {
 		c.bgColor = color
 		return nil
 	}
 }
 
+// FgColorC sets foreground color of any color.Color, such as color.RGBA.
+func FgColorC(clr color.Color) TextDrawOption {
+	return FgColor(image.NewUniform(clr))
+}
+
+// BgColorC sets background color of any color.Color, such as color.RGBA.
+func BgColorC(clr color.Color) TextDrawOption {
+	return BgColor(image.NewUniform(clr))
+}
+
 // FgHexColor sets foreground color hex.
-func FgHexColor(hex string) textDrawOption {
+func FgHexColor(hex string) TextDrawOption {
 	return func(c *Canvas) error {
 		color, err := Hex(hex)
 		if err != nil {
@@ -197,7 +787,7 @@ func FgHexColor(hex string) textDrawOption {
 }
 
 // BgHexColor sets background color hex.
-func BgHexColor(hex string) textDrawOption {
+func BgHexColor(hex string) TextDrawOption {
 	return func(c *Canvas) error {
 		color, err := Hex(hex)
 		if err != nil {
@@ -210,41 +800,253 @@ func BgHexColor(hex string) textDrawOption {
 
 // MaxWidth sets maximum width of text.
 // If the full text width exceeds the limit, drawer adds line breaks.
-func MaxWidth(max int) textDrawOption {
+func MaxWidth(max int) TextDrawOption {
 	return func(c *Canvas) error {
 		c.maxWidth = max
 		return nil
 	}
 }
 
-// LineSpace sets line space(px) of multi-line text.
-func LineSpacing(px int) textDrawOption {
+// LineSpace sets line space(px) of multi-line text. It must not be negative.
+func LineSpacing(px int) TextDrawOption {
 	return func(c *Canvas) error {
+		if px < 0 {
+			return fmt.Errorf("line spacing must not be negative: %d", px)
+		}
 		c.lineSpace = px
 		return nil
 	}
 }
 
-// BoxPadding sets box padding(px).
-func BoxPadding(bp config.Padding) textDrawOption {
+// LineHeight sets the distance between the baselines of multi-line text as a multiple of the font size
+// (e.g. 1.5). It takes precedence over LineSpacing unless it is 0, and requires the font size given by
+// FontFaceFromFFA or AutoFit; it is ignored for the face set by FontFace.
+func LineHeight(multiplier float64) TextDrawOption {
 	return func(c *Canvas) error {
+		if multiplier < 0 {
+			return fmt.Errorf("line height must not be negative: %v", multiplier)
+		}
+		c.lineHeight = multiplier
+		return nil
+	}
+}
+
+// linePitch returns the distance between the baselines (or the centers of the columns for vertical text):
+// the font size times LineHeight if it is set, or the height of the face plus LineSpacing.
+func (c *Canvas) linePitch() fixed.Int26_6 {
+	if c.lineHeight > 0 && c.fontSize > 0 {
+		return fixed.Int26_6(math.Round(c.fontSize * c.lineHeight * 64))
+	}
+	return c.fdr.Face.Metrics().Height + fixed.I(c.lineSpace)
+}
+
+// BoxPadding sets box padding(px). None of the sides must be negative.
+func BoxPadding(bp config.Padding) TextDrawOption {
+	return func(c *Canvas) error {
+		if bp.Top < 0 || bp.Right < 0 || bp.Bottom < 0 || bp.Left < 0 {
+			return fmt.Errorf("box padding must not be negative: %+v", bp)
+		}
 		c.boxPadding = bp
 		return nil
 	}
 }
 
-// BoxSpacing sets box spacing(px).
-func BoxSpacing(px int) textDrawOption {
+// BoxSpacing sets box spacing(px). It must not be negative.
+func BoxSpacing(px int) TextDrawOption {
 	return func(c *Canvas) error {
+		if px < 0 {
+			return fmt.Errorf("box spacing must not be negative: %d", px)
+		}
 		c.boxSpace = px
 		return nil
 	}
 }
 
+// BoxRowMaxWidth wraps the boxes of DrawBoxTexts into rows no wider than the width(px), which are laid out
+// below each other by the height of the boxes plus the row gap. The boxes are laid out in a row if it is 0
+// (default). It must not be negative.
+func BoxRowMaxWidth(px int) TextDrawOption {
+	return func(c *Canvas) error {
+		if px < 0 {
+			return fmt.Errorf("box row max width must not be negative: %d", px)
+		}
+		c.boxRowWidth = px
+		return nil
+	}
+}
+
+// BoxRowGap sets the gap(px) between the rows of boxes wrapped by BoxRowMaxWidth. It must not be negative.
+func BoxRowGap(px int) TextDrawOption {
+	return func(c *Canvas) error {
+		if px < 0 {
+			return fmt.Errorf("box row gap must not be negative: %d", px)
+		}
+		c.boxRowGap = px
+		return nil
+	}
+}
+
 // BoxAlign sets box align.
-func BoxAlign(align box.Align) textDrawOption {
+func BoxAlign(align box.Align) TextDrawOption {
 	return func(c *Canvas) error {
 		c.boxAlign = align
 		return nil
 	}
 }
+
+// BoxTextVAlign sets the vertical alignment of texts in boxes drawn by DrawBoxTexts, which is applied to
+// the glyphs from the ascent to the descent within the padding. The default is box.VAlignMiddle.
+func BoxTextVAlign(align box.VAlign) TextDrawOption {
+	return func(c *Canvas) error {
+		c.boxVAlign = align
+		return nil
+	}
+}
+
+// BoxMaxWidth sets the maximum width(px) of a box including the padding.
+// A text wider than the box is truncated with an ellipsis, and 0 disables it.
+func BoxMaxWidth(px int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.boxMaxWidth = px
+		return nil
+	}
+}
+
+// BoxCornerRadius sets the corner radius(px) of boxes.
+// The radius is clamped to half of the box height.
+func BoxCornerRadius(px int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.boxRadius = px
+		return nil
+	}
+}
+
+// BoxBorderColor sets the border color of boxes.
+func BoxBorderColor(color *image.Uniform) TextDrawOption {
+	return func(c *Canvas) error {
+		c.boxBorderColor = color
+		return nil
+	}
+}
+
+// BoxBorderHexColor sets the border color hex of boxes.
+func BoxBorderHexColor(hex string) TextDrawOption {
+	return func(c *Canvas) error {
+		color, err := Hex(hex)
+		if err != nil {
+			return err
+		}
+		c.boxBorderColor = color
+		return nil
+	}
+}
+
+// BoxBorderWidth sets the border width(px) of boxes.
+// The border is drawn inside the box, and 0 disables it.
+func BoxBorderWidth(px int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.boxBorderWidth = px
+		return nil
+	}
+}
+
+// BoxColorResolver returns the background and foreground colors of the box of the text.
+// A nil color falls back to the color set by BgColor or FgColor.
+type BoxColorResolver func(text string) (bg, fg *image.Uniform)
+
+// BoxColors sets the resolver of the colors of each box drawn by DrawBoxTexts.
+// The layout of the boxes does not depend on their colors.
+func BoxColors(resolve BoxColorResolver) TextDrawOption {
+	return func(c *Canvas) error {
+		c.boxColor = resolve
+		return nil
+	}
+}
+
+// BoxHexColors sets the colors of the boxes by their texts, which are matched case-insensitively.
+// An empty color falls back to the color set by BgHexColor or FgHexColor.
+func BoxHexColors(colors map[string]*config.BoxColorOption) TextDrawOption {
+	return func(c *Canvas) error {
+		if len(colors) == 0 {
+			c.boxColor = nil
+			return nil
+		}
+		type boxColor struct{ bg, fg *image.Uniform }
+		m := make(map[string]boxColor, len(colors))
+		for text, bco := range colors {
+			if bco == nil {
+				continue
+			}
+			var (
+				bc  boxColor
+				err error
+			)
+			if bco.BgHexColor != "" {
+				if bc.bg, err = Hex(bco.BgHexColor); err != nil {
+					return err
+				}
+			}
+			if bco.FgHexColor != "" {
+				if bc.fg, err = Hex(bco.FgHexColor); err != nil {
+					return err
+				}
+			}
+			m[strings.ToLower(text)] = bc
+		}
+		c.boxColor = func(text string) (*image.Uniform, *image.Uniform) {
+			bc := m[strings.ToLower(text)]
+			return bc.bg, bc.fg
+		}
+		return nil
+	}
+}
+
+// MeasureBounds makes the layout use the bounds of the drawn glyphs instead of the advance width.
+// Some faces leave a side bearing after the last glyph, which offsets right-aligned texts slightly.
+func MeasureBounds(enabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.measureBounds = enabled
+		return nil
+	}
+}
+
+// Hyphenate enables hyphenation of Latin words wider than the maximum width.
+// Otherwise, such words are broken between any characters.
+func Hyphenate(enabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.hyphenate = enabled
+		return nil
+	}
+}
+
+// Lang sets the language of the text, which selects the line breaking rules.
+// The Japanese rules are used for unknown languages.
+func Lang(lang string) TextDrawOption {
+	return func(c *Canvas) error {
+		c.lang = lang
+		return nil
+	}
+}
+
+// LineBreaker sets the function which splits the texts that are not Latin into the segments
+// between which the lines are wrapped, instead of the line breaking rules of the language, e.g.
+// the Parse method of a BudouX parser with a custom model or threshold. It only applies to this
+// draw call, so a parser can be chosen per card without any global state. Latin texts are still
+// wrapped between words. A nil function restores the rules of the language.
+func LineBreaker(segment text.SegmentFunc) TextDrawOption {
+	return func(c *Canvas) error {
+		c.segment = segment
+		return nil
+	}
+}
+
+// TextOpacity sets the opacity (0-1) of text drawn by DrawRotatedText.
+func TextOpacity(opacity float64) TextDrawOption {
+	return func(c *Canvas) error {
+		if opacity < 0 || opacity > 1 {
+			return fmt.Errorf("opacity must be between 0 and 1: %v", opacity)
+		}
+		c.textOpacity = opacity
+		return nil
+	}
+}
@@ -0,0 +1,1095 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas/box"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/text"
+)
+
+var (
+	white = color.RGBA{255, 255, 255, 255}
+	black = color.RGBA{0, 0, 0, 255}
+)
+
+func TestDrawBoxTextsCornerRadius(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		radius       int
+		expectCorner color.RGBA
+	}{
+		{desc: "Zero radius draws square corners", radius: 0, expectCorner: black},
+		{desc: "Rounded corners keep the background", radius: 8, expectCorner: white},
+		{desc: "Radius is clamped to half of the box height", radius: 1000, expectCorner: white},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			if err := c.DrawBoxTexts(
+				[]string{"tag"},
+				config.Point{X: 10, Y: 10},
+				FontFace(newTestFace(t, 22)),
+				BgColor(image.NewUniform(black)),
+				BoxPadding(config.Padding{Top: 6, Right: 10, Bottom: 6, Left: 10}),
+				BoxCornerRadius(tc.radius),
+			); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.dst.RGBAAt(10, 10); got != tc.expectCorner {
+				t.Fatalf("unexpected corner color: got=%v, want=%v", got, tc.expectCorner)
+			}
+			// inside of the left edge is always filled
+			if got := c.dst.RGBAAt(14, 31); got != black {
+				t.Fatalf("unexpected edge color: got=%v, want=%v", got, black)
+			}
+		})
+	}
+}
+
+func newTestCanvas(t testing.TB, w, h int) *Canvas {
+	t.Helper()
+	tpl := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(tpl, tpl.Bounds(), image.NewUniform(white), image.Point{}, draw.Src)
+	c, err := CreateCanvasFromImage(tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// applyOptions applies the options to the canvas outside of a draw call, so that the internal state can be
+// inspected with them. The draw methods restore the state on return.
+func applyOptions(t *testing.T, c *Canvas, opts ...TextDrawOption) {
+	t.Helper()
+	for _, f := range opts {
+		if err := f(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newTestFace(t *testing.T, size float64) font.Face {
+	t.Helper()
+	f, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return truetype.NewFace(f, &truetype.Options{Size: size})
+}
+
+func TestDrawBoxTextsBorder(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	drawBox := func(t *testing.T, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 400, 100)
+		opts = append([]TextDrawOption{
+			FontFace(newTestFace(t, 22)),
+			BgColor(image.NewUniform(black)),
+			BoxPadding(config.Padding{Top: 6, Right: 10, Bottom: 6, Left: 10}),
+			BoxSpacing(4),
+		}, opts...)
+		if err := c.DrawBoxTexts([]string{"go", "hugo"}, config.Point{X: 10, Y: 10}, opts...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("Zero width is identical to no border", func(t *testing.T) {
+		want := drawBox(t)
+		got := drawBox(t, BoxBorderColor(image.NewUniform(red)), BoxBorderWidth(0))
+		if !sameImage(got.dst, want.dst) {
+			t.Fatal("zero width border changes the output")
+		}
+	})
+	t.Run("Border is drawn inside the box", func(t *testing.T) {
+		plain := drawBox(t)
+		c := drawBox(t, BoxBorderColor(image.NewUniform(red)), BoxBorderWidth(2))
+		if got := c.dst.RGBAAt(10, 10); got != red {
+			t.Fatalf("box corner is not stroked: got=%v", got)
+		}
+		if got := c.dst.RGBAAt(11, 30); got != red {
+			t.Fatalf("inner edge of the border is not stroked: got=%v", got)
+		}
+		if got := c.dst.RGBAAt(12, 30); got != black {
+			t.Fatalf("border is wider than the width: got=%v", got)
+		}
+		if got := c.dst.RGBAAt(9, 30); got != white {
+			t.Fatalf("border bleeds out of the box: got=%v", got)
+		}
+		// the border does not change the layout of boxes
+		for x := 0; x < 400; x++ {
+			if (plain.dst.RGBAAt(x, 30) == white) != (c.dst.RGBAAt(x, 30) == white) {
+				t.Fatalf("box bounds are changed at x=%d", x)
+			}
+		}
+	})
+	t.Run("Border respects the corner radius", func(t *testing.T) {
+		c := drawBox(t, BoxCornerRadius(8), BoxBorderColor(image.NewUniform(red)), BoxBorderWidth(2))
+		if got := c.dst.RGBAAt(10, 10); got != white {
+			t.Fatalf("rounded corner is stroked: got=%v", got)
+		}
+		if got := c.dst.RGBAAt(10, 30); got != red {
+			t.Fatalf("left edge is not stroked: got=%v", got)
+		}
+	})
+}
+
+func sameImage(a, b *image.RGBA) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	for i := range a.Pix {
+		if a.Pix[i] != b.Pix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDrawOptionsAreScopedToTheCall(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	face := newTestFace(t, 22)
+	testCases := []struct {
+		desc string
+		// first is drawn with the options that must not leak into second, below y=120
+		first, second func(c *Canvas) error
+	}{
+		{
+			desc: "Text",
+			first: func(c *Canvas) error {
+				return c.DrawTextAtPoint("a short title", config.Point{X: 10, Y: 10},
+					FontFace(face), FgColor(image.NewUniform(red)), MaxWidth(60), LineSpacing(10), TextUnderline(true))
+			},
+			second: func(c *Canvas) error {
+				return c.DrawTextAtPoint("a tagline in a line\nand the next", config.Point{X: 10, Y: 150}, FontFace(face))
+			},
+		},
+		{
+			desc: "Box texts",
+			first: func(c *Canvas) error {
+				return c.DrawBoxTexts([]string{"go", "hugo"}, config.Point{X: 390, Y: 10},
+					FontFace(face), BgColor(image.NewUniform(red)), BoxAlign(box.AlignRight),
+					BoxPadding(config.Padding{Top: 10, Right: 20, Bottom: 10, Left: 20}), BoxSpacing(12))
+			},
+			second: func(c *Canvas) error {
+				return c.DrawBoxTexts([]string{"go", "hugo"}, config.Point{X: 10, Y: 150},
+					FontFace(face), BgColor(image.NewUniform(black)))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 300)
+			if err := tc.first(c); err != nil {
+				t.Fatal(err)
+			}
+			if err := tc.second(c); err != nil {
+				t.Fatal(err)
+			}
+			want := newTestCanvas(t, 400, 300)
+			if err := tc.second(want); err != nil {
+				t.Fatal(err)
+			}
+			below := image.Rect(0, 120, 400, 300)
+			if !sameImage(c.dst.SubImage(below).(*image.RGBA), want.dst.SubImage(below).(*image.RGBA)) {
+				t.Fatal("the options of the first draw leak into the second draw")
+			}
+		})
+	}
+}
+
+func TestColorC(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	drawBoxes := func(t *testing.T, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 400, 100)
+		if err := c.DrawBoxTexts([]string{"go", "hugo"}, config.Point{X: 10, Y: 10}, append(opts, FontFace(newTestFace(t, 22)))...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	want := drawBoxes(t, FgColor(image.NewUniform(red)), BgColor(image.NewUniform(black)))
+	got := drawBoxes(t, FgColorC(red), BgColorC(color.Gray{}))
+	if !sameImage(got.dst, want.dst) {
+		t.Fatal("color.Color is drawn differently from *image.Uniform")
+	}
+}
+
+func TestDrawTextAtPointStroke(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	drawText := func(t *testing.T, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 400, 200)
+		opts = append([]TextDrawOption{
+			FontFace(newTestFace(t, 32)),
+			FgColor(image.NewUniform(black)),
+			MaxWidth(200),
+		}, opts...)
+		if err := c.DrawTextAtPoint("hello world again", config.Point{X: 10, Y: 10}, opts...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("Zero width is identical to no stroke", func(t *testing.T) {
+		want := drawText(t)
+		got := drawText(t, TextStroke(image.NewUniform(red), 0))
+		if !sameImage(got.dst, want.dst) {
+			t.Fatal("zero width stroke changes the output")
+		}
+	})
+	t.Run("Every wrapped line is outlined", func(t *testing.T) {
+		plain := drawText(t)
+		c := drawText(t, TextStroke(image.NewUniform(red), 2))
+		lines := 0
+		for y := 0; y < 200; y++ {
+			if !rowHasColor(plain.dst, y, black) {
+				continue
+			}
+			if !rowHasColor(c.dst, y, red) {
+				t.Fatalf("text row y=%d is not outlined", y)
+			}
+			if !rowHasColor(plain.dst, y-1, black) {
+				lines++
+			}
+		}
+		if lines < 2 {
+			t.Fatalf("text is not wrapped: lines=%d", lines)
+		}
+	})
+}
+
+func rowHasColor(img *image.RGBA, y int, c color.RGBA) bool {
+	for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+		if img.RGBAAt(x, y) == c {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDrawTextAtPointShadow(t *testing.T) {
+	red := image.NewUniform(color.RGBA{255, 0, 0, 255})
+	drawText := func(t *testing.T, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 400, 200)
+		opts = append([]TextDrawOption{
+			FontFace(newTestFace(t, 32)),
+			FgColor(image.NewUniform(black)),
+			MaxWidth(200),
+		}, opts...)
+		if err := c.DrawTextAtPoint("hello world again", config.Point{X: 10, Y: 10}, opts...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("Zero offset and blur is identical to no shadow", func(t *testing.T) {
+		want := drawText(t)
+		got := drawText(t, TextShadow(red, config.Point{}, 0))
+		if !sameImage(got.dst, want.dst) {
+			t.Fatal("invisible shadow changes the output")
+		}
+	})
+	for _, blur := range []int{0, 3} {
+		t.Run(fmt.Sprintf("Every wrapped line casts a shadow with blur %d", blur), func(t *testing.T) {
+			plain := drawText(t)
+			c := drawText(t, TextShadow(red, config.Point{X: 0, Y: 60}, blur))
+			// shadows are offset 60px downward, so the last line casts a shadow below the text
+			var lastRow int
+			for y := 0; y < 200; y++ {
+				if rowHasColor(plain.dst, y, black) {
+					lastRow = y
+				}
+			}
+			if sameImage(c.dst, plain.dst) {
+				t.Fatal("shadow is not drawn")
+			}
+			if !rowHasReddish(c.dst, lastRow+10) && !rowHasReddish(c.dst, lastRow+20) {
+				t.Fatal("the last line does not cast a shadow")
+			}
+		})
+	}
+}
+
+func rowHasReddish(img *image.RGBA, y int) bool {
+	for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+		if c := img.RGBAAt(x, y); c.R > c.G+16 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDrawBoxTextsVAlign(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	padding := config.Padding{Top: 6, Right: 10, Bottom: 6, Left: 10}
+	face := newTestFace(t, 40)
+	m := face.Metrics()
+
+	// baselineIn draws "H", which sits on the baseline, in a box, and returns the box rows and the baseline
+	baselineIn := func(t *testing.T, opts ...TextDrawOption) (top, bottom, baseline int) {
+		c := newTestCanvas(t, 200, 120)
+		if err := c.DrawBoxTexts([]string{"H"}, config.Point{X: 10, Y: 10}, append([]TextDrawOption{
+			FontFace(face),
+			FgColor(image.NewUniform(red)),
+			BgColor(image.NewUniform(black)),
+			BoxPadding(padding),
+		}, opts...)...); err != nil {
+			t.Fatal(err)
+		}
+		top, bottom, baseline = -1, -1, -1
+		for y := 0; y < 120; y++ {
+			if c.dst.RGBAAt(10, y) == black {
+				if top < 0 {
+					top = y
+				}
+				bottom = y + 1
+			}
+			for x := 0; x < 200; x++ {
+				if p := c.dst.RGBAAt(x, y); p != black && p != white {
+					baseline = y + 1
+				}
+			}
+		}
+		return top, bottom, baseline
+	}
+
+	middle := func(top, bottom int) int {
+		return top + (bottom-top-(m.Ascent+m.Descent).Round())/2 + m.Ascent.Round()
+	}
+	testCases := []struct {
+		align box.VAlign
+		// expect returns the baseline expected from the content area of the box
+		expect func(top, bottom int) int
+	}{
+		{
+			align:  box.VAlignTop,
+			expect: func(top, _ int) int { return top + m.Ascent.Round() },
+		},
+		{
+			align:  box.VAlignMiddle,
+			expect: middle,
+		},
+		{
+			align:  "",
+			expect: middle,
+		},
+		{
+			align:  box.VAlignBottom,
+			expect: func(_, bottom int) int { return bottom - m.Descent.Round() },
+		},
+	}
+	var height int
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%q", tc.align), func(t *testing.T) {
+			top, bottom, baseline := baselineIn(t, BoxTextVAlign(tc.align))
+			if height == 0 {
+				height = bottom - top
+			} else if bottom-top != height {
+				t.Fatalf("alignment changes the box height: got=%d, want=%d", bottom-top, height)
+			}
+			want := tc.expect(top+padding.Top, bottom-padding.Bottom)
+			if d := baseline - want; d < -1 || d > 1 {
+				t.Fatalf("unexpected baseline: got=%d, want=%d (box %d-%d)", baseline, want, top, bottom)
+			}
+		})
+	}
+
+	t.Run("Ascent and descent are centered", func(t *testing.T) {
+		top, bottom, baseline := baselineIn(t)
+		above := baseline - m.Ascent.Round() - (top + padding.Top)
+		below := bottom - padding.Bottom - (baseline + m.Descent.Round())
+		if d := above - below; d < -1 || d > 1 {
+			t.Fatalf("text is off-center: %dpx above, %dpx below", above, below)
+		}
+	})
+}
+
+func TestDrawBoxTextsMeasureBounds(t *testing.T) {
+	// "f" overhangs its advance width, which offsets the drawn text from the aligned edge
+	const right = 300
+	inkRight := func(t *testing.T, measureBounds bool) int {
+		c := newTestCanvas(t, 400, 100)
+		if err := c.DrawBoxTexts(
+			[]string{"Tag", "Tf"},
+			config.Point{X: right, Y: 10},
+			FontFace(newTestFace(t, 40)),
+			FgColor(image.NewUniform(black)),
+			BgColor(image.NewUniform(white)),
+			BoxAlign(box.AlignRight),
+			BoxSpacing(4),
+			MeasureBounds(measureBounds),
+		); err != nil {
+			t.Fatal(err)
+		}
+		edge := -1
+		for y := 0; y < 100; y++ {
+			for x := 0; x < 400; x++ {
+				if c.dst.RGBAAt(x, y) != white && x > edge {
+					edge = x
+				}
+			}
+		}
+		return edge
+	}
+
+	if got := inkRight(t, true); got != right-1 {
+		t.Fatalf("right edge of the drawn text is not aligned: got=%d, want=%d", got, right-1)
+	}
+	if got := inkRight(t, false); got == right-1 {
+		t.Fatalf("advance width is expected to differ from the drawn bounds: got=%d", got)
+	}
+}
+
+func TestMeasureText(t *testing.T) {
+	const text = "Measure the wrapped title before drawing it"
+	opts := func(t *testing.T) []TextDrawOption {
+		return []TextDrawOption{FontFace(newTestFace(t, 32)), FgColor(image.NewUniform(black)), MaxWidth(300), LineSpacing(10)}
+	}
+
+	c := newTestCanvas(t, 400, 300)
+	w, h, lines, err := c.MeasureText(text, opts(t)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameImage(c.dst, newTestCanvas(t, 400, 300).dst) {
+		t.Fatal("MeasureText draws on the canvas")
+	}
+	if len(lines) < 2 {
+		t.Fatalf("text is not wrapped: %q", lines)
+	}
+	if w <= 0 || w > 300 {
+		t.Fatalf("unexpected width: %d", w)
+	}
+
+	// the drawn text fits in the measured bounding box
+	if err := c.DrawTextAtPoint(text, config.Point{X: 10, Y: 10}, opts(t)...); err != nil {
+		t.Fatal(err)
+	}
+	var lastRow int
+	for y := 0; y < 300; y++ {
+		if rowHasColor(c.dst, y, black) {
+			lastRow = y
+		}
+	}
+	if lastRow < 10+h-20 || lastRow >= 10+h {
+		t.Fatalf("drawn text ends at %d, measured box ends at %d", lastRow, 10+h)
+	}
+}
+
+func TestLineHeight(t *testing.T) {
+	ffa := testfont.New(t)
+	const text = "Line\nHeight"
+	draw := func(t *testing.T, opts ...TextDrawOption) (h int, ink image.Rectangle) {
+		t.Helper()
+		opts = append([]TextDrawOption{FontFaceFromFFA(ffa, fontfamily.Regular, 40), FgColor(image.NewUniform(black))}, opts...)
+		c := newTestCanvas(t, 400, 400)
+		_, h, _, err := c.MeasureText(text, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.DrawTextAtPoint(text, config.Point{X: 10, Y: 10}, opts...); err != nil {
+			t.Fatal(err)
+		}
+		return h, inkBounds(c.dst)
+	}
+
+	h2, ink2 := draw(t, LineHeight(2))
+	h3, ink3 := draw(t, LineHeight(3))
+	if h3-h2 != 40 {
+		t.Fatalf("measured height must grow by the font size: got=%d, want=40", h3-h2)
+	}
+	if d := ink3.Max.Y - ink2.Max.Y; d != 40 {
+		t.Fatalf("second line must move by the font size: got=%d, want=40", d)
+	}
+	for _, tc := range []struct {
+		h   int
+		ink image.Rectangle
+	}{{h2, ink2}, {h3, ink3}} {
+		if tc.ink.Max.Y > 10+tc.h {
+			t.Fatalf("drawn text ends at %d, measured box ends at %d", tc.ink.Max.Y, 10+tc.h)
+		}
+	}
+
+	// LineHeight takes precedence over LineSpacing
+	if h, _ := draw(t, LineSpacing(100), LineHeight(2)); h != h2 {
+		t.Fatalf("line spacing must be ignored: got=%d, want=%d", h, h2)
+	}
+	if h, _ := draw(t, LineSpacing(100), LineHeight(0)); h <= h3 {
+		t.Fatalf("line spacing must be used without line height: got=%d", h)
+	}
+
+	if err := LineHeight(-1)(newTestCanvas(t, 10, 10)); err == nil {
+		t.Fatal("expected an error for negative line height")
+	}
+}
+
+func TestWrapTextExplicitNewlines(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		text     string
+		maxWidth int
+		expect   []string
+	}{
+		{desc: "Newline breaks a short text", text: "Hello\nWorld", maxWidth: 1000, expect: []string{"Hello", "World"}},
+		{desc: "Spaces around the break are trimmed", text: "Hello  \r\n  World", maxWidth: 1000, expect: []string{"Hello", "World"}},
+		{desc: "Newline breaks without max width", text: "Hello\nWorld", maxWidth: 0, expect: []string{"Hello", "World"}},
+		{desc: "Forced lines are wrapped independently", text: "Hello World\nGo", maxWidth: 90, expect: []string{"Hello", "World", "Go"}},
+		{desc: "Empty line is kept", text: "Hello\n\nWorld", maxWidth: 1000, expect: []string{"Hello", "", "World"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 100, 100)
+			_, _, lines, err := c.MeasureText(tc.text, FontFace(newTestFace(t, 22)), MaxWidth(tc.maxWidth))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(lines, tc.expect) {
+				t.Fatalf("unexpected lines: got=%q, want=%q", lines, tc.expect)
+			}
+		})
+	}
+}
+
+func TestWrapTextLatinWords(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		text   string
+		expect []string
+	}{
+		{desc: "Accented words are not broken", text: "Crème brûlée", expect: []string{"Crème", "brûlée"}},
+		{desc: "Word wider than the max width is hard-broken", text: "Supercalifragilistic", expect: []string{"Supercalif", "ragilistic"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 100, 100)
+			_, _, lines, err := c.MeasureText(tc.text, FontFace(newTestFace(t, 22)), MaxWidth(100))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(lines, tc.expect) {
+				t.Fatalf("unexpected lines: got=%q, want=%q", lines, tc.expect)
+			}
+		})
+	}
+}
+
+func TestWrapTextSpacesAtBoundary(t *testing.T) {
+	testCases := []struct {
+		desc string
+		// fit is the text which fits in the max width exactly
+		fit    string
+		text   string
+		expect []string
+	}{
+		{desc: "Trailing space of the last word which fits", fit: "Hello World", text: "Hello World again", expect: []string{"Hello World", "again"}},
+		{desc: "Leading space of the wrapped line", fit: "日本語", text: "日本語 日本語", expect: []string{"日本語", "日本語"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			face := newTestFace(t, 22)
+			w, _, _, err := c.MeasureText(tc.fit, FontFace(face))
+			if err != nil {
+				t.Fatal(err)
+			}
+			width, _, lines, err := c.MeasureText(tc.text, FontFace(face), MaxWidth(w))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(lines, tc.expect) {
+				t.Fatalf("unexpected lines: got=%q, want=%q", lines, tc.expect)
+			}
+			if width > w {
+				t.Fatalf("the lines are wider than the max width: %d > %d", width, w)
+			}
+		})
+	}
+}
+
+func TestLineBreaker(t *testing.T) {
+	// phrases splits the text into the phrases like BudouX
+	phrases := func(string) []string { return []string{"今日は", "晴れです"} }
+	testCases := []struct {
+		desc    string
+		breaker text.SegmentFunc
+		expect  []string
+	}{
+		{desc: "Lines are broken between the segments of the breaker", breaker: phrases, expect: []string{"今日は", "晴れです"}},
+		{desc: "Nil breaker uses the rules of the language", breaker: nil, expect: []string{"今日は晴れ", "です"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			face := newTestFace(t, 22)
+			maxWidth, _, _, err := c.MeasureText("今日は晴れ", FontFace(face))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _, lines, err := c.MeasureText("今日は晴れです", FontFace(face), MaxWidth(maxWidth), LineBreaker(tc.breaker))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(lines, tc.expect) {
+				t.Fatalf("unexpected lines: got=%q, want=%q", lines, tc.expect)
+			}
+		})
+	}
+}
+
+func TestWrapTextUnbreakableCJK(t *testing.T) {
+	const maxWidth = 200
+	// a katakana run has no segment boundaries
+	katakana := strings.Repeat("アイウエオカキクケコ", 3)
+	testCases := []struct {
+		desc          string
+		text          string
+		expectWrapped bool
+	}{
+		{desc: "Katakana run wider than the max width is broken", text: katakana, expectWrapped: true},
+		{desc: "Segments which fit are not broken", text: "今日は晴れ", expectWrapped: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			w, _, lines, err := c.MeasureText(tc.text, FontFace(newTestFace(t, 22)), MaxWidth(maxWidth), Lang("ja"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w > maxWidth {
+				t.Fatalf("text overflows: width=%d, lines=%q", w, lines)
+			}
+			if got := strings.Join(lines, ""); got != tc.text {
+				t.Fatalf("text is changed by wrapping: got=%q, want=%q", got, tc.text)
+			}
+			if wrapped := len(lines) > 1; wrapped != tc.expectWrapped {
+				t.Fatalf("unexpected lines: got=%q", lines)
+			}
+		})
+	}
+}
+
+func TestDrawBoxTextsMaxWidth(t *testing.T) {
+	const maxWidth = 120
+	testCases := []struct {
+		desc         string
+		tag          string
+		expectSuffix bool
+	}{
+		{desc: "Short tag is not truncated", tag: "Go", expectSuffix: false},
+		{desc: "Overlong tag is truncated within the max width", tag: "an-extremely-long-single-tag-name", expectSuffix: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 600, 100)
+			face := newTestFace(t, 22)
+			padding := config.Padding{Top: 6, Right: 10, Bottom: 6, Left: 10}
+			if err := c.DrawBoxTexts(
+				[]string{tc.tag},
+				config.Point{X: 10, Y: 10},
+				FontFace(face),
+				BgColor(image.NewUniform(black)),
+				BoxPadding(padding),
+				BoxMaxWidth(maxWidth),
+			); err != nil {
+				t.Fatal(err)
+			}
+			right := 0
+			for x := 0; x < 600; x++ {
+				if c.dst.RGBAAt(x, 12) == black {
+					right = x
+				}
+			}
+			if right >= 10+maxWidth {
+				t.Fatalf("box exceeds the max width: right edge=%d", right)
+			}
+
+			applyOptions(t, c, FontFace(face))
+			got := c.truncate(tc.tag, maxWidth-padding.Left-padding.Right)
+			if strings.HasSuffix(got, ellipsis) != tc.expectSuffix {
+				t.Fatalf("unexpected truncation: %q", got)
+			}
+		})
+	}
+}
+
+func TestWrapTextHyphenate(t *testing.T) {
+	const word = "internationalization"
+	testCases := []struct {
+		desc      string
+		hyphenate bool
+		expect    []string
+	}{
+		{desc: "Overlong word is hyphenated", hyphenate: true, expect: []string{"internatio-", "nalization"}},
+		{desc: "Overlong word is hard-broken without hyphenation", hyphenate: false, expect: []string{"internation", "alization"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 200, 100)
+			face := newTestFace(t, 22)
+			_, _, lines, err := c.MeasureText(word, FontFace(face), MaxWidth(110), Hyphenate(tc.hyphenate))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(lines, tc.expect) {
+				t.Fatalf("unexpected lines: got=%q, want=%q", lines, tc.expect)
+			}
+			for _, line := range lines {
+				if w := font.MeasureString(face, line); w > fixed.I(110) {
+					t.Fatalf("line %q overflows: %v", line, w.Round())
+				}
+			}
+		})
+	}
+}
+
+func TestCanvasBounds(t *testing.T) {
+	c := newTestCanvas(t, 400, 100)
+	if got, want := c.Bounds(), image.Rect(0, 0, 400, 100); got != want {
+		t.Fatalf("unexpected bounds: got=%v, want=%v", got, want)
+	}
+	if got := c.Width(); got != 400 {
+		t.Fatalf("unexpected width: got=%d, want=%d", got, 400)
+	}
+	if got := c.Height(); got != 100 {
+		t.Fatalf("unexpected height: got=%d, want=%d", got, 100)
+	}
+}
+
+func TestDrawOutOfBounds(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		start     config.Point
+		expectErr bool
+	}{
+		{desc: "Origin is in bounds", start: config.Point{X: 0, Y: 0}},
+		{desc: "Last pixel is in bounds", start: config.Point{X: 399, Y: 99}},
+		{desc: "Negative X is out of bounds", start: config.Point{X: -1, Y: 10}, expectErr: true},
+		{desc: "X equal to the width is out of bounds", start: config.Point{X: 400, Y: 10}, expectErr: true},
+		{desc: "Y beyond the height is out of bounds", start: config.Point{X: 10, Y: 500}, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			draws := map[string]func() error{
+				"DrawTextAtPoint": func() error {
+					return c.DrawTextAtPoint("text", tc.start, FontFace(newTestFace(t, 22)))
+				},
+				"DrawBoxTexts": func() error {
+					return c.DrawBoxTexts([]string{"tag"}, tc.start, FontFace(newTestFace(t, 22)), BgColor(image.NewUniform(black)))
+				},
+			}
+			for name, draw := range draws {
+				err := draw()
+				if tc.expectErr {
+					if !errors.Is(err, ErrOutOfBounds) {
+						t.Fatalf("%s: expected ErrOutOfBounds, got=%v", name, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDrawBoxTextsRightAlignOverflow(t *testing.T) {
+	draw := func(t *testing.T, start config.Point, align box.Align) *Canvas {
+		c := newTestCanvas(t, 400, 100)
+		if err := c.DrawBoxTexts([]string{"golang", "hugo", "tcardgen"}, start,
+			FontFace(newTestFace(t, 22)), BgColor(image.NewUniform(black)), BoxAlign(align),
+			BoxPadding(config.Padding{Top: 4, Right: 10, Bottom: 4, Left: 10}), BoxSpacing(8)); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	got := draw(t, config.Point{X: 60, Y: 10}, box.AlignRight)
+	want := draw(t, config.Point{X: 0, Y: 10}, box.AlignLeft)
+	if !sameImage(got.dst, want.dst) {
+		t.Fatal("the boxes wider than the space on the left of start are not laid from the left edge")
+	}
+	if got.dst.RGBAAt(0, 20) != black {
+		t.Fatalf("the first box does not start at the left edge: %v", got.dst.RGBAAt(0, 20))
+	}
+}
+
+func TestDrawBoxTextsRows(t *testing.T) {
+	tags := []string{"golang", "hugo", "tcardgen", "ogp", "twitter"}
+	padding := config.Padding{Top: 4, Right: 10, Bottom: 4, Left: 10}
+	testCases := []struct {
+		desc       string
+		maxWidth   int
+		align      box.Align
+		expectRows int
+	}{
+		{desc: "Single row without max width", align: box.AlignLeft, expectRows: 1},
+		{desc: "Left aligned rows", maxWidth: 300, align: box.AlignLeft, expectRows: 2},
+		{desc: "Right aligned rows", maxWidth: 300, align: box.AlignRight, expectRows: 2},
+		{desc: "Box wider than max width", maxWidth: 10, align: box.AlignLeft, expectRows: len(tags)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 600, 300)
+			start := config.Point{X: 20, Y: 10}
+			if tc.align == box.AlignRight {
+				start.X = 580
+			}
+			opts := []TextDrawOption{FontFace(newTestFace(t, 22)), BgColor(image.NewUniform(black)), BoxAlign(tc.align),
+				BoxPadding(padding), BoxSpacing(8), BoxRowMaxWidth(tc.maxWidth), BoxRowGap(12)}
+			var l Layout
+			c.Record(&l)
+			if err := c.DrawBoxTexts(tags, start, opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			// the boxes are grouped into the rows by their tops
+			var tops []int
+			rows := map[int]image.Rectangle{}
+			for _, b := range l.Boxes {
+				r, ok := rows[b.Rect.Min.Y]
+				if !ok {
+					tops = append(tops, b.Rect.Min.Y)
+					r = b.Rect
+				}
+				rows[b.Rect.Min.Y] = r.Union(b.Rect)
+			}
+			if len(tops) != tc.expectRows {
+				t.Fatalf("unexpected number of rows: got=%d, want=%d", len(tops), tc.expectRows)
+			}
+			for i, top := range tops {
+				r := rows[top]
+				if i > 0 && top != tops[i-1]+r.Dy()+12 {
+					t.Fatalf("row #%d is not below the previous row by the gap: top=%d, previous=%d", i, top, tops[i-1])
+				}
+				if tc.maxWidth > 0 && tc.expectRows < len(tags) && r.Dx() > tc.maxWidth {
+					t.Fatalf("row #%d is wider than the max width: %v", i, r)
+				}
+				// the span of the boxes is measured as a whole, which may differ from the rounded boxes by a pixel
+				edge := r.Min.X
+				if tc.align == box.AlignRight {
+					edge = r.Max.X
+				}
+				if d := edge - start.X; d < -1 || d > 1 {
+					t.Fatalf("row #%d is not aligned to %s at %d: %v", i, tc.align, start.X, r)
+				}
+			}
+
+			bounds, _, err := c.BoxTextsBounds(tags, start, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := rows[tops[len(tops)-1]].Max.Y; bounds.Min.Y != start.Y || bounds.Max.Y != want {
+				t.Fatalf("bounds do not include all the rows: got=%v, want=%d-%d", bounds, start.Y, want)
+			}
+		})
+	}
+}
+
+func TestNegativeSpacing(t *testing.T) {
+	testCases := []struct {
+		desc string
+		opt  TextDrawOption
+	}{
+		{desc: "LineSpacing", opt: LineSpacing(-1)},
+		{desc: "BoxSpacing", opt: BoxSpacing(-1)},
+		{desc: "BoxRowMaxWidth", opt: BoxRowMaxWidth(-1)},
+		{desc: "BoxRowGap", opt: BoxRowGap(-1)},
+		{desc: "BoxPadding", opt: BoxPadding(config.Padding{Top: 4, Right: -10, Bottom: 4, Left: 10})},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			err := c.DrawBoxTexts([]string{"go"}, config.Point{X: 10, Y: 10},
+				FontFace(newTestFace(t, 22)), BgColor(image.NewUniform(black)), tc.opt)
+			if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDrawBoxTextsColors(t *testing.T) {
+	blue := color.RGBA{0, 0, 255, 255}
+	red := color.RGBA{255, 0, 0, 255}
+	draw := func(t *testing.T, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 400, 100)
+		if err := c.DrawBoxTexts([]string{"Go", "Rust"}, config.Point{X: 10, Y: 10}, append([]TextDrawOption{
+			FontFace(newTestFace(t, 24)),
+			BgColor(image.NewUniform(blue)),
+			FgColor(image.NewUniform(white)),
+			BoxSpacing(10),
+			BoxPadding(config.Padding{Left: 5, Right: 5}),
+		}, opts...)...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	plain := draw(t)
+	colored := draw(t, BoxHexColors(map[string]*config.BoxColorOption{
+		"rust": {BgHexColor: "#FF0000"},
+		"java": {BgHexColor: "#00FF00"},
+	}))
+
+	// the row passes through the padding above the glyphs
+	y := 11
+	var rustX int
+	for x := 0; x < 400; x++ {
+		p, got := plain.dst.RGBAAt(x, y), colored.dst.RGBAAt(x, y)
+		switch {
+		case p == white && got != white:
+			t.Fatalf("the layout is changed by the colors at x=%d", x)
+		case p == blue && got == red:
+			if rustX == 0 {
+				rustX = x
+			}
+		case p == blue && got != blue:
+			t.Fatalf("unexpected box color at x=%d: got=%v", x, got)
+		}
+	}
+	if rustX == 0 {
+		t.Fatal("the box of the mapped text is not colored")
+	}
+	if colored.dst.RGBAAt(10, y) != blue {
+		t.Fatal("the box of the unmapped text is not drawn in the default color")
+	}
+}
+
+// renderTestCard draws a card on the canvas with the options created for the call, as a server does.
+func renderTestCard(c *Canvas, ffa *fontfamily.FontFamily, title string) error {
+	if err := c.DrawTextAtPoint(title, config.Point{X: 20, Y: 20},
+		FontFaceFromFFA(ffa, fontfamily.Regular, 32), FgColor(image.NewUniform(black)), MaxWidth(360)); err != nil {
+		return err
+	}
+	return c.DrawBoxTexts([]string{"go", "hugo"}, config.Point{X: 20, Y: 220},
+		FontFaceFromFFA(ffa, fontfamily.Regular, 18), FgColor(image.NewUniform(white)), BgColor(image.NewUniform(black)),
+		BoxPadding(config.Padding{Top: 4, Right: 8, Bottom: 4, Left: 8}), BoxSpacing(8))
+}
+
+func TestClone(t *testing.T) {
+	ffa := testfont.New(t)
+	tpl := newTestCanvas(t, 400, 300)
+	titles := []string{"The first card", "The second card of a long title to wrap", "第三のカード"}
+
+	wants := make([]*image.RGBA, len(titles))
+	for i, title := range titles {
+		c := newTestCanvas(t, 400, 300)
+		if err := renderTestCard(c, ffa, title); err != nil {
+			t.Fatal(err)
+		}
+		wants[i] = c.Image()
+	}
+
+	// the clones are drawn concurrently, which is checked by the race detector
+	gots := make([]*image.RGBA, len(titles))
+	errs := make([]error, len(titles))
+	var wg sync.WaitGroup
+	for i, title := range titles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := tpl.Clone()
+			errs[i] = renderTestCard(c, ffa, title)
+			gots[i] = c.Image()
+		}()
+	}
+	wg.Wait()
+
+	for i := range titles {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if !sameImage(gots[i], wants[i]) {
+			t.Errorf("card #%d drawn on a clone differs from the one drawn on a new canvas", i)
+		}
+	}
+	if !sameImage(tpl.Image(), newTestCanvas(t, 400, 300).Image()) {
+		t.Error("the template canvas is drawn on by its clones")
+	}
+}
+
+func BenchmarkCloneConcurrent(b *testing.B) {
+	ffa := testfont.New(b)
+	tpl := newTestCanvas(b, 1200, 630)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := renderTestCard(tpl.Clone(), ffa, "Generate TwitterCard(OGP) images for your Hugo posts"); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func TestTextBounds(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		opts      []TextDrawOption
+		rightEdge bool
+	}{
+		{desc: "Left-to-right text starts at the point", opts: nil, rightEdge: false},
+		{desc: "Right-to-left text ends at the point", opts: []TextDrawOption{TextDirection(box.DirectionRTL)}, rightEdge: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 200)
+			opts := append([]TextDrawOption{FontFace(newTestFace(t, 22)), MaxWidth(150)}, tc.opts...)
+			start := config.Point{X: 200, Y: 20}
+			bounds, lines, err := c.TextBounds("Hello World again", start, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, h, expectLines, err := c.MeasureText("Hello World again", opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(lines, expectLines) || bounds.Dx() != w || bounds.Dy() != h {
+				t.Fatalf("bounds do not match the measured text: bounds=%v, lines=%q, size=%dx%d", bounds, lines, w, h)
+			}
+			edge := bounds.Min.X
+			if tc.rightEdge {
+				edge = bounds.Max.X
+			}
+			if edge != start.X || bounds.Min.Y != start.Y {
+				t.Fatalf("text is not anchored at the point: %v", bounds)
+			}
+		})
+	}
+}
+
+func TestBoxTextsBounds(t *testing.T) {
+	c := newTestCanvas(t, 400, 100)
+	opts := []TextDrawOption{FontFace(newTestFace(t, 16)), BgColorC(black), BoxPadding(config.Padding{Top: 2, Right: 4, Bottom: 2, Left: 4}), BoxSpacing(6), BoxMaxWidth(60)}
+	var l Layout
+	c.Record(&l)
+	if err := c.DrawBoxTexts([]string{"go", "kubernetes"}, config.Point{X: 10, Y: 10}, opts...); err != nil {
+		t.Fatal(err)
+	}
+	bounds, boxed, err := c.BoxTextsBounds([]string{"go", "kubernetes"}, config.Point{X: 10, Y: 10}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if boxed[0] != "go" || !strings.HasSuffix(boxed[1], ellipsis) {
+		t.Fatalf("unexpected texts in boxes: %q", boxed)
+	}
+	if want := l.Boxes[0].Rect.Union(l.Boxes[1].Rect); bounds != want {
+		t.Fatalf("bounds do not match the drawn boxes: got=%v, want=%v", bounds, want)
+	}
+}
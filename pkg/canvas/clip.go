@@ -0,0 +1,50 @@
+package canvas
+
+import (
+	"image"
+	"image/draw"
+)
+
+// ClipRect clips the texts, including their boxes and effects, to the rectangle, so that drawing them never
+// changes the pixels outside of it (e.g. over a logo on the template). An empty rectangle disables the
+// clipping, which is the default.
+func ClipRect(r image.Rectangle) TextDrawOption {
+	return func(c *Canvas) error {
+		c.clip = r
+		return nil
+	}
+}
+
+// clipped calls drawFn clipped to the rectangle set by ClipRect, if any.
+func (c *Canvas) clipped(drawFn func()) {
+	if c.clip.Empty() {
+		drawFn()
+		return
+	}
+	c.drawClipped(c.clip, drawFn)
+}
+
+// drawClipped calls drawFn, and restores the pixels outside of the clip rectangle changed by it.
+// Nothing is drawn if the clip rectangle is empty.
+func (c *Canvas) drawClipped(clip image.Rectangle, drawFn func()) {
+	b := c.dst.Bounds()
+	if b.In(clip) {
+		drawFn()
+		return
+	}
+	if clip = clip.Intersect(b); clip.Empty() {
+		return
+	}
+
+	orig := image.NewRGBA(b)
+	draw.Draw(orig, b, c.dst, b.Min, draw.Src)
+	drawFn()
+	for _, r := range []image.Rectangle{
+		image.Rect(b.Min.X, b.Min.Y, b.Max.X, clip.Min.Y),
+		image.Rect(b.Min.X, clip.Max.Y, b.Max.X, b.Max.Y),
+		image.Rect(b.Min.X, clip.Min.Y, clip.Min.X, clip.Max.Y),
+		image.Rect(clip.Max.X, clip.Min.Y, b.Max.X, clip.Max.Y),
+	} {
+		draw.Draw(c.dst, r, orig, r.Min, draw.Src)
+	}
+}
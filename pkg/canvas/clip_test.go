@@ -0,0 +1,80 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestClipRect(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	clip := image.Rect(20, 10, 120, 60)
+	testCases := []struct {
+		desc string
+		draw func(c *Canvas, opts ...TextDrawOption) error
+	}{
+		{
+			desc: "Text",
+			draw: func(c *Canvas, opts ...TextDrawOption) error {
+				return c.DrawTextAtPoint("Clipped text", config.Point{X: 10, Y: 10}, opts...)
+			},
+		},
+		{
+			desc: "Box texts",
+			draw: func(c *Canvas, opts ...TextDrawOption) error {
+				return c.DrawBoxTexts([]string{"box", "texts"}, config.Point{X: 10, Y: 10},
+					append([]TextDrawOption{BgColor(image.NewUniform(red)), BoxPadding(config.Padding{Top: 5, Right: 10, Bottom: 30, Left: 10})}, opts...)...)
+			},
+		},
+		{
+			desc: "Text in box",
+			draw: func(c *Canvas, opts ...TextDrawOption) error {
+				return c.DrawTextInBox("Clipped text in the box", image.Rect(0, 0, 200, 100), opts...)
+			},
+		},
+		{
+			desc: "Rotated text",
+			draw: func(c *Canvas, opts ...TextDrawOption) error {
+				return c.DrawRotatedText("Rotated", config.Point{X: 70, Y: 35}, 30, opts...)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := []TextDrawOption{FontFace(newTestFace(t, 32)), FgColor(image.NewUniform(black))}
+
+			c := newTestCanvas(t, 200, 100)
+			if err := tc.draw(c, opts...); err != nil {
+				t.Fatal(err)
+			}
+			if ink := inkBounds(c.dst); ink.In(clip) {
+				t.Fatalf("text must spill out of the clip rectangle without clipping: %v", ink)
+			}
+
+			c = newTestCanvas(t, 200, 100)
+			if err := tc.draw(c, append(opts, ClipRect(clip))...); err != nil {
+				t.Fatal(err)
+			}
+			ink := inkBounds(c.dst)
+			if ink.Empty() {
+				t.Fatal("nothing is drawn in the clip rectangle")
+			}
+			if !ink.In(clip) {
+				t.Fatalf("drawn pixels are outside of the clip rectangle: ink=%v, clip=%v", ink, clip)
+			}
+		})
+	}
+}
+
+func TestClipRectDisjoint(t *testing.T) {
+	c := newTestCanvas(t, 200, 100)
+	if err := c.DrawTextInBox("Nothing", image.Rect(0, 0, 150, 50),
+		FontFace(newTestFace(t, 32)), FgColor(image.NewUniform(black)), ClipRect(image.Rect(150, 60, 200, 100))); err != nil {
+		t.Fatal(err)
+	}
+	if ink := inkBounds(c.dst); !ink.Empty() {
+		t.Fatalf("text is drawn outside of the clip rectangle: %v", ink)
+	}
+}
@@ -1,20 +1,16 @@
 package canvas
 
 import (
-	"fmt"
 	"image"
-	"image/color"
+
+	"github.com/shunk031/tcardgen/pkg/style"
 )
 
 // Hex create image.Uniform from the specified color hex.
 func Hex(hex string) (*image.Uniform, error) {
-	var r, g, b uint8
-	n, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b)
+	c, err := style.ParseHexColor(hex)
 	if err != nil {
 		return nil, err
 	}
-	if n != 3 {
-		return nil, fmt.Errorf("failed to parse %v as a hex color", hex)
-	}
-	return image.NewUniform(color.RGBA{r, g, b, 255}), nil
+	return image.NewUniform(c), nil
 }
@@ -0,0 +1,66 @@
+package canvas
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// decorationRects returns the rectangles of the underline and the strikethrough of a line
+// drawn from the dot with the width. The face does not provide the positions of the lines,
+// so they are derived from the em size as most fonts define them.
+func (c *Canvas) decorationRects(face font.Face, dot fixed.Point26_6, width fixed.Int26_6) []image.Rectangle {
+	if !c.underline && !c.strikethrough {
+		return nil
+	}
+	m := face.Metrics()
+	em := m.Ascent + m.Descent
+	thickness := max(1, (em / 20).Round())
+	x0, x1 := dot.X.Round(), (dot.X + width).Round()
+
+	var rects []image.Rectangle
+	if c.underline {
+		y := (dot.Y + em/10).Round()
+		rects = append(rects, image.Rect(x0, y, x1, y+thickness))
+	}
+	if c.strikethrough {
+		xh := m.XHeight
+		if xh <= 0 {
+			xh = em / 2
+		}
+		y := (dot.Y - xh/2).Round() - thickness/2
+		rects = append(rects, image.Rect(x0, y, x1, y+thickness))
+	}
+	return rects
+}
+
+// drawDecorations draws the underline and the strikethrough of a line in the text color.
+// They are recorded with the text instead if this canvas records the layout.
+func (c *Canvas) drawDecorations(dot fixed.Point26_6, width fixed.Int26_6) {
+	if c.layout != nil {
+		return
+	}
+	for _, r := range c.decorationRects(c.fdr.Face, dot, width) {
+		draw.Draw(c.dst, r, c.fdr.Src, image.Point{}, draw.Over)
+	}
+}
+
+// TextUnderline enables the underline of each line of text.
+// It does not affect the layout of text.
+func TextUnderline(enabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.underline = enabled
+		return nil
+	}
+}
+
+// TextStrikethrough enables the strikethrough of each line of text.
+// It does not affect the layout of text.
+func TextStrikethrough(enabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.strikethrough = enabled
+		return nil
+	}
+}
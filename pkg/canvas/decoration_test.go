@@ -0,0 +1,67 @@
+package canvas
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestDrawTextAtPointDecoration(t *testing.T) {
+	const text = "i    i\ni    i"
+	testCases := []struct {
+		desc string
+		opt  TextDrawOption
+	}{
+		{desc: "Underline", opt: TextUnderline(true)},
+		{desc: "Strikethrough", opt: TextStrikethrough(true)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			face := newTestFace(t, 32)
+			plain := newTestCanvas(t, 200, 150)
+			if err := plain.DrawTextAtPoint(text, config.Point{X: 10, Y: 10}, FontFace(face)); err != nil {
+				t.Fatal(err)
+			}
+			pw, ph, _, err := plain.MeasureText(text, FontFace(face))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c := newTestCanvas(t, 200, 150)
+			opts := []TextDrawOption{FontFace(face), tc.opt}
+			if err := c.DrawTextAtPoint(text, config.Point{X: 10, Y: 10}, opts...); err != nil {
+				t.Fatal(err)
+			}
+			w, h, lines, err := c.MeasureText(text, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w != pw || h != ph {
+				t.Fatalf("decoration affects the layout: got=%dx%d, want=%dx%d", w, h, pw, ph)
+			}
+
+			// each line is decorated at the center, which is a space
+			applyOptions(t, c, opts...)
+			m := face.Metrics()
+			dot := fixed.P(10, 10).Add(fixed.Point26_6{Y: m.Height})
+			for i, line := range lines {
+				rects := c.decorationRects(face, dot, c.advance(line))
+				if len(rects) != 1 {
+					t.Fatalf("unexpected decorations: %v", rects)
+				}
+				r := rects[0]
+				p := image.Pt((r.Min.X+r.Max.X)/2, r.Min.Y)
+				if got := c.dst.RGBAAt(p.X, p.Y); got != black {
+					t.Fatalf("line #%d is not decorated at %v: got=%v", i, p, got)
+				}
+				if got := plain.dst.RGBAAt(p.X, p.Y); got != white {
+					t.Fatalf("line #%d has ink at %v without decoration: got=%v", i, p, got)
+				}
+				dot.Y += m.Height
+			}
+		})
+	}
+}
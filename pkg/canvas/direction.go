@@ -0,0 +1,51 @@
+package canvas
+
+import (
+	"github.com/shunk031/tcardgen/pkg/canvas/box"
+	"github.com/shunk031/tcardgen/pkg/text"
+)
+
+// TextDirection sets the base direction of text. Right-to-left text is reordered into the visual
+// order and anchored at its right edge: the start point is the top right corner of the text block,
+// and boxes are laid from the start point to the left. The default is box.DirectionLTR.
+//
+// Only the directional ordering is supported. Glyphs are not shaped, so cursive scripts such as
+// Arabic are drawn in their isolated forms unless the font maps them otherwise.
+func TextDirection(dir box.Direction) TextDrawOption {
+	return func(c *Canvas) error {
+		c.direction = dir
+		return nil
+	}
+}
+
+// VisualOrder enables reordering the right-to-left runs (e.g. Arabic or Hebrew names) of left-to-right
+// text into the visual order, so that they read correctly in a left-to-right line such as the authors.
+// Right-to-left text is always drawn in the visual order.
+func VisualOrder(enabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.visualOrder = enabled
+		return nil
+	}
+}
+
+// isRTL reports whether the text is laid out from right to left.
+func (c *Canvas) isRTL(s string) bool {
+	switch c.direction {
+	case box.DirectionRTL:
+		return true
+	case box.DirectionAuto:
+		return text.LangDirection(c.lang) == text.RightToLeft || text.BaseDirection(s) == text.RightToLeft
+	}
+	return false
+}
+
+// visual returns the string in the order in which it is drawn.
+func (c *Canvas) visual(s string, rtl bool) string {
+	switch {
+	case rtl:
+		return text.VisualOrderWithBase(s, text.RightToLeft)
+	case c.visualOrder:
+		return text.VisualOrderWithBase(s, text.LeftToRight)
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/box"
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestDrawTextAtPointRTL(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		text      string
+		opts      []TextDrawOption
+		expectRTL bool
+	}{
+		{desc: "LTR by default", text: "Title", expectRTL: false},
+		{desc: "RTL", text: "Title", opts: []TextDrawOption{TextDirection(box.DirectionRTL)}, expectRTL: true},
+		{desc: "Auto with RTL language", text: "Title", opts: []TextDrawOption{TextDirection(box.DirectionAuto), Lang("he")}, expectRTL: true},
+		{desc: "Auto with RTL text", text: "שלום Title", opts: []TextDrawOption{TextDirection(box.DirectionAuto)}, expectRTL: true},
+		{desc: "Auto with LTR text", text: "Title", opts: []TextDrawOption{TextDirection(box.DirectionAuto), Lang("en")}, expectRTL: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			start := config.Point{X: 200, Y: 20}
+			if err := c.DrawTextAtPoint(tc.text, start, append([]TextDrawOption{FontFace(newTestFace(t, 32))}, tc.opts...)...); err != nil {
+				t.Fatal(err)
+			}
+			ink := inkBounds(c.dst)
+			if rtl := ink.Max.X <= start.X; rtl != tc.expectRTL {
+				t.Fatalf("unexpected anchoring: ink=%v, start=%v, expectRTL=%v", ink, start, tc.expectRTL)
+			}
+		})
+	}
+}
+
+func TestDrawTextAtPointRTLAlignsLinesRight(t *testing.T) {
+	c := newTestCanvas(t, 400, 200)
+	face := newTestFace(t, 32)
+	if err := c.DrawTextAtPoint("Long first line\nEnd", config.Point{X: 390, Y: 10},
+		FontFace(face), TextDirection(box.DirectionRTL)); err != nil {
+		t.Fatal(err)
+	}
+	h := face.Metrics().Height.Ceil()
+	first := inkBounds(c.dst.SubImage(image.Rect(0, 10, 400, 10+h)).(*image.RGBA))
+	second := inkBounds(c.dst.SubImage(image.Rect(0, 10+h, 400, 10+2*h)).(*image.RGBA))
+	if first.Min.X >= second.Min.X {
+		t.Fatalf("the short line is not aligned to the right: first=%v, second=%v", first, second)
+	}
+	if d := first.Max.X - second.Max.X; d < -3 || d > 3 {
+		t.Fatalf("the right edges of lines differ: first=%v, second=%v", first, second)
+	}
+}
+
+func TestDrawBoxTextsRTL(t *testing.T) {
+	bg := color.RGBA{255, 0, 0, 255}
+	start := config.Point{X: 380, Y: 20}
+	c := newTestCanvas(t, 400, 100)
+	if err := c.DrawBoxTexts([]string{"i", "WWWW"}, start,
+		FontFace(newTestFace(t, 24)),
+		BgColor(image.NewUniform(bg)),
+		BoxSpacing(10),
+		TextDirection(box.DirectionRTL),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// find the boxes on the row from the left
+	var boxes []image.Rectangle
+	y := start.Y + 1
+	for x := 0; x < c.Width(); x++ {
+		if c.dst.RGBAAt(x, y) != bg {
+			continue
+		}
+		if n := len(boxes); n > 0 && boxes[n-1].Max.X == x {
+			boxes[n-1].Max.X++
+			continue
+		}
+		boxes = append(boxes, image.Rect(x, y, x+1, y+1))
+	}
+	if len(boxes) != 2 {
+		t.Fatalf("unexpected boxes: %v", boxes)
+	}
+	if boxes[1].Max.X != start.X {
+		t.Fatalf("boxes are not anchored at the start point: got=%d, want=%d", boxes[1].Max.X, start.X)
+	}
+	if boxes[1].Dx() >= boxes[0].Dx() {
+		t.Fatalf("the first text is not drawn on the right: %v", boxes)
+	}
+}
+
+func TestVisualOrder(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		opts   []TextDrawOption
+		expect string
+	}{
+		{desc: "Logical order by default", expect: "alice, محمد علي"},
+		{desc: "Right-to-left runs in the visual order", opts: []TextDrawOption{VisualOrder(true)}, expect: "alice, يلع دمحم"},
+		{desc: "Right-to-left text is in the visual order anyway", opts: []TextDrawOption{TextDirection(box.DirectionRTL)}, expect: "يلع دمحم ,alice"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			var l Layout
+			c.Record(&l)
+			if err := c.DrawTextAtPoint("alice, محمد علي", config.Point{X: 390, Y: 20}, append([]TextDrawOption{FontFace(newTestFace(t, 20))}, tc.opts...)...); err != nil {
+				t.Fatal(err)
+			}
+			if len(l.Texts) != 1 || l.Texts[0].Text != tc.expect {
+				t.Fatalf("unexpected text runs: got=%+v, want=%q", l.Texts, tc.expect)
+			}
+		})
+	}
+}
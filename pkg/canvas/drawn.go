@@ -0,0 +1,50 @@
+package canvas
+
+import (
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+// DrawnText is the layout of a text drawn by DrawTextAtPoint, with which the next text can be placed relative
+// to it, e.g. a subtitle below a title which wraps into a variable number of lines.
+type DrawnText struct {
+	// Lines is the number of the lines which the text is wrapped into, or the columns of vertical text.
+	Lines int
+	// Baseline is the y(px) of the baseline of the last line, or the bottom of the block of vertical text.
+	Baseline int
+	// Bottom is the y(px) of the bottom of the text block, which includes the descent of the last line.
+	Bottom int
+}
+
+// TextLayout returns the layout of the text drawn at the point as DrawTextAtPoint does, without drawing it.
+func (c *Canvas) TextLayout(text string, start config.Point, opts ...TextDrawOption) (DrawnText, error) {
+	var d DrawnText
+	err := c.withOptions(opts, func() error {
+		p, _, h, lines, err := c.layoutText(text, fixed.P(start.X, start.Y))
+		if err != nil {
+			return err
+		}
+		d = c.textLayout(p, h, lines)
+		return nil
+	})
+	if err != nil {
+		return DrawnText{}, err
+	}
+	return d, nil
+}
+
+// textLayout returns the layout of the lines in the text block with the top left corner at the point,
+// and the height h.
+func (c *Canvas) textLayout(p fixed.Point26_6, h fixed.Int26_6, lines []string) DrawnText {
+	bottom := (p.Y + h).Ceil()
+	if c.vertical {
+		return DrawnText{Lines: len(lines), Baseline: bottom, Bottom: bottom}
+	}
+	// dot.y points baseline of text, which advances by the pitch at each line as drawLines does
+	baseline := p.Y + c.fdr.Face.Metrics().Height
+	if len(lines) > 1 {
+		baseline += c.linePitch() * fixed.Int26_6(len(lines)-1)
+	}
+	return DrawnText{Lines: len(lines), Baseline: baseline.Round(), Bottom: bottom}
+}
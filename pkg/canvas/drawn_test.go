@@ -0,0 +1,68 @@
+package canvas
+
+import (
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestTextLayout(t *testing.T) {
+	face := newTestFace(t, 20)
+	height := face.Metrics().Height
+	start := config.Point{X: 10, Y: 10}
+	testCases := []struct {
+		desc        string
+		text        string
+		expectLines int
+	}{
+		{desc: "Single line", text: "Title", expectLines: 1},
+		{desc: "Wrapped lines", text: "The title of the post wraps into three lines", expectLines: 3},
+		{desc: "Forced lines", text: "Title\nSubtitle", expectLines: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 300)
+			opts := []TextDrawOption{FontFace(face), MaxWidth(160), LineSpacing(10)}
+			d, err := c.TextLayout(tc.text, start, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bounds, lines, err := c.TextBounds(tc.text, start, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Lines != tc.expectLines || d.Lines != len(lines) {
+				t.Fatalf("unexpected number of lines: got=%d, want=%d (%q)", d.Lines, tc.expectLines, lines)
+			}
+			pitch := height + fixed.I(10)
+			if want := (fixed.I(start.Y) + height + pitch*fixed.Int26_6(d.Lines-1)).Round(); d.Baseline != want {
+				t.Fatalf("unexpected baseline: got=%d, want=%d", d.Baseline, want)
+			}
+			if d.Bottom != bounds.Max.Y || d.Bottom < d.Baseline {
+				t.Fatalf("unexpected bottom: got=%d, want=%d", d.Bottom, bounds.Max.Y)
+			}
+			if !inkBounds(c.Image()).Empty() {
+				t.Fatal("text is drawn by the layout")
+			}
+		})
+	}
+}
+
+func TestTextLayoutVertical(t *testing.T) {
+	c := newTestCanvas(t, 400, 300)
+	start := config.Point{X: 300, Y: 10}
+	opts := []TextDrawOption{FontFace(newTestFace(t, 20)), Vertical(true), MaxHeight(100)}
+	d, err := c.TextLayout("abcdefghij", start, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounds, lines, err := c.TextBounds("abcdefghij", start, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Lines != len(lines) || d.Lines < 2 || d.Baseline != bounds.Max.Y || d.Bottom != bounds.Max.Y {
+		t.Fatalf("unexpected layout of the vertical text: %+v, bounds=%v", d, bounds)
+	}
+}
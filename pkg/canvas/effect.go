@@ -0,0 +1,173 @@
+package canvas
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+// drawString draws the string at the current dot with the text effects, and advances the dot.
+// The string is recorded instead if this canvas records the layout.
+func (c *Canvas) drawString(s string) {
+	if c.layout != nil {
+		c.recordString(s)
+		return
+	}
+	c.drawShadow(s)
+	c.drawStroke(s)
+	c.drawGlyphs(s, true)
+}
+
+// drawShadow draws the string offset from the dot in the shadow color, optionally blurred.
+func (c *Canvas) drawShadow(s string) {
+	if c.shadowColor == nil || (c.shadowOffset == (image.Point{}) && c.shadowBlur <= 0) {
+		return
+	}
+	dot := c.fdr.Dot.Add(fixed.P(c.shadowOffset.X, c.shadowOffset.Y))
+
+	// render the glyphs into an alpha mask with margins for the blur
+	b, _ := font.BoundString(c.fdr.Face, s)
+	b.Max.X += c.advance(s) - c.fdr.MeasureString(s)
+	r := image.Rect(
+		(dot.X + b.Min.X).Floor(), (dot.Y + b.Min.Y).Floor(),
+		(dot.X + b.Max.X).Ceil(), (dot.Y + b.Max.Y).Ceil(),
+	).Inset(-c.shadowBlur)
+	mask := image.NewAlpha(r)
+	c.drawSpaced(&font.Drawer{Dst: mask, Src: image.Opaque, Face: c.fdr.Face, Dot: dot}, s, false)
+	gaussianBlurAlpha(mask, c.shadowBlur)
+
+	draw.DrawMask(c.dst, r, c.shadowColor, image.Point{}, mask, r.Min, draw.Over)
+}
+
+// drawStroke draws the string offset around the dot in the stroke color, so that the
+// fill drawn on top of it gets an outline.
+func (c *Canvas) drawStroke(s string) {
+	if c.strokeWidth <= 0 || c.strokeColor == nil {
+		return
+	}
+	dot, src := c.fdr.Dot, c.fdr.Src
+	defer func() {
+		c.fdr.Dot, c.fdr.Src = dot, src
+	}()
+
+	c.fdr.Src = c.strokeColor
+	w := c.strokeWidth
+	for dy := -w; dy <= w; dy++ {
+		for dx := -w; dx <= w; dx++ {
+			if (dx == 0 && dy == 0) || dx*dx+dy*dy > w*w+w {
+				continue
+			}
+			c.fdr.Dot = dot.Add(fixed.P(dx, dy))
+			c.drawGlyphs(s, false)
+		}
+	}
+}
+
+// TextStroke sets the outline color and width(px) of text.
+// The width 0 disables the outline.
+func TextStroke(color *image.Uniform, width int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.strokeColor = color
+		c.strokeWidth = width
+		return nil
+	}
+}
+
+// TextStrokeHex sets the outline color hex and width(px) of text.
+// The width 0 disables the outline, and the color is not parsed in that case.
+func TextStrokeHex(hex string, width int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.strokeWidth = width
+		if width <= 0 {
+			return nil
+		}
+		color, err := Hex(hex)
+		if err != nil {
+			return err
+		}
+		c.strokeColor = color
+		return nil
+	}
+}
+
+// TextShadow sets the drop shadow color of text, its offset(px), and the blur radius(px).
+// A zero offset with zero blur disables the shadow.
+func TextShadow(color *image.Uniform, offset config.Point, blur int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.shadowColor = color
+		c.shadowOffset = image.Pt(offset.X, offset.Y)
+		c.shadowBlur = blur
+		return nil
+	}
+}
+
+// TextShadowHex sets the drop shadow color hex of text, its offset(px), and the blur radius(px).
+// A zero offset with zero blur disables the shadow, and the color is not parsed in that case.
+func TextShadowHex(hex string, offset config.Point, blur int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.shadowOffset = image.Pt(offset.X, offset.Y)
+		c.shadowBlur = blur
+		if offset == (config.Point{}) && blur <= 0 {
+			c.shadowColor = nil
+			return nil
+		}
+		color, err := Hex(hex)
+		if err != nil {
+			return err
+		}
+		c.shadowColor = color
+		return nil
+	}
+}
+
+// gaussianBlurAlpha blurs the alpha image in place with a separable Gaussian kernel of the radius.
+func gaussianBlurAlpha(img *image.Alpha, radius int) {
+	if radius <= 0 {
+		return
+	}
+	kernel := gaussianKernel(radius)
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	buf := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum float64
+			for k, wt := range kernel {
+				if sx := x + k - radius; sx >= 0 && sx < w {
+					sum += wt * float64(img.Pix[y*img.Stride+sx])
+				}
+			}
+			buf[y*w+x] = sum
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum float64
+			for k, wt := range kernel {
+				if sy := y + k - radius; sy >= 0 && sy < h {
+					sum += wt * buf[sy*w+x]
+				}
+			}
+			img.Pix[y*img.Stride+x] = uint8(math.Round(math.Min(255, sum)))
+		}
+	}
+}
+
+func gaussianKernel(radius int) []float64 {
+	sigma := math.Max(float64(radius)/2, 0.5)
+	kernel := make([]float64, 2*radius+1)
+	var sum float64
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel
+}
@@ -0,0 +1,63 @@
+package canvas
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/image/font"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/emoji"
+)
+
+// EmojiFont sets the color font which draws the emoji in text, while the other characters are drawn
+// with the font face. FontFaceFromFFA sets the emoji font of the font family.
+func EmojiFont(f *emoji.Font) TextDrawOption {
+	return func(c *Canvas) error {
+		c.emoji = f
+		return nil
+	}
+}
+
+// emojiSpan is a run of text drawn with the font face, or an emoji drawn with the emoji font.
+type emojiSpan struct {
+	s     string
+	glyph *emoji.Glyph
+}
+
+// emojiSpans splits the string into the runs of text and the emoji which the emoji font has.
+func (c *Canvas) emojiSpans(s string) []emojiSpan {
+	m := c.fdr.Face.Metrics()
+	size := m.Ascent + m.Descent
+
+	var spans []emojiSpan
+	gr := uniseg.NewGraphemes(s)
+	for gr.Next() {
+		if r, ok := emoji.Rune(gr.Str()); ok && c.emoji.HasGlyph(r) {
+			// a broken bitmap is drawn with the font face
+			if g, err := c.emoji.Glyph(r, size); err == nil {
+				spans = append(spans, emojiSpan{s: gr.Str(), glyph: g})
+				continue
+			}
+		}
+		if n := len(spans); n > 0 && spans[n-1].glyph == nil {
+			spans[n-1].s += gr.Str()
+			continue
+		}
+		spans = append(spans, emojiSpan{s: gr.Str()})
+	}
+	return spans
+}
+
+// drawEmoji draws the emoji glyph at the dot of the drawer and advances the dot.
+// The bitmap is drawn in its colors, or used as a mask of the drawer color.
+func drawEmoji(d *font.Drawer, g *emoji.Glyph, color bool) {
+	b := g.Image.Bounds()
+	r := b.Sub(b.Min).Add(image.Pt(d.Dot.X.Round(), d.Dot.Y.Round()).Add(g.Offset))
+	if color {
+		draw.Draw(d.Dst, r, g.Image, b.Min, draw.Over)
+	} else {
+		draw.DrawMask(d.Dst, r, d.Src, image.Point{}, g.Image, b.Min, draw.Over)
+	}
+	d.Dot.X += g.Advance
+}
@@ -0,0 +1,252 @@
+// Package emoji draws emoji with a color bitmap font in the CBDT/CBLC format, such as Noto Color Emoji.
+// The glyphs of golang.org/x/image/font are alpha masks, so the color bitmaps are decoded here instead.
+//
+// Only the glyphs mapped from single code points are supported. Sequences which are composed by the
+// ligatures of the font (ZWJ sequences, skin tones, flags, and keycaps) are drawn as their first emoji.
+package emoji
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"sync"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/fixed"
+)
+
+var errInvalidFont = errors.New("emoji: invalid color font")
+
+// Font is a color bitmap font.
+type Font struct {
+	cbdt   []byte
+	ppem   int
+	cmap   map[rune]uint16
+	glyphs map[uint16]location
+
+	mu    sync.Mutex
+	cache map[cacheKey]*Glyph
+}
+
+// location is where the bitmap of a glyph is stored in the CBDT table.
+type location struct {
+	offset, length int
+	format         int
+	// metrics of image format 19, which are stored in the index subtable
+	metrics *bigMetrics
+}
+
+type bigMetrics struct {
+	height, width      int
+	bearingX, bearingY int
+	advance            int
+}
+
+type cacheKey struct {
+	glyph uint16
+	size  fixed.Int26_6
+}
+
+// Glyph is a color bitmap glyph scaled to a font size.
+type Glyph struct {
+	// Image is the color bitmap of the glyph.
+	Image image.Image
+	// Offset is the top left corner of the bitmap relative to the dot on the baseline.
+	Offset image.Point
+	// Advance is the advance width of the glyph.
+	Advance fixed.Int26_6
+}
+
+// LoadFromFile loads a color bitmap font from a file.
+func LoadFromFile(filename string) (*Font, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// IsColorFont reports whether the font data has the color bitmap tables.
+func IsColorFont(b []byte) bool {
+	tables, err := parseTables(b)
+	if err != nil {
+		return false
+	}
+	_, cbdt := tables["CBDT"]
+	_, cblc := tables["CBLC"]
+	return cbdt && cblc
+}
+
+// Parse parses a color bitmap font. The largest bitmap strike is used for all sizes.
+func Parse(b []byte) (*Font, error) {
+	tables, err := parseTables(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range []string{"cmap", "CBDT", "CBLC"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("emoji: missing %s table", tag)
+		}
+	}
+	cmap, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	ppem, glyphs, err := parseCBLC(tables["CBLC"])
+	if err != nil {
+		return nil, err
+	}
+	return &Font{
+		cbdt:   tables["CBDT"],
+		ppem:   ppem,
+		cmap:   cmap,
+		glyphs: glyphs,
+		cache:  make(map[cacheKey]*Glyph),
+	}, nil
+}
+
+// HasGlyph reports whether the font has the bitmap of the rune.
+func (f *Font) HasGlyph(r rune) bool {
+	gid, ok := f.cmap[r]
+	if !ok {
+		return false
+	}
+	_, ok = f.glyphs[gid]
+	return ok
+}
+
+// Glyph returns the glyph of the rune scaled to the em size (px).
+func (f *Font) Glyph(r rune, size fixed.Int26_6) (*Glyph, error) {
+	gid, ok := f.cmap[r]
+	if !ok {
+		return nil, fmt.Errorf("emoji: no glyph for %U", r)
+	}
+	loc, ok := f.glyphs[gid]
+	if !ok {
+		return nil, fmt.Errorf("emoji: no bitmap for %U", r)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := cacheKey{glyph: gid, size: size}
+	if g, ok := f.cache[key]; ok {
+		return g, nil
+	}
+	img, m, err := f.decode(loc)
+	if err != nil {
+		return nil, fmt.Errorf("emoji: failed to decode %U: %w", r, err)
+	}
+
+	scale := float64(size) / 64 / float64(f.ppem)
+	sw := max(1, int(float64(m.width)*scale+0.5))
+	sh := max(1, int(float64(m.height)*scale+0.5))
+	dst := image.NewRGBA(image.Rect(0, 0, sw, sh))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	g := &Glyph{
+		Image:   dst,
+		Offset:  image.Pt(int(float64(m.bearingX)*scale+0.5), -int(float64(m.bearingY)*scale+0.5)),
+		Advance: fixed.Int26_6(float64(m.advance) * scale * 64),
+	}
+	f.cache[key] = g
+	return g, nil
+}
+
+// decode decodes the PNG bitmap of the glyph and its metrics.
+func (f *Font) decode(loc location) (image.Image, bigMetrics, error) {
+	if loc.offset < 0 || loc.offset+loc.length > len(f.cbdt) {
+		return nil, bigMetrics{}, errInvalidFont
+	}
+	b := f.cbdt[loc.offset : loc.offset+loc.length]
+
+	var m bigMetrics
+	switch loc.format {
+	case 17:
+		// smallGlyphMetrics
+		if len(b) < 5 {
+			return nil, m, errInvalidFont
+		}
+		m = bigMetrics{
+			height:   int(b[0]),
+			width:    int(b[1]),
+			bearingX: int(int8(b[2])),
+			bearingY: int(int8(b[3])),
+			advance:  int(b[4]),
+		}
+		b = b[5:]
+	case 18:
+		if len(b) < 8 {
+			return nil, m, errInvalidFont
+		}
+		m = readBigMetrics(b)
+		b = b[8:]
+	case 19:
+		if loc.metrics == nil {
+			return nil, m, errInvalidFont
+		}
+		m = *loc.metrics
+	default:
+		return nil, m, fmt.Errorf("unsupported image format %d", loc.format)
+	}
+
+	if len(b) < 4 {
+		return nil, m, errInvalidFont
+	}
+	n := int(binary.BigEndian.Uint32(b))
+	if n > len(b)-4 {
+		return nil, m, errInvalidFont
+	}
+	img, err := png.Decode(bytes.NewReader(b[4 : 4+n]))
+	if err != nil {
+		return nil, m, err
+	}
+	if m.width == 0 || m.height == 0 {
+		m.width, m.height = img.Bounds().Dx(), img.Bounds().Dy()
+	}
+	return img, m, nil
+}
+
+func readBigMetrics(b []byte) bigMetrics {
+	return bigMetrics{
+		height:   int(b[0]),
+		width:    int(b[1]),
+		bearingX: int(int8(b[2])),
+		bearingY: int(int8(b[3])),
+		advance:  int(b[4]),
+	}
+}
+
+// Rune returns the code point drawn for the grapheme cluster if it is presented as an emoji:
+// a pictograph, or a character followed by the emoji variation selector (U+FE0F).
+func Rune(cluster string) (rune, bool) {
+	var first rune = -1
+	for _, r := range cluster {
+		if first < 0 {
+			first = r
+		}
+		if r == 0xFE0F {
+			return first, true
+		}
+	}
+	if first < 0 || uniseg.StringWidth(cluster) < 2 {
+		return 0, false
+	}
+	return first, isPictograph(first)
+}
+
+// isPictograph reports whether the rune is in the blocks of emoji presented in color by default.
+func isPictograph(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF,
+		r >= 0x2600 && r <= 0x27BF,
+		r >= 0x2300 && r <= 0x23FF,
+		r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package emoji
+
+import (
+	"image/color"
+	"os"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+// testdata/red.ttf maps U+1F600 to a 10x10 red bitmap at 10ppem, with the bearing (0, 8) and the advance 12.
+
+func TestGlyph(t *testing.T) {
+	f, err := LoadFromFile("testdata/red.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.HasGlyph(0x1F600) {
+		t.Fatal("the font does not have U+1F600")
+	}
+	if f.HasGlyph('a') {
+		t.Fatal("the font has 'a'")
+	}
+
+	g, err := f.Glyph(0x1F600, fixed.I(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Image.Bounds().Size(); got.X != 20 || got.Y != 20 {
+		t.Fatalf("the bitmap is not scaled: got=%v", got)
+	}
+	if g.Offset.X != 0 || g.Offset.Y != -16 {
+		t.Fatalf("unexpected offset: got=%v", g.Offset)
+	}
+	if g.Advance != fixed.I(24) {
+		t.Fatalf("unexpected advance: got=%v", g.Advance)
+	}
+	if got := color.RGBAModel.Convert(g.Image.At(10, 10)); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Fatalf("unexpected color: got=%v", got)
+	}
+	if cached, _ := f.Glyph(0x1F600, fixed.I(20)); cached != g {
+		t.Fatal("the glyph is not cached")
+	}
+
+	if _, err := f.Glyph('a', fixed.I(20)); err == nil {
+		t.Fatal("expected an error for a missing glyph")
+	}
+}
+
+func TestIsColorFont(t *testing.T) {
+	b, err := os.ReadFile("testdata/red.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsColorFont(b) {
+		t.Fatal("the color font is not detected")
+	}
+	if IsColorFont(goregular.TTF) {
+		t.Fatal("the outline font is detected as a color font")
+	}
+	if _, err := Parse(goregular.TTF); err == nil {
+		t.Fatal("expected an error for an outline font")
+	}
+}
+
+func TestRune(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expect   rune
+		expectOK bool
+	}{
+		{input: "😀", expect: 0x1F600, expectOK: true},
+		{input: "👍🏽", expect: 0x1F44D, expectOK: true},
+		{input: "☺️", expect: 0x263A, expectOK: true},
+		{input: "☺", expectOK: false},
+		{input: "a", expectOK: false},
+		{input: "漢", expectOK: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, ok := Rune(tc.input)
+			if ok != tc.expectOK || (ok && got != tc.expect) {
+				t.Fatalf("Rune() returns unexpected value: got=%U %v, want=%U %v", got, ok, tc.expect, tc.expectOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package emoji
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// parseTables returns the tables of the sfnt font data by their tags.
+func parseTables(b []byte) (map[string][]byte, error) {
+	if len(b) < 12 {
+		return nil, errInvalidFont
+	}
+	n := int(binary.BigEndian.Uint16(b[4:]))
+	if len(b) < 12+16*n {
+		return nil, errInvalidFont
+	}
+	tables := make(map[string][]byte, n)
+	for i := 0; i < n; i++ {
+		rec := b[12+16*i:]
+		off := int(binary.BigEndian.Uint32(rec[8:]))
+		length := int(binary.BigEndian.Uint32(rec[12:]))
+		if off < 0 || length < 0 || off+length > len(b) {
+			return nil, errInvalidFont
+		}
+		tables[string(rec[:4])] = b[off : off+length]
+	}
+	return tables, nil
+}
+
+// parseCmap returns the mapping from code points to glyph IDs of the Unicode cmap subtable.
+// The formats 12 (full Unicode) and 4 (BMP) are supported.
+func parseCmap(b []byte) (map[rune]uint16, error) {
+	if len(b) < 4 {
+		return nil, errInvalidFont
+	}
+	var fmt4, fmt12 []byte
+	n := int(binary.BigEndian.Uint16(b[2:]))
+	for i := 0; i < n; i++ {
+		if len(b) < 4+8*(i+1) {
+			return nil, errInvalidFont
+		}
+		rec := b[4+8*i:]
+		platform, encoding := binary.BigEndian.Uint16(rec), binary.BigEndian.Uint16(rec[2:])
+		off := int(binary.BigEndian.Uint32(rec[4:]))
+		if !(platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))) || off+2 > len(b) {
+			continue
+		}
+		switch binary.BigEndian.Uint16(b[off:]) {
+		case 4:
+			fmt4 = b[off:]
+		case 12:
+			fmt12 = b[off:]
+		}
+	}
+	switch {
+	case fmt12 != nil:
+		return parseCmap12(fmt12)
+	case fmt4 != nil:
+		return parseCmap4(fmt4)
+	}
+	return nil, fmt.Errorf("emoji: no supported Unicode cmap subtable")
+}
+
+func parseCmap12(b []byte) (map[rune]uint16, error) {
+	if len(b) < 16 {
+		return nil, errInvalidFont
+	}
+	n := int(binary.BigEndian.Uint32(b[12:]))
+	if len(b) < 16+12*n {
+		return nil, errInvalidFont
+	}
+	m := make(map[rune]uint16)
+	for i := 0; i < n; i++ {
+		g := b[16+12*i:]
+		start, end := binary.BigEndian.Uint32(g), binary.BigEndian.Uint32(g[4:])
+		gid := binary.BigEndian.Uint32(g[8:])
+		if end < start || end > 0x10FFFF {
+			return nil, errInvalidFont
+		}
+		for c := start; c <= end; c++ {
+			m[rune(c)] = uint16(gid + c - start)
+		}
+	}
+	return m, nil
+}
+
+func parseCmap4(b []byte) (map[rune]uint16, error) {
+	if len(b) < 14 {
+		return nil, errInvalidFont
+	}
+	segs := int(binary.BigEndian.Uint16(b[6:])) / 2
+	ends := 14
+	starts := ends + 2*segs + 2
+	deltas := starts + 2*segs
+	rangeOffsets := deltas + 2*segs
+	if len(b) < rangeOffsets+2*segs {
+		return nil, errInvalidFont
+	}
+	u16 := func(off int) uint16 {
+		if off+2 > len(b) {
+			return 0
+		}
+		return binary.BigEndian.Uint16(b[off:])
+	}
+
+	m := make(map[rune]uint16)
+	for i := 0; i < segs; i++ {
+		start, end := u16(starts+2*i), u16(ends+2*i)
+		delta, ro := u16(deltas+2*i), u16(rangeOffsets+2*i)
+		for c := uint32(start); c <= uint32(end) && c != 0xFFFF; c++ {
+			gid := uint16(c) + delta
+			if ro != 0 {
+				// the offset is relative to the idRangeOffset entry itself
+				gid = u16(rangeOffsets + 2*i + int(ro) + 2*int(c-uint32(start)))
+				if gid != 0 {
+					gid += delta
+				}
+			}
+			if gid != 0 {
+				m[rune(c)] = gid
+			}
+		}
+	}
+	return m, nil
+}
+
+// parseCBLC returns the ppem of the largest strike and the locations of its glyph bitmaps in the CBDT table.
+func parseCBLC(b []byte) (int, map[uint16]location, error) {
+	if len(b) < 8 {
+		return 0, nil, errInvalidFont
+	}
+	n := int(binary.BigEndian.Uint32(b[4:]))
+	if n == 0 || len(b) < 8+48*n {
+		return 0, nil, errInvalidFont
+	}
+	// choose the largest strike, which is scaled down with the least loss
+	var size []byte
+	for i := 0; i < n; i++ {
+		s := b[8+48*i:]
+		if size == nil || s[45] > size[45] {
+			size = s
+		}
+	}
+	ppem := int(size[45])
+	arrOff := int(binary.BigEndian.Uint32(size))
+	numSubtables := int(binary.BigEndian.Uint32(size[8:]))
+	if ppem == 0 || len(b) < arrOff+8*numSubtables {
+		return 0, nil, errInvalidFont
+	}
+
+	glyphs := make(map[uint16]location)
+	for i := 0; i < numSubtables; i++ {
+		e := b[arrOff+8*i:]
+		first, last := binary.BigEndian.Uint16(e), binary.BigEndian.Uint16(e[2:])
+		off := arrOff + int(binary.BigEndian.Uint32(e[4:]))
+		if last < first || len(b) < off+8 {
+			return 0, nil, errInvalidFont
+		}
+		if err := parseIndexSubtable(b[off:], first, last, glyphs); err != nil {
+			return 0, nil, err
+		}
+	}
+	return ppem, glyphs, nil
+}
+
+// parseIndexSubtable adds the locations of the glyphs in the index subtable.
+func parseIndexSubtable(b []byte, first, last uint16, glyphs map[uint16]location) error {
+	indexFormat := binary.BigEndian.Uint16(b)
+	imageFormat := int(binary.BigEndian.Uint16(b[2:]))
+	dataOff := int(binary.BigEndian.Uint32(b[4:]))
+	count := int(last-first) + 1
+	add := func(gid uint16, start, end int, m *bigMetrics) {
+		if end > start {
+			glyphs[gid] = location{offset: dataOff + start, length: end - start, format: imageFormat, metrics: m}
+		}
+	}
+
+	switch indexFormat {
+	case 1, 3:
+		w := 4
+		if indexFormat == 3 {
+			w = 2
+		}
+		if len(b) < 8+w*(count+1) {
+			return errInvalidFont
+		}
+		at := func(i int) int {
+			if w == 2 {
+				return int(binary.BigEndian.Uint16(b[8+w*i:]))
+			}
+			return int(binary.BigEndian.Uint32(b[8+w*i:]))
+		}
+		for i := 0; i < count; i++ {
+			add(first+uint16(i), at(i), at(i+1), nil)
+		}
+	case 2, 5:
+		if len(b) < 20 {
+			return errInvalidFont
+		}
+		imageSize := int(binary.BigEndian.Uint32(b[8:]))
+		m := readBigMetrics(b[12:])
+		if indexFormat == 2 {
+			for i := 0; i < count; i++ {
+				add(first+uint16(i), imageSize*i, imageSize*(i+1), &m)
+			}
+			return nil
+		}
+		if len(b) < 24 {
+			return errInvalidFont
+		}
+		n := int(binary.BigEndian.Uint32(b[20:]))
+		if len(b) < 24+2*n {
+			return errInvalidFont
+		}
+		for i := 0; i < n; i++ {
+			add(binary.BigEndian.Uint16(b[24+2*i:]), imageSize*i, imageSize*(i+1), &m)
+		}
+	case 4:
+		n := int(binary.BigEndian.Uint32(b[8:]))
+		if len(b) < 12+4*(n+1) {
+			return errInvalidFont
+		}
+		for i := 0; i < n; i++ {
+			p := b[12+4*i:]
+			add(binary.BigEndian.Uint16(p), int(binary.BigEndian.Uint16(p[2:])), int(binary.BigEndian.Uint16(p[6:])), nil)
+		}
+	default:
+		return fmt.Errorf("emoji: unsupported index subtable format %d", indexFormat)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package canvas
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/emoji"
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestDrawTextAtPointEmoji(t *testing.T) {
+	f, err := emoji.LoadFromFile("emoji/testdata/red.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	red := color.RGBA{255, 0, 0, 255}
+	countRed := func(c *Canvas) int {
+		var n int
+		b := c.dst.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if c.dst.RGBAAt(x, y) == red {
+					n++
+				}
+			}
+		}
+		return n
+	}
+
+	c := newTestCanvas(t, 300, 100)
+	if err := c.DrawTextAtPoint("Go 😀!", config.Point{X: 10, Y: 10}, FontFace(newTestFace(t, 32))); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRed(c); n != 0 {
+		t.Fatalf("emoji is drawn without the emoji font: %d pixels", n)
+	}
+	applyOptions(t, c, FontFace(newTestFace(t, 32)))
+	plain := c.advance("Go 😀!")
+
+	c = newTestCanvas(t, 300, 100)
+	if err := c.DrawTextAtPoint("Go 😀!", config.Point{X: 10, Y: 10}, FontFace(newTestFace(t, 32)), EmojiFont(f)); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRed(c); n == 0 {
+		t.Fatal("emoji is not drawn in color")
+	}
+	applyOptions(t, c, FontFace(newTestFace(t, 32)), EmojiFont(f))
+	m := c.fdr.Face.Metrics()
+	g, err := f.Glyph(0x1F600, m.Ascent+m.Descent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.advance("Go 😀!"), c.advance("Go ")+g.Advance+c.advance("!"); got != want {
+		t.Fatalf("emoji advance is not used for the layout: got=%v, want=%v", got, want)
+	}
+	if c.advance("Go 😀!") == plain {
+		t.Fatal("the advance is not changed by the emoji font")
+	}
+}
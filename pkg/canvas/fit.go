@@ -0,0 +1,80 @@
+package canvas
+
+import (
+	"errors"
+	"math"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+)
+
+type autoFit struct {
+	ffa      *fontfamily.FontFamily
+	style    fontfamily.Style
+	minSize  float64
+	maxSize  float64
+	maxLines int
+}
+
+// MaxWidthPercent sets maximum width of text as a percentage of the canvas width.
+// If the full text width exceeds the limit, drawer adds line breaks.
+func MaxWidthPercent(percent float64) TextDrawOption {
+	return func(c *Canvas) error {
+		if percent < 0 || percent > 100 {
+			return errors.New("max width percentage must be between 0 and 100")
+		}
+		c.maxWidth = int(float64(c.dst.Bounds().Dx()) * percent / 100)
+		return nil
+	}
+}
+
+// AutoFit sets the font face to the largest size between minSize and maxSize at which the
+// text wraps into maxLines lines or less within the maximum width.
+// The minimum size is used if the text does not fit at any size.
+func AutoFit(ffa *fontfamily.FontFamily, style fontfamily.Style, minSize, maxSize float64, maxLines int) TextDrawOption {
+	return func(c *Canvas) error {
+		if minSize <= 0 || minSize > maxSize {
+			return errors.New("auto-fit font size range is invalid")
+		}
+		if maxLines < 1 {
+			return errors.New("auto-fit max lines must be positive")
+		}
+		c.autoFit = &autoFit{ffa: ffa, style: style, minSize: minSize, maxSize: maxSize, maxLines: maxLines}
+		return nil
+	}
+}
+
+// fitFontFace sets the largest font face at which the text fits in the auto-fit lines.
+// Sizes are searched at 1pt steps from the maximum size.
+func (c *Canvas) fitFontFace(text string) error {
+	af := c.autoFit
+	fits := func(size float64) (bool, error) {
+		ff, err := af.ffa.NewFace(af.style, size, fontfamily.FaceHinting(c.hinting))
+		if err != nil {
+			return false, err
+		}
+		c.fdr.Face = ff
+		return len(c.wrapText(text)) <= af.maxLines, nil
+	}
+
+	// binary search the number of 1pt steps below the maximum size
+	lo, hi := 0, int(math.Floor(af.maxSize-af.minSize))
+	for lo < hi {
+		mid := (lo + hi) / 2
+		ok, err := fits(af.maxSize - float64(mid))
+		if err != nil {
+			return err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	size := math.Max(af.maxSize-float64(lo), af.minSize)
+	if _, err := fits(size); err != nil {
+		return err
+	}
+	c.fontSize = size
+	c.fontFamily, c.fontStyle = af.ffa.Name, af.style
+	return nil
+}
@@ -0,0 +1,52 @@
+package canvas
+
+import (
+	"testing"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestAutoFitWithMaxWidthPercent(t *testing.T) {
+	ffa := testfont.New(t)
+	title := "Auto-fit picks the largest font size that keeps the title in the lines"
+	fitTitle := func(t *testing.T, width int, maxLines int) *Canvas {
+		c := newTestCanvas(t, width, 600)
+		applyOptions(t, c,
+			FontFaceFromFFA(ffa, fontfamily.Regular, 72),
+			MaxWidthPercent(80),
+			AutoFit(ffa, fontfamily.Regular, 12, 72, maxLines),
+		)
+		if _, _, _, err := c.measureText(title); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	narrow, wide := fitTitle(t, 600, 2), fitTitle(t, 1200, 2)
+	if narrow.maxWidth != 480 || wide.maxWidth != 960 {
+		t.Fatalf("unexpected max width: narrow=%d, wide=%d", narrow.maxWidth, wide.maxWidth)
+	}
+	if !(narrow.fontSize < wide.fontSize) {
+		t.Fatalf("wider canvas must fit a larger font: narrow=%v, wide=%v", narrow.fontSize, wide.fontSize)
+	}
+	for _, c := range []*Canvas{narrow, wide} {
+		if n := len(c.wrapText(title)); n > 2 {
+			t.Fatalf("title is wrapped into %d lines at %vpt", n, c.fontSize)
+		}
+	}
+
+	if c := fitTitle(t, 200, 1); c.fontSize != 12 {
+		t.Fatalf("minimum size must be used when the text does not fit: got=%v", c.fontSize)
+	}
+}
+
+func TestMaxWidthPercentOutOfRange(t *testing.T) {
+	for _, p := range []float64{-1, 101} {
+		c := newTestCanvas(t, 100, 100)
+		if err := c.DrawTextAtPoint("text", config.Point{}, FontFace(newTestFace(t, 12)), MaxWidthPercent(p)); err == nil {
+			t.Fatalf("expected an error for %v%%", p)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package fontfamily
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+const (
+	TrueTypeCollectionExt = ".ttc"
+	OpenTypeCollectionExt = ".otc"
+)
+
+const (
+	collectionTag = "ttcf"
+	// offsetTableLen is the length of the offset table of a font, which is followed by its table records.
+	offsetTableLen = 12
+	// tableRecordLen is the length of a table record: the tag, the checksum, the offset, and the length.
+	tableRecordLen = 16
+)
+
+// LoadFromCollection loads the fonts of a TrueType/OpenType Collection (".ttc" or ".otc") file into a FontFamily.
+// The family name of the first font is used as the family name, and each font is identified as the style of
+// its subfamily name (e.g. "Bold" or "Bold Italic"), or the weight and slant of its OS/2 table if the name is
+// not a style. The first font is used if some fonts have the same style.
+// The fonts must have TrueType outlines.
+func LoadFromCollection(filename string) (*FontFamily, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ffa := NewFontFamily("")
+	if err := ffa.loadCollection(b); err != nil {
+		return nil, fmt.Errorf("failed to load %q: %w", filename, err)
+	}
+	return ffa, nil
+}
+
+// loadCollection loads the fonts of the collection. The name of this font family is set to the family name
+// of the first font if it is empty.
+func (fs *FontFamily) loadCollection(b []byte) error {
+	fonts, err := splitCollection(b)
+	if err != nil {
+		return err
+	}
+	for i, fb := range fonts {
+		f, err := sfnt.Parse(fb)
+		if err != nil {
+			return fmt.Errorf("failed to parse the font #%d: %w", i, err)
+		}
+		if fs.Name == "" {
+			fs.Name, _ = f.Name(nil, sfnt.NameIDFamily)
+		}
+		style := fontStyle(f, fb)
+		if fs.HasStyle(style) {
+			continue
+		}
+		if err := fs.loadFont(fb, style); err != nil {
+			return fmt.Errorf("failed to load the font #%d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// splitCollection extracts the fonts of the collection as standalone font files, which contain the tables
+// of the fonts with their offsets rewritten, since the tables of a collection may be shared by the fonts.
+func splitCollection(b []byte) ([][]byte, error) {
+	invalid := errors.New("invalid font collection")
+	if len(b) < 12 || string(b[:4]) != collectionTag {
+		return nil, errors.New("not a font collection")
+	}
+	n := int(binary.BigEndian.Uint32(b[8:]))
+	if n == 0 || len(b) < 12+4*n {
+		return nil, invalid
+	}
+
+	fonts := make([][]byte, 0, n)
+	for i := range n {
+		off := int(binary.BigEndian.Uint32(b[12+4*i:]))
+		if off+offsetTableLen > len(b) {
+			return nil, invalid
+		}
+		numTables := int(binary.BigEndian.Uint16(b[off+4:]))
+		dir := off + offsetTableLen
+		if dir+numTables*tableRecordLen > len(b) {
+			return nil, invalid
+		}
+
+		// the offset table and the table records are followed by the tables aligned to 4 bytes
+		head := offsetTableLen + numTables*tableRecordLen
+		fb := make([]byte, head, head+len(b)/n)
+		copy(fb, b[off:off+head])
+		for t := range numTables {
+			rec := fb[offsetTableLen+t*tableRecordLen:]
+			start := int(binary.BigEndian.Uint32(rec[8:]))
+			length := int(binary.BigEndian.Uint32(rec[12:]))
+			if start+length > len(b) {
+				return nil, invalid
+			}
+			binary.BigEndian.PutUint32(rec[8:], uint32(len(fb)))
+			fb = append(fb, b[start:start+length]...)
+			for len(fb)%4 != 0 {
+				fb = append(fb, 0)
+			}
+		}
+		fonts = append(fonts, fb)
+	}
+	return fonts, nil
+}
+
+// fontStyle returns the style of the subfamily name of the font, e.g. "BoldItalic" of "Bold Italic". If the name
+// is not a style, the style of the weight and the slant in the OS/2 table of the font file is returned, or
+// Regular if the font does not have the table.
+func fontStyle(f *sfnt.Font, fb []byte) Style {
+	for _, id := range []sfnt.NameID{sfnt.NameIDTypographicSubfamily, sfnt.NameIDSubfamily} {
+		name, err := f.Name(nil, id)
+		if err != nil {
+			continue
+		}
+		if w, italic, ok := Style(strings.ReplaceAll(name, " ", "")).Weight(); ok {
+			return WeightStyle(w, italic)
+		}
+	}
+
+	os2, ok := findTable(fb, "OS/2")
+	// usWeightClass is at 4, and fsSelection at 62, whose bit 0 is italic
+	if !ok || len(os2) < 64 {
+		return Regular
+	}
+	weight := int(binary.BigEndian.Uint16(os2[4:]))
+	italic := binary.BigEndian.Uint16(os2[62:])&1 != 0
+	// round the weight to the hundreds of the styles, e.g. 350 to 400
+	weight = min(max((weight+50)/100*100, 100), 900)
+	return WeightStyle(weight, italic)
+}
+
+// findTable returns the table of the tag in the font file.
+func findTable(fb []byte, tag string) ([]byte, bool) {
+	if len(fb) < offsetTableLen {
+		return nil, false
+	}
+	numTables := int(binary.BigEndian.Uint16(fb[4:]))
+	for t := range numTables {
+		rec := offsetTableLen + t*tableRecordLen
+		if rec+tableRecordLen > len(fb) {
+			return nil, false
+		}
+		if string(fb[rec:rec+4]) != tag {
+			continue
+		}
+		start := int(binary.BigEndian.Uint32(fb[rec+8:]))
+		length := int(binary.BigEndian.Uint32(fb[rec+12:]))
+		if start+length > len(fb) {
+			return nil, false
+		}
+		return fb[start : start+length], true
+	}
+	return nil, false
+}
@@ -0,0 +1,106 @@
+package fontfamily
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// makeCollection packs the font files into a font collection.
+func makeCollection(fonts ...[]byte) []byte {
+	head := 12 + 4*len(fonts)
+	b := make([]byte, head)
+	copy(b, collectionTag)
+	binary.BigEndian.PutUint32(b[4:], 0x00010000)
+	binary.BigEndian.PutUint32(b[8:], uint32(len(fonts)))
+	for i, fb := range fonts {
+		off := len(b)
+		binary.BigEndian.PutUint32(b[12+4*i:], uint32(off))
+		b = append(b, fb...)
+		// the table offsets of the font are relative to the collection
+		numTables := int(binary.BigEndian.Uint16(fb[4:]))
+		for t := range numTables {
+			rec := b[off+offsetTableLen+t*tableRecordLen:]
+			binary.BigEndian.PutUint32(rec[8:], binary.BigEndian.Uint32(rec[8:])+uint32(off))
+		}
+	}
+	return b
+}
+
+func TestLoadFromCollection(t *testing.T) {
+	// the second Regular font is ignored
+	ttc := filepath.Join(t.TempDir(), "Go.ttc")
+	if err := os.WriteFile(ttc, makeCollection(goregular.TTF, gobold.TTF, goitalic.TTF, goregular.TTF), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ffa, err := LoadFromCollection(ttc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ffa.Name != "Go" {
+		t.Fatalf("unexpected family name: %q", ffa.Name)
+	}
+	var styles []Style
+	for s := range ffa.fonts {
+		styles = append(styles, s)
+	}
+	slices.Sort(styles)
+	if want := []Style{Bold, "Italic", Regular}; !slices.Equal(styles, want) {
+		t.Fatalf("unexpected styles: got=%v, want=%v", styles, want)
+	}
+
+	// the face is selected from the fonts of the collection
+	bold, err := ffa.NewFace(Bold, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regular, err := ffa.NewFace(Regular, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ba, _ := bold.GlyphAdvance('m')
+	ra, _ := regular.GlyphAdvance('m')
+	if ba == ra {
+		t.Fatalf("the bold face is not selected: %v", ba)
+	}
+	if fb, _, err := ffa.FontFile(Bold); err != nil || len(fb) == 0 {
+		t.Fatalf("the file of the bold font is not loaded: %v", err)
+	}
+}
+
+func TestLoadFromFSCollection(t *testing.T) {
+	fsys := fstest.MapFS{
+		"font/Go.ttc":          {Data: makeCollection(goregular.TTF, gobold.TTF)},
+		"font/Go-Medium.ttf":   {Data: goregular.TTF},
+		"font/README.md":       {Data: []byte("fonts")},
+		"broken/Broken.ttc":    {Data: []byte("ttcf\x00\x01\x00\x00\x00\x00\x00\x01\x00\x00\x10\x00")},
+		"notcollection/Go.ttc": {Data: goregular.TTF},
+	}
+	ffa, err := LoadFromFS(fsys, "font")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []Style{Regular, Medium, Bold} {
+		if !ffa.HasStyle(s) {
+			t.Fatalf("%q style is not loaded", s)
+		}
+	}
+	for _, dir := range []string{"broken", "notcollection"} {
+		if _, err := LoadFromFS(fsys, dir); err == nil {
+			t.Fatalf("expected an error for %s", dir)
+		}
+	}
+}
+
+func TestLoadFromCollectionNotExist(t *testing.T) {
+	if _, err := LoadFromCollection(filepath.Join(t.TempDir(), "missing.ttc")); !os.IsNotExist(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -3,12 +3,16 @@ package fontfamily
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/emoji"
 )
 
 type Style string
@@ -26,34 +30,106 @@ const (
 	TrueTypeFontExt = ".ttf"
 )
 
+// Hinting is how the glyph outlines are fitted to the pixel grid.
+type Hinting string
+
+const (
+	// HintingNone draws the outlines as designed. Large glyphs keep their shapes, while small text may look fuzzy.
+	HintingNone = Hinting("None")
+	// HintingVertical fits the outlines vertically. It is drawn as HintingFull, which is the only hinting
+	// supported by the TrueType rasterizer.
+	HintingVertical = Hinting("Vertical")
+	// HintingFull fits the outlines to the grid and rounds the advance widths to whole pixels. Small text
+	// looks sharper, while large display glyphs may be distorted.
+	HintingFull = Hinting("Full")
+)
+
+// FaceOption customizes the font face created by NewFace.
+type FaceOption func(*truetype.Options) error
+
+// FaceHinting sets the hinting of the font face. An empty hinting is HintingNone.
+func FaceHinting(h Hinting) FaceOption {
+	return func(o *truetype.Options) error {
+		switch h {
+		case "", HintingNone:
+			o.Hinting = font.HintingNone
+		case HintingVertical:
+			o.Hinting = font.HintingVertical
+		case HintingFull:
+			o.Hinting = font.HintingFull
+		default:
+			return fmt.Errorf("unknown hinting %q", h)
+		}
+		return nil
+	}
+}
+
 // LoadFromDir loads files and return FontFamily object from the specified directory.
 // The directory name is used as a family name, and all font files in it are identified as part
 // of the same font family.  Each filename must follows this `<name>-<style>.ttf`naming rule.
+// The fonts of a collection (".ttc" or ".otc") are loaded as the styles of their weights as LoadFromCollection does.
+// A color emoji font in the CBDT/CBLC format, such as NotoColorEmoji.ttf, is loaded as the emoji font
+// regardless of its name.
 func LoadFromDir(dir string) (*FontFamily, error) {
-	finfos, err := os.ReadDir(dir)
+	ffa, err := loadFromFS(os.DirFS(dir), ".", filepath.Base(dir))
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		// the paths in os.DirFS are relative to the directory
+		pe.Path = filepath.Join(dir, pe.Path)
+	}
+	return ffa, err
+}
+
+// LoadFromFS loads the font files in the directory of the file system as LoadFromDir does, e.g. from
+// an embed.FS to ship the fonts inside a binary. The directory name is used as a family name.
+func LoadFromFS(fsys fs.FS, dir string) (*FontFamily, error) {
+	return loadFromFS(fsys, dir, path.Base(dir))
+}
+
+// loadFromFS loads the font files in the directory of the file system as the font family of the name.
+func loadFromFS(fsys fs.FS, dir, name string) (*FontFamily, error) {
+	finfos, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		return nil, err
 	}
-	fs := NewFontFamily(filepath.Base(dir))
+	ffa := NewFontFamily(name)
 	for _, finfo := range finfos {
 		fn := finfo.Name()
-		ext := filepath.Ext(fn)
-		if ext != TrueTypeFontExt {
+		ext := path.Ext(fn)
+		isCollection := ext == TrueTypeCollectionExt || ext == OpenTypeCollectionExt
+		if ext != TrueTypeFontExt && !isCollection {
 			// skip non TTF file
 			continue
 		}
 
+		fb, err := fs.ReadFile(fsys, path.Join(dir, fn))
+		if err != nil {
+			return nil, err
+		}
+		if isCollection {
+			if err := ffa.loadCollection(fb); err != nil {
+				return nil, fmt.Errorf("failed to load %q: %w", fn, err)
+			}
+			continue
+		}
+		if emoji.IsColorFont(fb) {
+			if ffa.emoji, err = emoji.Parse(fb); err != nil {
+				return nil, fmt.Errorf("failed to load %q: %w", fn, err)
+			}
+			continue
+		}
+
 		name := fn[:len(fn)-len(ext)]
 		ss := strings.Split(name, "-")
 		if len(ss) != 2 {
 			return nil, fmt.Errorf("failed to parse %q name", fn)
 		}
 
-		if err := fs.LoadFont(filepath.Join(dir, fn), Style(ss[1])); err != nil {
+		if err := ffa.loadFont(fb, Style(ss[1])); err != nil {
 			return nil, err
 		}
 	}
-	return fs, nil
+	return ffa, nil
 }
 
 // NewFontFamily initialize a FontFamily object and return it.
@@ -61,12 +137,15 @@ func NewFontFamily(name string) *FontFamily {
 	return &FontFamily{
 		Name:  name,
 		fonts: make(map[Style]*truetype.Font),
+		files: make(map[Style][]byte),
 	}
 }
 
 type FontFamily struct {
 	Name  string
 	fonts map[Style]*truetype.Font
+	files map[Style][]byte
+	emoji *emoji.Font
 }
 
 // LoadFont loads TrueType font from a file.
@@ -78,6 +157,10 @@ func (fs *FontFamily) LoadFont(filename string, style Style) error {
 	if err != nil {
 		return err
 	}
+	return fs.loadFont(fb, style)
+}
+
+func (fs *FontFamily) loadFont(fb []byte, style Style) error {
 	f, err := truetype.Parse(fb)
 	if err != nil {
 		return err
@@ -86,14 +169,70 @@ func (fs *FontFamily) LoadFont(filename string, style Style) error {
 		return errors.New("parsed font is nil")
 	}
 	fs.fonts[style] = f
+	fs.files[style] = fb
 	return nil
 }
 
-// NewFace creates a new font face with size option.
-func (fs *FontFamily) NewFace(style Style, size float64) (font.Face, error) {
-	f, ok := fs.fonts[style]
+// FontFile returns the TrueType font file of the style, which is resolved as NewFace does, and the
+// resolved style, so that the font can be embedded into a document (e.g. SVG).
+func (fs *FontFamily) FontFile(style Style) ([]byte, Style, error) {
+	style, err := fs.Resolve(style)
+	if err != nil {
+		return nil, "", err
+	}
+	fb, ok := fs.files[style]
 	if !ok {
-		return nil, fmt.Errorf("this font family does not contain %q style font", style)
+		return nil, "", fmt.Errorf("the file of %q style font is not loaded", style)
+	}
+	return fb, style, nil
+}
+
+// LoadEmojiFont loads a color emoji font in the CBDT/CBLC format from a file.
+// The emoji in text are drawn with it instead of the font of the style.
+func (fs *FontFamily) LoadEmojiFont(filename string) error {
+	f, err := emoji.LoadFromFile(filename)
+	if err != nil {
+		return err
+	}
+	fs.emoji = f
+	return nil
+}
+
+// Emoji returns the color emoji font of this font family, or nil if it is not loaded.
+func (fs *FontFamily) Emoji() *emoji.Font {
+	return fs.emoji
+}
+
+// HasStyle reports whether this font family contains the style font exactly, without falling back to
+// the nearest weight.
+func (fs *FontFamily) HasStyle(style Style) bool {
+	_, ok := fs.fonts[style]
+	return ok
+}
+
+// HasGlyph reports whether the font of the style, which is resolved as NewFace does, has the glyph of the rune.
+func (fs *FontFamily) HasGlyph(style Style, r rune) bool {
+	style, err := fs.Resolve(style)
+	if err != nil {
+		return false
+	}
+	return fs.fonts[style].Index(r) != 0
+}
+
+// NewFace creates a new font face with size option. The font of the nearest weight is used if this font
+// family does not contain the style, as Resolve chooses it.
+// Glyphs are not hinted by default, so that the same text is always rendered into the same pixels.
+func (fs *FontFamily) NewFace(style Style, size float64, opts ...FaceOption) (font.Face, error) {
+	style, err := fs.Resolve(style)
+	if err != nil {
+		return nil, err
+	}
+	f := fs.fonts[style]
+	o := &truetype.Options{Size: size, Hinting: font.HintingNone}
+	for _, fn := range opts {
+		if err := fn(o); err != nil {
+			return nil, err
+		}
 	}
-	return truetype.NewFace(f, &truetype.Options{Size: size}), nil
+	return truetype.NewFace(f, o), nil
 }
@@ -0,0 +1,162 @@
+package fontfamily
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestFaceHinting(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		hinting   Hinting
+		expect    font.Hinting
+		expectErr bool
+	}{
+		{desc: "Default", hinting: "", expect: font.HintingNone},
+		{desc: "None", hinting: HintingNone, expect: font.HintingNone},
+		{desc: "Vertical", hinting: HintingVertical, expect: font.HintingVertical},
+		{desc: "Full", hinting: HintingFull, expect: font.HintingFull},
+		{desc: "Unknown", hinting: "Slight", expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			o := &truetype.Options{}
+			err := FaceHinting(tc.hinting)(o)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error for unknown hinting")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.Hinting != tc.expect {
+				t.Fatalf("unexpected hinting: got=%v, want=%v", o.Hinting, tc.expect)
+			}
+		})
+	}
+}
+
+func TestNewFaceHinting(t *testing.T) {
+	ffa := NewFontFamily("Go")
+	if err := ffa.loadFont(goregular.TTF, Regular); err != nil {
+		t.Fatal(err)
+	}
+	advance := func(t *testing.T, opts ...FaceOption) (adv int) {
+		t.Helper()
+		ff, err := ffa.NewFace(Regular, 13.3, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, _ := ff.GlyphAdvance('a')
+		return int(a)
+	}
+
+	// full hinting rounds the advance widths to whole pixels
+	if adv := advance(t); adv%64 == 0 {
+		t.Fatalf("advance is rounded without hinting: %d", adv)
+	}
+	if adv := advance(t, FaceHinting(HintingFull)); adv%64 != 0 {
+		t.Fatalf("advance is not rounded with full hinting: %d", adv)
+	}
+	if _, err := ffa.NewFace(Regular, 13.3, FaceHinting("Slight")); err == nil {
+		t.Fatal("expected an error for unknown hinting")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	f, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := NewFontFamily("Go")
+	fs.fonts[Regular] = f
+	fs.fonts[Bold] = f
+	fs.fonts["Condensed"] = f
+
+	if got, want := fs.Styles(), []Style{Regular, Bold, "Condensed"}; !slices.Equal(got, want) {
+		t.Fatalf("unexpected styles: got=%q, want=%q", got, want)
+	}
+
+	m, err := fs.Metrics(Regular, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(m.XHeight > 0 && m.XHeight < m.CapHeight && m.CapHeight < m.Ascent) {
+		t.Fatalf("heights are not ordered: %+v", m)
+	}
+	if m.Descent <= 0 || m.LineHeight < m.Ascent {
+		t.Fatalf("unexpected metrics: %+v", m)
+	}
+	// the metrics are proportional to the size
+	half, err := fs.Metrics(Regular, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := m.CapHeight - 2*half.CapHeight; d < -1 || d > 1 {
+		t.Fatalf("cap height is not proportional to the size: 64pt=%v, 32pt=%v", m.CapHeight, half.CapHeight)
+	}
+
+	if _, err := fs.Metrics("Wide", 64); err == nil {
+		t.Fatal("expected an error for the missing style")
+	}
+}
+
+func TestLoadFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fonts/Go/Go-Regular.ttf": {Data: goregular.TTF},
+		"fonts/Go/Go-Bold.ttf":    {Data: goregular.TTF},
+		"fonts/Go/README.md":      {Data: []byte("not a font")},
+		"fonts/Broken/Broken.ttf": {Data: goregular.TTF},
+	}
+	ffa, err := LoadFromFS(fsys, "fonts/Go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ffa.Name != "Go" {
+		t.Fatalf("unexpected family name: %q", ffa.Name)
+	}
+	if got := ffa.Styles(); !slices.Equal(got, []Style{Regular, Bold}) {
+		t.Fatalf("unexpected styles: %v", got)
+	}
+	if fb, _, err := ffa.FontFile(Bold); err != nil || len(fb) != len(goregular.TTF) {
+		t.Fatalf("font file is not kept: %d bytes, %v", len(fb), err)
+	}
+
+	if _, err := LoadFromFS(fsys, "fonts/Broken"); err == nil {
+		t.Fatal("expected an error for a font file without style")
+	}
+	if _, err := LoadFromFS(fsys, "fonts/Missing"); err == nil {
+		t.Fatal("expected an error for a missing directory")
+	}
+}
+
+func TestHasGlyph(t *testing.T) {
+	ffa := NewFontFamily("Go")
+	if err := ffa.loadFont(goregular.TTF, Regular); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		desc   string
+		style  Style
+		r      rune
+		expect bool
+	}{
+		{desc: "Latin", style: Regular, r: 'A', expect: true},
+		{desc: "CJK", style: Regular, r: '字', expect: false},
+		{desc: "Fallback style", style: Bold, r: 'A', expect: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := ffa.HasGlyph(tc.style, tc.r); got != tc.expect {
+				t.Fatalf("unexpected result: got=%v, want=%v", got, tc.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package fontfamily
+
+import (
+	"cmp"
+	"slices"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Styles returns the styles of the fonts in this font family from the thinnest to the boldest, with the
+// italic after the upright of the same weight. The styles with unknown weights follow them in alphabetical order.
+func (fs *FontFamily) Styles() []Style {
+	styles := make([]Style, 0, len(fs.fonts))
+	for style := range fs.fonts {
+		styles = append(styles, style)
+	}
+	slices.SortFunc(styles, func(a, b Style) int {
+		wa, ia, oka := a.Weight()
+		wb, ib, okb := b.Weight()
+		switch {
+		case !oka && !okb:
+			return cmp.Compare(a, b)
+		case !oka:
+			return 1
+		case !okb:
+			return -1
+		}
+		return cmp.Or(cmp.Compare(wa, wb), cmp.Compare(boolInt(ia), boolInt(ib)), cmp.Compare(a, b))
+	})
+	return styles
+}
+
+// Metrics is the vertical metrics(px) of a font face.
+// A text drawn at a start point has the baseline of its first line at LineHeight below the point,
+// and the capital letters reach CapHeight above the baseline.
+type Metrics struct {
+	// Ascent is the distance from the baseline to the top of the face.
+	Ascent float64
+	// Descent is the distance from the baseline to the bottom of the face.
+	Descent float64
+	// LineHeight is the recommended distance between the baselines, which is the height of the first line.
+	LineHeight float64
+	// CapHeight is the height of the capital letters measured by "H", or 0 if the font does not have it.
+	CapHeight float64
+	// XHeight is the height of the lowercase letters measured by "x", or 0 if the font does not have it.
+	XHeight float64
+}
+
+// Metrics returns the vertical metrics(px) of the style font in the size, hinted by the options as NewFace is.
+func (fs *FontFamily) Metrics(style Style, size float64, opts ...FaceOption) (Metrics, error) {
+	face, err := fs.NewFace(style, size, opts...)
+	if err != nil {
+		return Metrics{}, err
+	}
+	defer face.Close()
+	return faceMetrics(face), nil
+}
+
+func faceMetrics(face font.Face) Metrics {
+	m := face.Metrics()
+	return Metrics{
+		Ascent:     toFloat(m.Ascent),
+		Descent:    toFloat(m.Descent),
+		LineHeight: toFloat(m.Height),
+		CapHeight:  glyphHeight(face, 'H'),
+		XHeight:    glyphHeight(face, 'x'),
+	}
+}
+
+// glyphHeight returns the height of the glyph above the baseline.
+func glyphHeight(face font.Face, r rune) float64 {
+	b, _, ok := face.GlyphBounds(r)
+	if !ok {
+		return 0
+	}
+	return toFloat(-b.Min.Y)
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func toFloat(x fixed.Int26_6) float64 {
+	return float64(x) / 64
+}
@@ -0,0 +1,133 @@
+package fontfamily
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The named styles of the weights between the ones above, which follow the CSS font-weight names.
+const (
+	ExtraLight = "ExtraLight"
+	SemiBold   = "SemiBold"
+	ExtraBold  = "ExtraBold"
+)
+
+// italicSuffix is appended to the style of the italic fonts, e.g. "BoldItalic". The italic of Regular is "Italic".
+const italicSuffix = "Italic"
+
+// styleWeights are the numeric weights of the named styles.
+var styleWeights = map[Style]int{
+	Thin:       100,
+	ExtraLight: 200,
+	Light:      300,
+	Regular:    400,
+	Medium:     500,
+	SemiBold:   600,
+	Bold:       700,
+	ExtraBold:  800,
+	Black:      900,
+}
+
+// Weight returns the numeric weight (100-900) of the style and whether it is italic. The style is a named
+// style, a numeric weight (e.g. "600"), or either of them followed by "Italic" (e.g. "BoldItalic" or
+// "600Italic"); "Italic" alone is the italic of Regular. It reports false for the other styles.
+func (s Style) Weight() (weight int, italic, ok bool) {
+	name := string(s)
+	if name == italicSuffix {
+		return styleWeights[Regular], true, true
+	}
+	if n, found := strings.CutSuffix(name, italicSuffix); found {
+		name, italic = n, true
+	}
+	if w, ok := styleWeights[Style(name)]; ok {
+		return w, italic, true
+	}
+	if w, err := strconv.Atoi(name); err == nil && w >= 100 && w <= 900 {
+		return w, italic, true
+	}
+	return 0, false, false
+}
+
+// WeightStyle returns the style of the numeric weight, which is the named style of the weight if it has one
+// (e.g. 600 is SemiBold), and the italic of it if italic is set.
+func WeightStyle(weight int, italic bool) Style {
+	name := strconv.Itoa(weight)
+	for s, w := range styleWeights {
+		if w == weight {
+			name = string(s)
+		}
+	}
+	if !italic {
+		return Style(name)
+	}
+	if name == Regular {
+		return italicSuffix
+	}
+	return Style(name + italicSuffix)
+}
+
+// Resolve returns the style of the font in this font family to draw the style with. The style itself is used
+// if the family contains it. Otherwise the font of the nearest weight is chosen by the CSS font matching
+// rules, preferring the fonts of the same slant: a lighter weight than 400 falls back to the lighter fonts first,
+// a bolder weight than 500 to the bolder fonts first, and 400 and 500 try each other first.
+func (fs *FontFamily) Resolve(style Style) (Style, error) {
+	if _, ok := fs.fonts[style]; ok {
+		return style, nil
+	}
+	notFound := fmt.Errorf("this font family does not contain %q style font", style)
+	weight, italic, ok := style.Weight()
+	if !ok {
+		return "", notFound
+	}
+
+	var best Style
+	var bestRank [3]int
+	for s := range fs.fonts {
+		w, i, ok := s.Weight()
+		if !ok {
+			continue
+		}
+		rank := [3]int{0, 0, 0}
+		if i != italic {
+			rank[0] = 1
+		}
+		rank[1], rank[2] = weightRank(weight, w)
+		if best == "" || cmp.Or(cmp.Compare(rank[0], bestRank[0]), cmp.Compare(rank[1], bestRank[1]),
+			cmp.Compare(rank[2], bestRank[2]), cmp.Compare(s, best)) < 0 {
+			best, bestRank = s, rank
+		}
+	}
+	if best == "" {
+		return "", notFound
+	}
+	return best, nil
+}
+
+// weightRank returns the order of the weight w to fall back to for the desired weight, as the group
+// and the distance in it, which are smaller for the preferred weights.
+func weightRank(desired, w int) (group, distance int) {
+	d := w - desired
+	switch {
+	case desired >= 400 && desired <= 500:
+		switch {
+		case d >= 0 && w <= 500:
+			return 0, d
+		case d < 0:
+			return 1, -d
+		default:
+			return 2, d
+		}
+	case desired < 400:
+		if d <= 0 {
+			return 0, -d
+		}
+		return 1, d
+	default:
+		if d >= 0 {
+			return 0, d
+		}
+		return 1, -d
+	}
+}
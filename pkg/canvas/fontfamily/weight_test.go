@@ -0,0 +1,115 @@
+package fontfamily
+
+import (
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestStyleWeight(t *testing.T) {
+	testCases := []struct {
+		style        Style
+		expectWeight int
+		expectItalic bool
+		expectOK     bool
+	}{
+		{style: Regular, expectWeight: 400, expectOK: true},
+		{style: SemiBold, expectWeight: 600, expectOK: true},
+		{style: Black, expectWeight: 900, expectOK: true},
+		{style: "Italic", expectWeight: 400, expectItalic: true, expectOK: true},
+		{style: "BoldItalic", expectWeight: 700, expectItalic: true, expectOK: true},
+		{style: "350", expectWeight: 350, expectOK: true},
+		{style: "600Italic", expectWeight: 600, expectItalic: true, expectOK: true},
+		{style: "1000"},
+		{style: "Wide"},
+		{style: "WideItalic"},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.style), func(t *testing.T) {
+			w, i, ok := tc.style.Weight()
+			if w != tc.expectWeight || i != tc.expectItalic || ok != tc.expectOK {
+				t.Fatalf("unexpected weight: got=(%d, %v, %v), want=(%d, %v, %v)", w, i, ok, tc.expectWeight, tc.expectItalic, tc.expectOK)
+			}
+		})
+	}
+}
+
+func TestWeightStyle(t *testing.T) {
+	testCases := []struct {
+		weight int
+		italic bool
+		expect Style
+	}{
+		{weight: 400, expect: Regular},
+		{weight: 400, italic: true, expect: "Italic"},
+		{weight: 600, expect: SemiBold},
+		{weight: 700, italic: true, expect: "BoldItalic"},
+		{weight: 350, expect: "350"},
+		{weight: 350, italic: true, expect: "350Italic"},
+	}
+	for _, tc := range testCases {
+		if got := WeightStyle(tc.weight, tc.italic); got != tc.expect {
+			t.Errorf("WeightStyle(%d, %v) returns unexpected style: got=%q, want=%q", tc.weight, tc.italic, got, tc.expect)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	f, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFamily := func(styles ...Style) *FontFamily {
+		fs := NewFontFamily("Go")
+		for _, s := range styles {
+			fs.fonts[s] = f
+		}
+		return fs
+	}
+	full := newFamily(Regular, Medium, Bold, "Italic", "BoldItalic", "Condensed")
+	sparse := newFamily(Light, Bold)
+
+	testCases := []struct {
+		desc      string
+		fs        *FontFamily
+		style     Style
+		expect    Style
+		expectErr bool
+	}{
+		{desc: "Exact style", fs: full, style: Bold, expect: Bold},
+		{desc: "Exact style of unknown weight", fs: full, style: "Condensed", expect: "Condensed"},
+		{desc: "Bolder weight than 500 falls back to bolder", fs: full, style: SemiBold, expect: Bold},
+		{desc: "Numeric weight", fs: full, style: "600", expect: Bold},
+		{desc: "Boldest weight falls back to lighter", fs: full, style: Black, expect: Bold},
+		{desc: "Lighter weight than 400 falls back to heavier without lighter", fs: full, style: Thin, expect: Regular},
+		{desc: "Weight between 400 and 500 tries up to 500 first", fs: full, style: "450", expect: Medium},
+		{desc: "Lighter weight than 400 falls back to lighter", fs: sparse, style: ExtraLight, expect: Light},
+		{desc: "500 falls back to lighter first", fs: sparse, style: Medium, expect: Light},
+		{desc: "Italic of the nearest weight", fs: full, style: "SemiBoldItalic", expect: "BoldItalic"},
+		{desc: "Italic of 500 falls back to the regular italic", fs: full, style: "MediumItalic", expect: "Italic"},
+		{desc: "Upright without italic", fs: sparse, style: "BoldItalic", expect: Bold},
+		{desc: "Unknown style", fs: full, style: "Wide", expectErr: true},
+		{desc: "No fonts of known weights", fs: newFamily("Condensed"), style: Bold, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tc.fs.Resolve(tc.style)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got=%q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.expect {
+				t.Fatalf("unexpected style: got=%q, want=%q", got, tc.expect)
+			}
+			if _, err := tc.fs.NewFace(tc.style, 16); err != nil {
+				t.Fatalf("NewFace does not fall back: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/font"
+)
+
+// GammaCorrectText enables blending the coverage of the glyphs in the linear color space instead of the
+// sRGB space, which reduces the fringes and the thinning of the text edges over colored backgrounds.
+// It is disabled by default, and makes drawing texts slower. Texts with color emoji are drawn as usual.
+func GammaCorrectText(enabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.gammaCorrect = enabled
+		return nil
+	}
+}
+
+// linearLUTSize is the number of the entries of the table to encode linear intensities into sRGB.
+const linearLUTSize = 4096
+
+var (
+	// srgbToLinear decodes the 8-bit sRGB values into linear intensities (0-1).
+	srgbToLinear [256]float64
+	// linearToSRGB encodes the linear intensities quantized into the table size into 8-bit sRGB values.
+	linearToSRGB [linearLUTSize]uint8
+)
+
+func init() {
+	for i := range srgbToLinear {
+		v := float64(i) / 0xff
+		if v <= 0.04045 {
+			srgbToLinear[i] = v / 12.92
+		} else {
+			srgbToLinear[i] = math.Pow((v+0.055)/1.055, 2.4)
+		}
+	}
+	for i := range linearToSRGB {
+		v := float64(i) / (linearLUTSize - 1)
+		if v <= 0.0031308 {
+			v *= 12.92
+		} else {
+			v = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+		linearToSRGB[i] = uint8(math.Round(v * 0xff))
+	}
+}
+
+// encodeLinear encodes the linear intensity (0-1) into the 8-bit sRGB value.
+func encodeLinear(v float64) uint8 {
+	return linearToSRGB[int(min(max(v, 0), 1)*(linearLUTSize-1)+0.5)]
+}
+
+// drawGlyphs draws the string at the dot of the drawer of this canvas in its color, and advances the dot.
+// The glyphs are blended in the linear color space if the gamma correction is enabled. Emoji are drawn in
+// their colors if color is set, otherwise in the color of the drawer.
+func (c *Canvas) drawGlyphs(s string, color bool) {
+	src := uniformColor(c.fdr.Src)
+	if !c.gammaCorrect || src == nil || (color && c.emoji != nil) {
+		c.drawSpaced(c.fdr, s, color)
+		return
+	}
+
+	// render the coverage of the glyphs into an alpha mask
+	dot := c.fdr.Dot
+	b, _ := font.BoundString(c.fdr.Face, s)
+	b.Max.X += c.advance(s) - c.fdr.MeasureString(s)
+	r := image.Rect(
+		(dot.X + b.Min.X).Floor(), (dot.Y + b.Min.Y).Floor(),
+		(dot.X + b.Max.X).Ceil(), (dot.Y + b.Max.Y).Ceil(),
+	)
+	mask := image.NewAlpha(r)
+	d := &font.Drawer{Dst: mask, Src: image.Opaque, Face: c.fdr.Face, Dot: dot}
+	c.drawSpaced(d, s, false)
+	c.fdr.Dot = d.Dot
+
+	blendLinear(c.dst, r, src, mask)
+}
+
+// blendLinear blends the color over the image in the rectangle by the coverage of the mask, in the linear
+// color space.
+func blendLinear(dst *image.RGBA, r image.Rectangle, src color.Color, mask *image.Alpha) {
+	r = r.Intersect(dst.Bounds()).Intersect(mask.Bounds())
+	sc := color.NRGBAModel.Convert(src).(color.NRGBA)
+	sa := float64(sc.A) / 0xff
+	sr, sg, sb := srgbToLinear[sc.R], srgbToLinear[sc.G], srgbToLinear[sc.B]
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cov := mask.AlphaAt(x, y).A
+			if cov == 0 {
+				continue
+			}
+			a := float64(cov) / 0xff * sa
+			i := dst.PixOffset(x, y)
+			p := dst.Pix[i : i+4 : i+4]
+
+			// the destination is premultiplied, so it is unpremultiplied before decoded
+			da := float64(p[3]) / 0xff
+			var dr, dg, db float64
+			if p[3] != 0 {
+				dr = srgbToLinear[unpremultiply(p[0], p[3])] * da
+				dg = srgbToLinear[unpremultiply(p[1], p[3])] * da
+				db = srgbToLinear[unpremultiply(p[2], p[3])] * da
+			}
+			oa := a + da*(1-a)
+			or := (sr*a + dr*(1-a)) / oa
+			og := (sg*a + dg*(1-a)) / oa
+			ob := (sb*a + db*(1-a)) / oa
+
+			p[0] = uint8(math.Round(float64(encodeLinear(or)) * oa))
+			p[1] = uint8(math.Round(float64(encodeLinear(og)) * oa))
+			p[2] = uint8(math.Round(float64(encodeLinear(ob)) * oa))
+			p[3] = uint8(math.Round(oa * 0xff))
+		}
+	}
+}
+
+// unpremultiply returns the straight value of the premultiplied value with the alpha.
+func unpremultiply(v, a uint8) uint8 {
+	if a == 0xff {
+		return v
+	}
+	return uint8(min(0xff, (uint32(v)*0xff+uint32(a)/2)/uint32(a)))
+}
@@ -0,0 +1,70 @@
+package canvas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestGammaCorrectText(t *testing.T) {
+	draw := func(t *testing.T, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 200, 60)
+		opts = append([]TextDrawOption{FontFace(newTestFace(t, 24)), FgColorC(black)}, opts...)
+		if err := c.DrawTextAtPoint("Gamma", config.Point{X: 10, Y: 10}, opts...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	plain := draw(t)
+	if !sameImage(draw(t, GammaCorrectText(false)).Image(), plain.Image()) {
+		t.Fatal("disabled gamma correction changes the text")
+	}
+
+	gamma := draw(t, GammaCorrectText(true)).Image()
+	var changed bool
+	for i := 0; i < len(gamma.Pix); i += 4 {
+		g, p := int(gamma.Pix[i]), int(plain.Image().Pix[i])
+		// the partially covered pixels of black text on white are lighter in the linear color space
+		if g+1 < p {
+			t.Fatalf("pixel %d is darker with gamma correction: got=%d, plain=%d", i/4, g, p)
+		}
+		if p == 0 && g != 0 {
+			t.Fatalf("fully covered pixel %d is not the text color: %d", i/4, g)
+		}
+		changed = changed || g != p
+	}
+	if !changed {
+		t.Fatal("gamma correction does not change the text edges")
+	}
+}
+
+func TestEncodeLinear(t *testing.T) {
+	for i := range 256 {
+		if got := encodeLinear(srgbToLinear[i]); got != uint8(i) {
+			t.Fatalf("sRGB value %d is not restored: got=%d", i, got)
+		}
+	}
+}
+
+func BenchmarkGammaCorrectText(b *testing.B) {
+	ffa := testfont.New(b)
+	for _, enabled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("enabled=%v", enabled), func(b *testing.B) {
+			c := newTestCanvas(b, 1200, 630)
+			opts := []TextDrawOption{
+				FontFaceFromFFA(ffa, fontfamily.Regular, 48),
+				MaxWidth(1000),
+				GammaCorrectText(enabled),
+			}
+			b.ResetTimer()
+			for range b.N {
+				if err := c.DrawTextAtPoint("Generate TwitterCard(OGP) images for your Hugo posts", config.Point{X: 100, Y: 100}, opts...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
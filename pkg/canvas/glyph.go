@@ -0,0 +1,73 @@
+package canvas
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/emoji"
+)
+
+// MissingGlyphError is a character of a text which neither the font nor the emoji font has the glyph of.
+// It is drawn as the invisible or box glyph (tofu) of the font.
+type MissingGlyphError struct {
+	Rune rune
+	Text string
+}
+
+func (e *MissingGlyphError) Error() string {
+	return fmt.Sprintf("the font does not have the glyph of %U %q in %q", e.Rune, e.Rune, e.Text)
+}
+
+// ReportMissingGlyphs makes this canvas call the handler with each character of the texts drawn by
+// DrawTextAtPoint, DrawTextInBox, DrawBoxTexts and DrawRotatedText which the fonts do not have, before the
+// text is drawn. Each character is reported once per text, and the draw call fails with the error of the
+// handler if it returns one, e.g. the MissingGlyphError itself to fail strictly. The controls and the
+// invisible format characters such as ZWJ are not reported. A nil handler stops the reporting.
+// The glyphs are looked up in the font of the FontFamily, or by the GlyphAdvance of the font face set by FontFace.
+func (c *Canvas) ReportMissingGlyphs(handler func(*MissingGlyphError) error) {
+	c.missingGlyph = handler
+}
+
+// checkGlyphs reports the characters of the text which the fonts do not have.
+func (c *Canvas) checkGlyphs(text string) error {
+	if c.missingGlyph == nil {
+		return nil
+	}
+	ffa, style := c.ffa, c.fontStyle
+	if c.autoFit != nil {
+		ffa, style = c.autoFit.ffa, c.autoFit.style
+	}
+	face := c.fdr.Face
+	if ffa == nil && face == nil {
+		return nil
+	}
+
+	seen := map[rune]bool{}
+	gr := uniseg.NewGraphemes(text)
+	for gr.Next() {
+		if r, ok := emoji.Rune(gr.Str()); ok && c.emoji != nil && c.emoji.HasGlyph(r) {
+			continue
+		}
+		for _, r := range gr.Runes() {
+			if seen[r] || unicode.In(r, unicode.Cc, unicode.Cf, unicode.Variation_Selector) {
+				continue
+			}
+			seen[r] = true
+			var ok bool
+			if ffa != nil {
+				ok = ffa.HasGlyph(style, r)
+			} else {
+				_, ok = face.GlyphAdvance(r)
+			}
+			if ok {
+				continue
+			}
+			if err := c.missingGlyph(&MissingGlyphError{Rune: r, Text: text}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package canvas
+
+import (
+	"errors"
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestReportMissingGlyphs(t *testing.T) {
+	ffa := testfont.New(t)
+	testCases := []struct {
+		desc   string
+		text   string
+		expect []rune
+	}{
+		{desc: "All glyphs", text: "Hello, world"},
+		{desc: "CJK", text: "Go 言語の文字列", expect: []rune{'言', '語', 'の', '文', '字', '列'}},
+		{desc: "Once per text", text: "字 and 字", expect: []rune{'字'}},
+		{desc: "Controls and format characters", text: "a\tb‍c­"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 400, 100)
+			var got []rune
+			c.ReportMissingGlyphs(func(e *MissingGlyphError) error {
+				if e.Text != tc.text {
+					t.Errorf("unexpected text: got=%q, want=%q", e.Text, tc.text)
+				}
+				got = append(got, e.Rune)
+				return nil
+			})
+			if err := c.DrawTextAtPoint(tc.text, config.Point{X: 0, Y: 0}, FontFaceFromFFA(ffa, fontfamily.Regular, 20)); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tc.expect) {
+				t.Fatalf("unexpected missing glyphs: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestReportMissingGlyphsFailsDraws(t *testing.T) {
+	ffa := testfont.New(t)
+	face := FontFaceFromFFA(ffa, fontfamily.Regular, 20)
+	strict := func(e *MissingGlyphError) error { return e }
+	testCases := []struct {
+		desc string
+		draw func(c *Canvas) error
+	}{
+		{desc: "DrawTextAtPoint", draw: func(c *Canvas) error {
+			return c.DrawTextAtPoint("タイトル", config.Point{}, face)
+		}},
+		{desc: "DrawTextInBox", draw: func(c *Canvas) error {
+			return c.DrawTextInBox("タイトル", image.Rect(0, 0, 200, 100), face)
+		}},
+		{desc: "DrawBoxTexts", draw: func(c *Canvas) error {
+			return c.DrawBoxTexts([]string{"Go", "タグ"}, config.Point{}, face, BgColor(image.NewUniform(black)))
+		}},
+		{desc: "DrawRotatedText", draw: func(c *Canvas) error {
+			return c.DrawRotatedText("タイトル", config.Point{X: 100, Y: 50}, 30, face)
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 200, 100)
+			c.ReportMissingGlyphs(strict)
+			var mge *MissingGlyphError
+			if err := tc.draw(c); !errors.As(err, &mge) {
+				t.Fatalf("expected a missing glyph error: %v", err)
+			}
+			if mge.Rune != 'タ' {
+				t.Fatalf("unexpected rune: got=%q, want=%q", mge.Rune, 'タ')
+			}
+
+			c.ReportMissingGlyphs(nil)
+			if err := tc.draw(c); err != nil {
+				t.Fatalf("expected no error without the handler: %v", err)
+			}
+		})
+	}
+}
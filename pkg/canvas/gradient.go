@@ -0,0 +1,47 @@
+package canvas
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// CreateCanvasWithGradient creates a canvas filled with a linear gradient from one color to another.
+// The angle is in degrees clockwise from the x-axis, so 0 paints from left to right and 90 from top to
+// bottom. Colors parsed by Hex can be passed as they are.
+func CreateCanvasWithGradient(width, height int, from, to color.Color, angle float64) (*Canvas, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("canvas size must be positive: %dx%d", width, height)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	fillLinearGradient(dst, from, to, angle)
+	return CreateCanvasFromImage(dst)
+}
+
+// fillLinearGradient fills dst so that the first and last pixels along the angle get the end colors.
+func fillLinearGradient(dst *image.RGBA, from, to color.Color, angle float64) {
+	fr, fg, fb, fa := from.RGBA()
+	tr, tg, tb, ta := to.RGBA()
+	lerp := func(a, b uint32, t float64) uint8 {
+		return uint8((float64(a)+(float64(b)-float64(a))*t)/257 + 0.5)
+	}
+
+	rad := angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	b := dst.Bounds()
+	cx, cy := float64(b.Min.X+b.Max.X)/2, float64(b.Min.Y+b.Max.Y)/2
+	// length of the gradient line so that it spans from corner to corner
+	l := math.Abs(float64(b.Dx()-1)*cos) + math.Abs(float64(b.Dy()-1)*sin)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			t := 0.5
+			if l > 0 {
+				t = ((float64(x)+0.5-cx)*cos+(float64(y)+0.5-cy)*sin)/l + 0.5
+			}
+			t = min(max(t, 0), 1)
+			dst.SetRGBA(x, y, color.RGBA{lerp(fr, tr, t), lerp(fg, tg, t), lerp(fb, tb, t), lerp(fa, ta, t)})
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCreateCanvasWithGradient(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	testCases := []struct {
+		desc   string
+		angle  float64
+		expect map[image.Point]color.RGBA
+	}{
+		{
+			desc:  "Zero degrees paints from left to right",
+			angle: 0,
+			expect: map[image.Point]color.RGBA{
+				{0, 0}:   red,
+				{0, 49}:  red,
+				{99, 0}:  blue,
+				{99, 49}: blue,
+			},
+		},
+		{
+			desc:  "Ninety degrees paints from top to bottom",
+			angle: 90,
+			expect: map[image.Point]color.RGBA{
+				{0, 0}:   red,
+				{99, 0}:  red,
+				{0, 49}:  blue,
+				{99, 49}: blue,
+			},
+		},
+		{
+			desc:  "Forty-five degrees paints from corner to corner",
+			angle: 45,
+			expect: map[image.Point]color.RGBA{
+				{0, 0}:   red,
+				{99, 49}: blue,
+			},
+		},
+		{
+			desc:  "One hundred eighty degrees reverses the colors",
+			angle: 180,
+			expect: map[image.Point]color.RGBA{
+				{0, 0}:  blue,
+				{99, 0}: red,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := CreateCanvasWithGradient(100, 50, red, blue, tc.angle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for p, want := range tc.expect {
+				if got := c.dst.RGBAAt(p.X, p.Y); got != want {
+					t.Fatalf("unexpected color at %v: got=%v, want=%v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateCanvasWithGradientHex(t *testing.T) {
+	from, err := Hex("#000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := Hex("#FFFFFF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := CreateCanvasWithGradient(3, 1, from, to, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := color.RGBA{128, 128, 128, 255}
+	if got := c.dst.RGBAAt(1, 0); got != want {
+		t.Fatalf("unexpected middle color: got=%v, want=%v", got, want)
+	}
+
+	if _, err := CreateCanvasWithGradient(0, 10, from, to, 0); err == nil {
+		t.Fatal("expected an error for an empty canvas")
+	}
+}
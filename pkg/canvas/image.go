@@ -0,0 +1,127 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"net/http"
+	"strings"
+	"time"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/vector"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+type imageOptions struct {
+	width  int
+	height int
+	circle bool
+}
+
+// ImageDrawOption configures how the image is drawn on the canvas.
+type ImageDrawOption func(*imageOptions) error
+
+// ImageWidth sets the width(px) of the drawn image.
+// If the height is not set, it is scaled to keep the aspect ratio.
+func ImageWidth(px int) ImageDrawOption {
+	return func(o *imageOptions) error {
+		if px < 0 {
+			return fmt.Errorf("image width must not be negative: %d", px)
+		}
+		o.width = px
+		return nil
+	}
+}
+
+// ImageHeight sets the height(px) of the drawn image.
+// If the width is not set, it is scaled to keep the aspect ratio.
+func ImageHeight(px int) ImageDrawOption {
+	return func(o *imageOptions) error {
+		if px < 0 {
+			return fmt.Errorf("image height must not be negative: %d", px)
+		}
+		o.height = px
+		return nil
+	}
+}
+
+// CircleClip clips the image to the circle inscribed in it (e.g. for round avatars).
+func CircleClip(enabled bool) ImageDrawOption {
+	return func(o *imageOptions) error {
+		o.circle = enabled
+		return nil
+	}
+}
+
+// DrawImageAtPoint draws the image over this canvas. The start point is the top-left corner of the image.
+func (c *Canvas) DrawImageAtPoint(img image.Image, start config.Point, opts ...ImageDrawOption) error {
+	o := &imageOptions{}
+	for _, f := range opts {
+		if err := f(o); err != nil {
+			return err
+		}
+	}
+
+	sb := img.Bounds()
+	if sb.Empty() {
+		return errors.New("image is empty")
+	}
+	w, h := o.width, o.height
+	switch {
+	case w == 0 && h == 0:
+		w, h = sb.Dx(), sb.Dy()
+	case h == 0:
+		h = max(1, sb.Dy()*w/sb.Dx())
+	case w == 0:
+		w = max(1, sb.Dx()*h/sb.Dy())
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(src, src.Bounds(), img, sb, xdraw.Src, nil)
+
+	r := src.Bounds().Add(image.Pt(start.X, start.Y))
+	if !o.circle {
+		draw.Draw(c.dst, r, src, image.Point{}, draw.Over)
+		return nil
+	}
+	draw.DrawMask(c.dst, r, src, image.Point{}, ellipseMask(w, h), image.Point{}, draw.Over)
+	return nil
+}
+
+// ellipseMask returns an anti-aliased mask of the ellipse inscribed in a w x h rectangle.
+func ellipseMask(w, h int) *image.Alpha {
+	rx, ry := float32(w)/2, float32(h)/2
+	z := vector.NewRasterizer(w, h)
+	z.MoveTo(rx*2, ry)
+	z.CubeTo(rx*2, ry+ry*kappa, rx+rx*kappa, ry*2, rx, ry*2)
+	z.CubeTo(rx-rx*kappa, ry*2, 0, ry+ry*kappa, 0, ry)
+	z.CubeTo(0, ry-ry*kappa, rx-rx*kappa, 0, rx, 0)
+	z.CubeTo(rx+rx*kappa, 0, rx*2, ry-ry*kappa, rx*2, ry)
+	z.ClosePath()
+	mask := image.NewAlpha(image.Rect(0, 0, w, h))
+	z.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	return mask
+}
+
+// LoadImage loads an image from a file path or an HTTP(S) URL.
+// Supported image types are JPEG and PNG.
+func LoadImage(src string, opts ...LoadOption) (image.Image, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return LoadFromFile(src, opts...)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", src, resp.Status)
+	}
+	img, _, err := Decode(resp.Body, opts...)
+	return img, err
+}
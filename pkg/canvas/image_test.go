@@ -0,0 +1,68 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestDrawImageAtPoint(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	src := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	draw.Draw(src, src.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+
+	testCases := []struct {
+		desc   string
+		opts   []ImageDrawOption
+		inside []image.Point
+		corner image.Point
+		expect color.RGBA
+	}{
+		{
+			desc:   "Native size",
+			inside: []image.Point{{10, 10}, {29, 19}},
+			corner: image.Pt(30, 20),
+			expect: white,
+		},
+		{
+			desc:   "Height keeps the aspect ratio from the width",
+			opts:   []ImageDrawOption{ImageWidth(40)},
+			inside: []image.Point{{10, 10}, {49, 29}},
+			corner: image.Pt(49, 30),
+			expect: white,
+		},
+		{
+			desc:   "Circle clip keeps the corners",
+			opts:   []ImageDrawOption{ImageWidth(40), ImageHeight(40), CircleClip(true)},
+			inside: []image.Point{{30, 30}, {12, 30}},
+			corner: image.Pt(11, 11),
+			expect: white,
+		},
+		{
+			desc:   "Square corners without circle clip",
+			opts:   []ImageDrawOption{ImageWidth(40), ImageHeight(40)},
+			inside: []image.Point{{30, 30}},
+			corner: image.Pt(11, 11),
+			expect: red,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 100, 100)
+			if err := c.DrawImageAtPoint(src, config.Point{X: 10, Y: 10}, tc.opts...); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range tc.inside {
+				if got := c.dst.RGBAAt(p.X, p.Y); got != red {
+					t.Fatalf("unexpected color at %v: got=%v, want=%v", p, got, red)
+				}
+			}
+			if got := c.dst.RGBAAt(tc.corner.X, tc.corner.Y); got != tc.expect {
+				t.Fatalf("unexpected color at %v: got=%v, want=%v", tc.corner, got, tc.expect)
+			}
+		})
+	}
+}
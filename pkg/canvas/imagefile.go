@@ -1,29 +1,313 @@
 package canvas
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
 	"image"
+	"image/jpeg"
 	"image/png"
+	"io"
+	"io/fs"
 	"os"
+	"time"
+	"unicode/utf8"
 )
 
+// DefaultMaxPixels is the number of pixels of the largest image decoded by default, which is 16 megapixels.
+const DefaultMaxPixels = 16_000_000
+
+// ErrImageTooLarge is returned when the image to decode has more pixels than the limit.
+var ErrImageTooLarge = errors.New("image is too large")
+
+// LoadOption customizes how an image is decoded.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	maxPixels int
+}
+
+// MaxPixels sets the number of pixels of the largest image to decode. The size of an image is checked
+// before decoding it, so that an enormous image given by mistake fails instead of allocating its pixels.
+// Zero or a negative number disables the limit. The default is DefaultMaxPixels.
+func MaxPixels(n int) LoadOption {
+	return func(lo *loadOptions) {
+		lo.maxPixels = n
+	}
+}
+
 // LoadFromFile loads an image file and generate image.Image from it.
 // Supported image types are JPEG and PNG.
-func LoadFromFile(filename string) (image.Image, error) {
+func LoadFromFile(filename string, opts ...LoadOption) (image.Image, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-	img, _, err := image.Decode(f)
+	img, _, err := Decode(f, opts...)
 	return img, err
 }
 
-// SaveAsPNG saves image object as a PNG image.
-func SaveAsPNG(filename string, img image.Image) error {
-	f, err := os.Create(filename)
+// LoadFromFS loads an image file of the name from the file system, e.g. an embed.FS to ship a template
+// inside a binary. Supported image types are JPEG and PNG.
+func LoadFromFS(fsys fs.FS, name string, opts ...LoadOption) (image.Image, error) {
+	f, err := fsys.Open(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f.Close()
-	return png.Encode(f, img)
+	img, _, err := Decode(f, opts...)
+	return img, err
+}
+
+// Decode decodes a JPEG or PNG image, and returns it with the format name as image.Decode does.
+// It fails with ErrImageTooLarge if the image has more pixels than the limit.
+func Decode(r io.Reader, opts ...LoadOption) (image.Image, string, error) {
+	lo := &loadOptions{maxPixels: DefaultMaxPixels}
+	for _, f := range opts {
+		f(lo)
+	}
+
+	// the header read to check the size is decoded again with the rest
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, "", fmt.Errorf("image is empty: %dx%d", cfg.Width, cfg.Height)
+	}
+	if lo.maxPixels > 0 && cfg.Width*cfg.Height > lo.maxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d exceeds the limit of %d pixels", ErrImageTooLarge, cfg.Width, cfg.Height, lo.maxPixels)
+	}
+	return image.Decode(io.MultiReader(&header, r))
+}
+
+// ErrOutputTooLarge is returned when the encoded image is larger than the limit set by MaxBytes.
+var ErrOutputTooLarge = errors.New("encoded image is too large")
+
+const (
+	// minJPEGQuality is the lowest quality at which a JPEG image is re-encoded to fit in MaxBytes.
+	minJPEGQuality = 30
+	// jpegQualityStep is the step by which the quality is lowered to re-encode a JPEG image.
+	jpegQualityStep = 10
+)
+
+// SaveOption customizes how an image is encoded into a PNG file.
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	level       png.CompressionLevel
+	metadata    []textChunk
+	jpegQuality int
+	maxBytes    int
+	size        *int
+}
+
+// textChunk is a keyword and text pair embedded as a tEXt or iTXt chunk.
+type textChunk struct {
+	keyword string
+	text    string
+}
+
+// CompressionLevel sets the compression level of the PNG encoder.
+// The default is png.BestCompression to make cards small.
+func CompressionLevel(level png.CompressionLevel) SaveOption {
+	return func(so *saveOptions) {
+		so.level = level
+	}
+}
+
+// Metadata embeds the text with the keyword into the PNG file.
+// The keyword must be 1-79 Latin-1 characters.
+func Metadata(keyword, text string) SaveOption {
+	return func(so *saveOptions) {
+		so.metadata = append(so.metadata, textChunk{keyword: keyword, text: text})
+	}
+}
+
+// SourceMetadata embeds the source path which the card is generated from and the generation time
+// into the PNG file, so that the card can be traced back to its post.
+func SourceMetadata(source string, t time.Time) SaveOption {
+	return func(so *saveOptions) {
+		Metadata("Source", source)(so)
+		Metadata("Creation Time", t.Format(time.RFC1123Z))(so)
+	}
+}
+
+// JPEGQuality encodes the image as a JPEG image of the quality (1-100) instead of a PNG image.
+// The metadata cannot be embedded into a JPEG image.
+func JPEGQuality(quality int) SaveOption {
+	return func(so *saveOptions) {
+		so.jpegQuality = quality
+	}
+}
+
+// MaxBytes sets the maximum size (bytes) of the encoded image, since social platforms may reject large
+// images. The image is encoded in memory first, and a larger image is not written and fails with
+// ErrOutputTooLarge. A JPEG image is re-encoded at progressively lower qualities down to 30 until it fits.
+// Zero or a negative number disables the limit, which is the default.
+func MaxBytes(n int) SaveOption {
+	return func(so *saveOptions) {
+		so.maxBytes = n
+	}
+}
+
+// EncodedSize stores the size (bytes) of the encoded image into n when it is written.
+func EncodedSize(n *int) SaveOption {
+	return func(so *saveOptions) {
+		so.size = n
+	}
+}
+
+// SaveAsPNG saves image object as a PNG image, or a JPEG image with JPEGQuality.
+func SaveAsPNG(filename string, img image.Image, opts ...SaveOption) error {
+	b, so, err := encode(img, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, b, 0644); err != nil {
+		return err
+	}
+	so.reportSize(len(b))
+	return nil
+}
+
+// Encode writes the image to w as a PNG image, or a JPEG image with JPEGQuality, as SaveAsPNG does.
+func Encode(w io.Writer, img image.Image, opts ...SaveOption) error {
+	b, so, err := encode(img, opts)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	so.reportSize(len(b))
+	return nil
+}
+
+// encode encodes the image in memory, and checks its size.
+func encode(img image.Image, opts []SaveOption) ([]byte, *saveOptions, error) {
+	so := &saveOptions{level: png.BestCompression}
+	for _, f := range opts {
+		f(so)
+	}
+	var b []byte
+	var err error
+	if so.jpegQuality != 0 {
+		b, err = encodeJPEG(img, so)
+	} else {
+		b, err = encodePNG(img, so)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if so.maxBytes > 0 && len(b) > so.maxBytes {
+		return nil, nil, fmt.Errorf("%w: %d bytes, more than %d bytes", ErrOutputTooLarge, len(b), so.maxBytes)
+	}
+	return b, so, nil
+}
+
+func encodePNG(img image.Image, so *saveOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := &png.Encoder{CompressionLevel: so.level}
+	if err := enc.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	if len(so.metadata) == 0 {
+		return buf.Bytes(), nil
+	}
+	return insertTextChunks(buf.Bytes(), so.metadata)
+}
+
+// encodeJPEG encodes the image at the quality, which is lowered until the image fits in the maximum size.
+// The image of the lowest quality is returned if it never fits.
+func encodeJPEG(img image.Image, so *saveOptions) ([]byte, error) {
+	if so.jpegQuality < 1 || so.jpegQuality > 100 {
+		return nil, fmt.Errorf("JPEG quality must be 1-100: %d", so.jpegQuality)
+	}
+	if len(so.metadata) > 0 {
+		return nil, errors.New("metadata cannot be embedded into a JPEG image")
+	}
+	var buf bytes.Buffer
+	for q := so.jpegQuality; ; q = max(q-jpegQualityStep, minJPEGQuality) {
+		buf.Reset()
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: q}); err != nil {
+			return nil, err
+		}
+		if so.maxBytes <= 0 || buf.Len() <= so.maxBytes || q <= minJPEGQuality {
+			return buf.Bytes(), nil
+		}
+	}
+}
+
+func (so *saveOptions) reportSize(n int) {
+	if so.size != nil {
+		*so.size = n
+	}
+}
+
+const pngHeaderLen = 8
+
+// insertTextChunks inserts the text chunks right after the IHDR chunk of the encoded PNG.
+func insertTextChunks(b []byte, tcs []textChunk) ([]byte, error) {
+	if len(b) < pngHeaderLen+8 {
+		return nil, fmt.Errorf("invalid PNG data")
+	}
+	// IHDR is always the first chunk: length(4) + type(4) + data + CRC(4)
+	pos := pngHeaderLen + 12 + int(binary.BigEndian.Uint32(b[pngHeaderLen:]))
+	if pos > len(b) {
+		return nil, fmt.Errorf("invalid PNG data")
+	}
+
+	var chunks bytes.Buffer
+	for _, tc := range tcs {
+		if l := len(tc.keyword); l < 1 || l > 79 {
+			return nil, fmt.Errorf("PNG metadata keyword must be 1-79 characters: %q", tc.keyword)
+		}
+		if isLatin1(tc.text) {
+			writeChunk(&chunks, "tEXt", []byte(tc.keyword+"\x00"+latin1(tc.text)))
+			continue
+		}
+		// keyword, compression flag and method, empty language tag and translated keyword
+		writeChunk(&chunks, "iTXt", []byte(tc.keyword+"\x00\x00\x00\x00\x00"+tc.text))
+	}
+
+	out := make([]byte, 0, len(b)+chunks.Len())
+	out = append(out, b[:pos]...)
+	out = append(out, chunks.Bytes()...)
+	return append(out, b[pos:]...), nil
+}
+
+func writeChunk(buf *bytes.Buffer, typ string, data []byte) {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(data)))
+	buf.Write(n[:])
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	buf.WriteString(typ)
+	buf.Write(data)
+	binary.BigEndian.PutUint32(n[:], crc.Sum32())
+	buf.Write(n[:])
+}
+
+func isLatin1(s string) bool {
+	for _, r := range s {
+		if r > 0xff || r == utf8.RuneError {
+			return false
+		}
+	}
+	return true
+}
+
+// latin1 encodes the string, which must consist of Latin-1 characters, into Latin-1.
+func latin1(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		b = append(b, byte(r))
+	}
+	return string(b)
 }
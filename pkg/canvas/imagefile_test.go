@@ -0,0 +1,231 @@
+package canvas
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+//go:embed testdata/template.png
+var testFS embed.FS
+
+func TestSaveAsPNGMetadata(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		desc         string
+		opts         []SaveOption
+		expectChunks []string
+	}{
+		{
+			desc:         "No metadata by default",
+			expectChunks: nil,
+		},
+		{
+			desc: "Source metadata is embedded as tEXt",
+			opts: []SaveOption{SourceMetadata("content/post.md", ts)},
+			expectChunks: []string{
+				"tEXtSource\x00content/post.md",
+				"tEXtCreation Time\x00Sat, 02 Jan 2021 03:04:05 +0000",
+			},
+		},
+		{
+			desc:         "Non Latin-1 text is embedded as iTXt",
+			opts:         []SaveOption{CompressionLevel(png.BestSpeed), Metadata("Source", "記事.md")},
+			expectChunks: []string{"iTXtSource\x00\x00\x00\x00\x00記事.md"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "card.png")
+			if err := SaveAsPNG(filename, image.NewRGBA(image.Rect(0, 0, 10, 10)), tc.opts...); err != nil {
+				t.Fatal(err)
+			}
+			b, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := png.Decode(bytes.NewReader(b)); err != nil {
+				t.Fatalf("failed to decode the saved PNG: %v", err)
+			}
+			for _, c := range tc.expectChunks {
+				if !bytes.Contains(b, []byte(c)) {
+					t.Fatalf("chunk %q is not embedded", c)
+				}
+			}
+			if tc.expectChunks == nil && (bytes.Contains(b, []byte("tEXt")) || bytes.Contains(b, []byte("iTXt"))) {
+				t.Fatal("unexpected text chunk is embedded")
+			}
+		})
+	}
+}
+
+func TestSaveAsPNGInvalidKeyword(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "card.png")
+	if err := SaveAsPNG(filename, image.NewRGBA(image.Rect(0, 0, 10, 10)), Metadata("", "text")); err == nil {
+		t.Fatal("expected an error for an empty keyword")
+	}
+}
+
+func TestEncodeMaxBytes(t *testing.T) {
+	// a noisy image which does not compress well
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 7919 % 251)
+	}
+	jpegSize := func(quality int) int {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Len()
+	}
+
+	testCases := []struct {
+		desc       string
+		opts       []SaveOption
+		expectSize int
+		expectErr  error
+		expectJPEG bool
+	}{
+		{
+			desc: "PNG without the limit",
+		},
+		{
+			desc:      "PNG larger than the limit",
+			opts:      []SaveOption{MaxBytes(100)},
+			expectErr: ErrOutputTooLarge,
+		},
+		{
+			desc:       "JPEG at the quality",
+			opts:       []SaveOption{JPEGQuality(90)},
+			expectSize: jpegSize(90),
+			expectJPEG: true,
+		},
+		{
+			desc:       "JPEG is re-encoded at lower qualities to fit",
+			opts:       []SaveOption{JPEGQuality(90), MaxBytes(jpegSize(60))},
+			expectSize: jpegSize(60),
+			expectJPEG: true,
+		},
+		{
+			desc:      "JPEG which does not fit at the lowest quality",
+			opts:      []SaveOption{JPEGQuality(90), MaxBytes(jpegSize(minJPEGQuality) - 1)},
+			expectErr: ErrOutputTooLarge,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			var size int
+			err := Encode(&buf, img, append(tc.opts, EncodedSize(&size))...)
+			if tc.expectErr != nil {
+				if !errors.Is(err, tc.expectErr) {
+					t.Fatalf("unexpected error: got=%v, want=%v", err, tc.expectErr)
+				}
+				if buf.Len() != 0 || size != 0 {
+					t.Fatalf("image is written: %d bytes", buf.Len())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != buf.Len() {
+				t.Fatalf("unexpected encoded size: got=%d, want=%d", size, buf.Len())
+			}
+			if tc.expectSize != 0 && size != tc.expectSize {
+				t.Fatalf("unexpected size: got=%d, want=%d", size, tc.expectSize)
+			}
+			_, format, err := image.Decode(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := map[bool]string{false: "png", true: "jpeg"}[tc.expectJPEG]; format != want {
+				t.Fatalf("unexpected format: got=%s, want=%s", format, want)
+			}
+		})
+	}
+}
+
+func TestEncodeInvalidJPEG(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	if err := Encode(io.Discard, img, JPEGQuality(101)); err == nil {
+		t.Fatal("expected an error for an invalid quality")
+	}
+	if err := Encode(io.Discard, img, JPEGQuality(90), Metadata("Source", "post.md")); err == nil {
+		t.Fatal("expected an error for the metadata of a JPEG image")
+	}
+}
+
+func TestDecodeMaxPixels(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 30, 20))); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		desc      string
+		opts      []LoadOption
+		expectErr bool
+	}{
+		{desc: "Default limit"},
+		{desc: "Image of the limit", opts: []LoadOption{MaxPixels(600)}},
+		{desc: "Image larger than the limit", opts: []LoadOption{MaxPixels(599)}, expectErr: true},
+		{desc: "No limit", opts: []LoadOption{MaxPixels(0)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			img, format, err := Decode(bytes.NewReader(buf.Bytes()), tc.opts...)
+			if tc.expectErr {
+				if !errors.Is(err, ErrImageTooLarge) {
+					t.Fatalf("expected ErrImageTooLarge, got=%v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != "png" || img.Bounds() != image.Rect(0, 0, 30, 20) {
+				t.Fatalf("unexpected image: format=%s, bounds=%v", format, img.Bounds())
+			}
+		})
+	}
+}
+
+func TestLoadFromFS(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		name      string
+		opts      []LoadOption
+		expectErr bool
+	}{
+		{desc: "Embedded template is loaded", name: "testdata/template.png"},
+		{desc: "Pixel limit applies", name: "testdata/template.png", opts: []LoadOption{MaxPixels(4)}, expectErr: true},
+		{desc: "Missing template is an error", name: "testdata/missing.png", expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			img, err := LoadFromFS(testFS, tc.name, tc.opts...)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectErr {
+				return
+			}
+			if img.Bounds() != image.Rect(0, 0, 4, 2) {
+				t.Fatalf("unexpected bounds: %v", img.Bounds())
+			}
+			if got := color.RGBAModel.Convert(img.At(0, 0)); got != (color.RGBA{255, 0, 0, 255}) {
+				t.Fatalf("unexpected color: %v", got)
+			}
+		})
+	}
+}
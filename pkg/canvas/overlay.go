@@ -0,0 +1,37 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// ApplyOverlay composites the color over the whole canvas with the opacity (0-1), which darkens or tints
+// a photographic template so that the texts drawn over it are legible. Call it before drawing the texts.
+func (c *Canvas) ApplyOverlay(color *image.Uniform, opacity float64) error {
+	if color == nil {
+		return errors.New("overlay color is nil")
+	}
+	return c.applyOverlay(color, opacity)
+}
+
+// ApplyGradientOverlay composites a linear gradient over the whole canvas with the opacity (0-1).
+// The angle is as of CreateCanvasWithGradient, and the colors may be translucent, e.g. from transparent
+// to black at 90 darkens the bottom of the canvas under the texts.
+func (c *Canvas) ApplyGradientOverlay(from, to color.Color, angle, opacity float64) error {
+	layer := image.NewRGBA(c.dst.Bounds())
+	fillLinearGradient(layer, from, to, angle)
+	return c.applyOverlay(layer, opacity)
+}
+
+func (c *Canvas) applyOverlay(layer image.Image, opacity float64) error {
+	if opacity < 0 || opacity > 1 {
+		return fmt.Errorf("overlay opacity must be between 0 and 1: %v", opacity)
+	}
+	mask := image.NewUniform(color.Alpha{A: uint8(math.Round(opacity * 255))})
+	draw.DrawMask(c.dst, c.dst.Bounds(), layer, c.dst.Bounds().Min, mask, image.Point{}, draw.Over)
+	return nil
+}
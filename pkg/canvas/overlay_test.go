@@ -0,0 +1,72 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestApplyOverlay(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	testCases := []struct {
+		desc      string
+		color     color.RGBA
+		opacity   float64
+		expect    color.RGBA
+		expectErr bool
+	}{
+		{desc: "Black darkens the background", color: black, opacity: 0.25, expect: color.RGBA{191, 191, 191, 255}},
+		{desc: "Color tints the background", color: red, opacity: 0.5, expect: color.RGBA{255, 127, 127, 255}},
+		{desc: "Zero opacity keeps the background", color: black, opacity: 0, expect: white},
+		{desc: "Opacity over 1 is an error", color: black, opacity: 1.5, expectErr: true},
+		{desc: "Negative opacity is an error", color: black, opacity: -0.1, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newTestCanvas(t, 40, 20)
+			err := c.ApplyOverlay(image.NewUniform(tc.color), tc.opacity)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range []image.Point{{0, 0}, {39, 19}} {
+				if got := c.dst.RGBAAt(p.X, p.Y); !closeRGBA(got, tc.expect) {
+					t.Fatalf("unexpected color at %v: got=%v, want=%v", p, got, tc.expect)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyOverlayNilColor(t *testing.T) {
+	c := newTestCanvas(t, 40, 20)
+	if err := c.ApplyOverlay(nil, 0.5); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestApplyGradientOverlay(t *testing.T) {
+	c := newTestCanvas(t, 40, 20)
+	if err := c.ApplyGradientOverlay(color.Transparent, black, 90, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.dst.RGBAAt(20, 0); got != white {
+		t.Fatalf("the top is overlaid: %v", got)
+	}
+	if got := c.dst.RGBAAt(20, 19); got != black {
+		t.Fatalf("the bottom is not darkened: %v", got)
+	}
+	if got := c.dst.RGBAAt(20, 10); got.R <= 100 || got.R >= 160 {
+		t.Fatalf("the middle is not half darkened: %v", got)
+	}
+}
+
+func closeRGBA(a, b color.RGBA) bool {
+	d := func(x, y uint8) bool { return max(x, y)-min(x, y) <= 1 }
+	return d(a.R, b.R) && d(a.G, b.G) && d(a.B, b.B) && d(a.A, b.A)
+}
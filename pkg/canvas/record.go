@@ -0,0 +1,106 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+)
+
+// Layout is the texts and the boxes laid out on a canvas which records them instead of drawing them,
+// so that they can be rendered as vector graphics (e.g. SVG) over the pixels of the rest of the card.
+type Layout struct {
+	Texts []TextRun
+	Boxes []BoxRect
+}
+
+// TextRun is a line of text, or a character of vertical text, laid out from its start point on the baseline.
+type TextRun struct {
+	// Text is the text in the visual order, which is reversed for right-to-left text.
+	Text string
+	// X and Y are the start point(px) on the baseline.
+	X, Y float64
+	// Width is the advance width(px) including the letter spacing.
+	Width float64
+
+	// FontFamily and FontStyle are empty if the font face is set by FontFace.
+	FontFamily string
+	FontStyle  fontfamily.Style
+	// FontSize is the size(pt) of the font, which is 0 if the font face is set by FontFace.
+	FontSize      float64
+	Color         color.Color
+	LetterSpacing int
+	// NoKerning is set if the kerning between the letters is disabled.
+	NoKerning bool
+
+	StrokeColor color.Color
+	StrokeWidth int
+
+	Underline     bool
+	Strikethrough bool
+}
+
+// BoxRect is the background of a box drawn by DrawBoxTexts.
+type BoxRect struct {
+	Rect        image.Rectangle
+	Radius      int
+	Fill        color.Color
+	BorderColor color.Color
+	BorderWidth int
+}
+
+// Record makes this canvas append the texts drawn by DrawTextAtPoint, DrawTextInBox and DrawBoxTexts,
+// and the boxes of DrawBoxTexts, to the layout instead of drawing them. The other drawings (e.g. images,
+// borders, scrims, and rotated texts) are still drawn on the pixels. The shadows and the clipping of the
+// recorded texts are not applied. A nil layout stops the recording.
+func (c *Canvas) Record(l *Layout) {
+	c.layout = l
+}
+
+// recordString records the string at the current dot, and advances the dot.
+func (c *Canvas) recordString(s string) {
+	adv := c.advance(s)
+	run := TextRun{
+		Text:          s,
+		X:             float(c.fdr.Dot.X),
+		Y:             float(c.fdr.Dot.Y),
+		Width:         float(adv),
+		FontFamily:    c.fontFamily,
+		FontStyle:     c.fontStyle,
+		FontSize:      c.fontSize,
+		Color:         uniformColor(c.fdr.Src),
+		LetterSpacing: c.letterSpacing,
+		NoKerning:     c.noKerning,
+		Underline:     c.underline,
+		Strikethrough: c.strikethrough,
+	}
+	if c.strokeWidth > 0 && c.strokeColor != nil {
+		run.StrokeColor, run.StrokeWidth = c.strokeColor.C, c.strokeWidth
+	}
+	c.layout.Texts = append(c.layout.Texts, run)
+	c.fdr.Dot.X += adv
+}
+
+// recordBox records the box filled in the color with the current border.
+func (c *Canvas) recordBox(rect image.Rectangle, bg *image.Uniform) {
+	b := BoxRect{Rect: rect, Radius: c.boxRadius, Fill: uniformColor(bg)}
+	if c.boxBorderWidth > 0 && c.boxBorderColor != nil {
+		b.BorderColor, b.BorderWidth = c.boxBorderColor.C, c.boxBorderWidth
+	}
+	c.layout.Boxes = append(c.layout.Boxes, b)
+}
+
+// uniformColor returns the color of the uniform image, or nil for the other images.
+func uniformColor(img image.Image) color.Color {
+	if u, ok := img.(*image.Uniform); ok && u != nil {
+		return u.C
+	}
+	return nil
+}
+
+// float converts the 26.6 fixed point number to pixels.
+func float(x fixed.Int26_6) float64 {
+	return float64(x) / 64
+}
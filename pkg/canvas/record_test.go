@@ -0,0 +1,70 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestRecord(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	c := newTestCanvas(t, 400, 200)
+	blank := newTestCanvas(t, 400, 200)
+	var l Layout
+	c.Record(&l)
+
+	ffa := testfont.New(t)
+	opts := []TextDrawOption{FontFaceFromFFA(ffa, fontfamily.Regular, 20), FgColorC(red), MaxWidth(100), TextUnderline(true)}
+	if err := c.DrawTextAtPoint("Hello World", config.Point{X: 10, Y: 20}, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DrawBoxTexts([]string{"go", "svg"}, config.Point{X: 10, Y: 120},
+		FontFaceFromFFA(ffa, fontfamily.Regular, 16), BgColorC(black), BoxCornerRadius(4)); err != nil {
+		t.Fatal(err)
+	}
+	if !sameImage(c.Image(), blank.Image()) {
+		t.Fatal("recorded texts and boxes are drawn on the pixels")
+	}
+
+	_, _, lines, err := c.MeasureText("Hello World", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Texts) != len(lines)+2 {
+		t.Fatalf("unexpected number of text runs: got=%d, want=%d", len(l.Texts), len(lines)+2)
+	}
+	for i, line := range lines {
+		run := l.Texts[i]
+		if run.Text != line || run.X != 10 || run.FontFamily != "Go" || run.FontStyle != fontfamily.Regular || run.FontSize != 20 {
+			t.Fatalf("unexpected text run of line %d: %+v", i, run)
+		}
+		if run.Color != red || !run.Underline {
+			t.Fatalf("text run does not have the style: %+v", run)
+		}
+		if i > 0 && run.Y <= l.Texts[i-1].Y {
+			t.Fatalf("lines are not laid out downward: %+v", l.Texts[:i+1])
+		}
+	}
+
+	if len(l.Boxes) != 2 {
+		t.Fatalf("unexpected number of boxes: got=%d", len(l.Boxes))
+	}
+	if b := l.Boxes[0]; b.Rect.Min != (image.Point{10, 120}) || b.Radius != 4 || b.Fill != black {
+		t.Fatalf("unexpected box: %+v", b)
+	}
+	if l.Boxes[1].Rect.Min.X <= l.Boxes[0].Rect.Max.X-1 {
+		t.Fatalf("boxes overlap: %+v", l.Boxes)
+	}
+
+	c.Record(nil)
+	if err := c.DrawTextAtPoint("Hello", config.Point{X: 10, Y: 20}, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if sameImage(c.Image(), blank.Image()) {
+		t.Fatal("text is not drawn after the recording stops")
+	}
+}
@@ -0,0 +1,87 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/box"
+)
+
+// ErrRegionOverflow is returned when a text block does not fit in its region.
+var ErrRegionOverflow = errors.New("text overflows the region")
+
+// DrawTextInBox draws text within the region of this canvas, aligned vertically by VAlign.
+// Right-to-left and vertical text is aligned to the right of the region.
+// The text is wrapped to the width (the height for vertical text) of the region unless MaxWidth
+// (MaxHeight) is given. A text block larger than the region is handled as set by Overflow, and it is
+// reported as ErrRegionOverflow by default.
+func (c *Canvas) DrawTextInBox(text string, region image.Rectangle, opts ...TextDrawOption) error {
+	if region.Empty() || !region.In(c.dst.Bounds()) {
+		return fmt.Errorf("%w: region %v is not in %v", ErrOutOfBounds, region, c.dst.Bounds())
+	}
+	return c.withOptions(append([]TextDrawOption{MaxWidth(region.Dx()), MaxHeight(region.Dy())}, opts...), func() error {
+		return c.drawTextInBox(text, region)
+	})
+}
+
+func (c *Canvas) drawTextInBox(text string, region image.Rectangle) error {
+	if err := c.checkGlyphs(text); err != nil {
+		return err
+	}
+	w, h, lines, err := c.measureText(text)
+	if err != nil {
+		return err
+	}
+
+	overflow := w > fixed.I(region.Dx()) || h > fixed.I(region.Dy())
+	if overflow && c.overflow != box.OverflowClip {
+		return fmt.Errorf("%w: the text block is %dx%d but the region is %dx%d", ErrRegionOverflow, w.Ceil(), h.Ceil(), region.Dx(), region.Dy())
+	}
+
+	rtl := c.isRTL(text)
+	p := fixed.P(region.Min.X, region.Min.Y)
+	if rtl || c.vertical {
+		p.X = fixed.I(region.Max.X) - w
+	}
+	switch c.vAlign {
+	case box.VAlignMiddle:
+		p.Y += c.snap((fixed.I(region.Dy()) - h) / 2)
+	case box.VAlignBottom:
+		p.Y = fixed.I(region.Max.Y) - h
+	}
+
+	drawFn := func() {
+		c.drawTextBlock(p, w, h, lines, rtl)
+	}
+	if !overflow {
+		c.clipped(drawFn)
+		return nil
+	}
+	clip := region
+	if !c.clip.Empty() {
+		clip = clip.Intersect(c.clip)
+	}
+	c.drawClipped(clip, drawFn)
+	return nil
+}
+
+// VAlign sets the vertical alignment of text drawn by DrawTextInBox.
+// The default is box.VAlignTop.
+func VAlign(align box.VAlign) TextDrawOption {
+	return func(c *Canvas) error {
+		c.vAlign = align
+		return nil
+	}
+}
+
+// Overflow sets how DrawTextInBox handles a text block larger than the region.
+// The default is box.OverflowError.
+func Overflow(overflow box.Overflow) TextDrawOption {
+	return func(c *Canvas) error {
+		c.overflow = overflow
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package canvas
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/box"
+)
+
+func TestDrawTextInBoxVAlign(t *testing.T) {
+	region := image.Rect(20, 20, 380, 280)
+	draw := func(t *testing.T, align box.VAlign) image.Rectangle {
+		c := newTestCanvas(t, 400, 300)
+		if err := c.DrawTextInBox("Vertically aligned text", region, FontFace(newTestFace(t, 32)), VAlign(align)); err != nil {
+			t.Fatal(err)
+		}
+		return inkBounds(c.dst)
+	}
+
+	c := newTestCanvas(t, 400, 300)
+	_, h, _, err := c.MeasureText("Vertically aligned text", FontFace(newTestFace(t, 32)), MaxWidth(region.Dx()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	top := draw(t, box.VAlignTop)
+	testCases := []struct {
+		align  box.VAlign
+		expect int
+	}{
+		{align: box.VAlignTop, expect: 0},
+		{align: box.VAlignMiddle, expect: (region.Dy() - h) / 2},
+		{align: box.VAlignBottom, expect: region.Dy() - h},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.align), func(t *testing.T) {
+			got := draw(t, tc.align)
+			if got.Min.Y-top.Min.Y != tc.expect {
+				t.Fatalf("unexpected offset from the top alignment: got=%d, want=%d", got.Min.Y-top.Min.Y, tc.expect)
+			}
+			if !got.In(region) {
+				t.Fatalf("text %v is drawn outside of the region %v", got, region)
+			}
+		})
+	}
+}
+
+func TestDrawTextInBoxOverflow(t *testing.T) {
+	region := image.Rect(20, 20, 200, 60)
+	text := "A long text which is wrapped into more lines than the region can hold"
+
+	c := newTestCanvas(t, 400, 300)
+	if err := c.DrawTextInBox(text, region, FontFace(newTestFace(t, 32))); !errors.Is(err, ErrRegionOverflow) {
+		t.Fatalf("expected ErrRegionOverflow, got=%v", err)
+	}
+	if got := inkBounds(c.dst); !got.Empty() {
+		t.Fatalf("text is drawn on overflow: %v", got)
+	}
+
+	c = newTestCanvas(t, 400, 300)
+	if err := c.DrawTextInBox(text, region, FontFace(newTestFace(t, 32)), Overflow(box.OverflowClip)); err != nil {
+		t.Fatal(err)
+	}
+	got := inkBounds(c.dst)
+	if got.Empty() {
+		t.Fatal("clipped text is not drawn")
+	}
+	if !got.In(region) {
+		t.Fatalf("text %v is drawn outside of the region %v", got, region)
+	}
+
+	if err := c.DrawTextInBox(text, image.Rect(300, 200, 500, 400)); !errors.Is(err, ErrOutOfBounds) {
+		t.Fatalf("expected ErrOutOfBounds, got=%v", err)
+	}
+}
+
+// inkBounds returns the bounds of the pixels which are not white.
+func inkBounds(img *image.RGBA) image.Rectangle {
+	var r image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y) != white {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,53 @@
+package canvas
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/resize"
+)
+
+// CreateCanvasFromImageResized creates a canvas of the specified size from the template image scaled by the mode.
+// All the points and sizes drawn on the canvas are in the resized coordinate space.
+// For resize.ModeContain, the letterbox is filled with the color at the top-left corner of the template.
+func CreateCanvasFromImageResized(tpl image.Image, width, height int, mode resize.Mode) (*Canvas, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("canvas size must be positive: %dx%d", width, height)
+	}
+
+	sb := tpl.Bounds()
+	if sb.Empty() {
+		return nil, fmt.Errorf("template is empty: %dx%d", sb.Dx(), sb.Dy())
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	var dr, sr image.Rectangle
+	switch mode {
+	case resize.ModeStretch:
+		dr, sr = dst.Bounds(), sb
+	case resize.ModeContain:
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(tpl.At(sb.Min.X, sb.Min.Y)), image.Point{}, draw.Src)
+		dr, sr = fitRect(dst.Bounds(), sb.Dx(), sb.Dy()), sb
+	case resize.ModeCover, "":
+		dr, sr = dst.Bounds(), fitRect(sb, width, height)
+	default:
+		return nil, fmt.Errorf("unknown resize mode: %q", mode)
+	}
+	xdraw.CatmullRom.Scale(dst, dr, tpl, sr, xdraw.Over, nil)
+
+	return CreateCanvasFromImage(dst)
+}
+
+// fitRect returns the largest rectangle of the w:h aspect ratio centered in r.
+func fitRect(r image.Rectangle, w, h int) image.Rectangle {
+	dw, dh := r.Dx(), r.Dy()
+	if dw*h > dh*w {
+		dw = dh * w / h
+	} else {
+		dh = dw * h / w
+	}
+	min := r.Min.Add(image.Pt((r.Dx()-dw)/2, (r.Dy()-dh)/2))
+	return image.Rectangle{Min: min, Max: min.Add(image.Pt(dw, dh))}
+}
@@ -0,0 +1,12 @@
+package resize
+
+type Mode string
+
+const (
+	// ModeCover scales the image to cover the target size, cropping the overflow.
+	ModeCover = Mode("Cover")
+	// ModeContain scales the image to fit in the target size, letterboxing the rest.
+	ModeContain = Mode("Contain")
+	// ModeStretch scales the image to the target size, ignoring the aspect ratio.
+	ModeStretch = Mode("Stretch")
+)
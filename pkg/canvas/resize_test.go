@@ -0,0 +1,79 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/resize"
+)
+
+func TestCreateCanvasFromImageResized(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	// 200x100 template: a red frame around a blue body
+	tpl := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(tpl, tpl.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(tpl, image.Rect(20, 20, 180, 80), image.NewUniform(blue), image.Point{}, draw.Src)
+
+	testCases := []struct {
+		desc   string
+		mode   resize.Mode
+		expect map[image.Point]color.RGBA
+	}{
+		{
+			desc: "Cover crops the sides",
+			mode: resize.ModeCover,
+			expect: map[image.Point]color.RGBA{
+				{2, 50}:  blue, // the left frame is cropped
+				{50, 50}: blue,
+				{50, 2}:  red,
+			},
+		},
+		{
+			desc: "Contain letterboxes the top and bottom",
+			mode: resize.ModeContain,
+			expect: map[image.Point]color.RGBA{
+				{50, 10}: red, // letterbox
+				{50, 50}: blue,
+				{2, 50}:  red,
+			},
+		},
+		{
+			desc: "Stretch keeps the whole template",
+			mode: resize.ModeStretch,
+			expect: map[image.Point]color.RGBA{
+				{2, 50}:  red,
+				{50, 50}: blue,
+				{50, 2}:  red,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := CreateCanvasFromImageResized(tpl, 100, 100, tc.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := c.dst.Bounds(); b != image.Rect(0, 0, 100, 100) {
+				t.Fatalf("unexpected bounds: %v", b)
+			}
+			for p, want := range tc.expect {
+				if got := c.dst.RGBAAt(p.X, p.Y); got != want {
+					t.Fatalf("unexpected color at %v: got=%v, want=%v", p, got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := CreateCanvasFromImageResized(tpl, 0, 100, resize.ModeCover); err == nil {
+		t.Fatal("expected an error for the empty size")
+	}
+	if _, err := CreateCanvasFromImageResized(image.NewRGBA(image.Rect(0, 0, 100, 0)), 100, 100, resize.ModeContain); err == nil {
+		t.Fatal("expected an error for the empty template")
+	}
+	if _, err := CreateCanvasFromImageResized(tpl, 100, 100, resize.Mode("Unknown")); err == nil {
+		t.Fatal("expected an error for the unknown mode")
+	}
+}
@@ -0,0 +1,98 @@
+package canvas
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+// DrawRotatedText draws single-line text rotated by the angle (degrees, counterclockwise)
+// around the center point. This is mainly used to stamp a watermark on the card.
+func (c *Canvas) DrawRotatedText(text string, center config.Point, angle float64, opts ...TextDrawOption) error {
+	return c.withOptions(opts, func() error {
+		return c.drawRotatedText(text, center, angle)
+	})
+}
+
+func (c *Canvas) drawRotatedText(text string, center config.Point, angle float64) error {
+	if c.fdr.Face == nil {
+		return errors.New("font face is not set")
+	}
+	if err := c.checkGlyphs(text); err != nil {
+		return err
+	}
+
+	// draw the text into an alpha mask at the origin
+	m := c.fdr.Face.Metrics()
+	w := c.advance(text).Ceil()
+	h := (m.Ascent + m.Descent).Ceil()
+	if w == 0 || h == 0 {
+		return nil
+	}
+	src := image.NewAlpha(image.Rect(0, 0, w, h))
+	dot := fixed.P(0, m.Ascent.Ceil())
+	c.drawSpaced(&font.Drawer{
+		Dst:  src,
+		Src:  image.Opaque,
+		Face: c.fdr.Face,
+		Dot:  dot,
+	}, c.visual(text, c.isRTL(text)), false)
+	for _, r := range c.decorationRects(c.fdr.Face, dot, c.advance(text)) {
+		draw.Draw(src, r, image.Opaque, image.Point{}, draw.Src)
+	}
+
+	rad := angle * math.Pi / 180
+	sin, cos := math.Sincos(rad)
+	cx, cy := float64(center.X), float64(center.Y)
+	hw, hh := float64(w)/2, float64(h)/2
+
+	// bounding box of the rotated text on the canvas
+	ex := math.Abs(hw*cos) + math.Abs(hh*sin)
+	ey := math.Abs(hw*sin) + math.Abs(hh*cos)
+	bbox := image.Rect(
+		int(math.Floor(cx-ex)), int(math.Floor(cy-ey)),
+		int(math.Ceil(cx+ex)), int(math.Ceil(cy+ey)),
+	).Intersect(c.dst.Bounds())
+	if !c.clip.Empty() {
+		bbox = bbox.Intersect(c.clip)
+	}
+	if bbox.Empty() {
+		return nil
+	}
+
+	mask := image.NewAlpha(bbox)
+	for y := bbox.Min.Y; y < bbox.Max.Y; y++ {
+		for x := bbox.Min.X; x < bbox.Max.X; x++ {
+			// rotate back the canvas point into the text coordinates
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			sx := dx*cos - dy*sin + hw - 0.5
+			sy := dx*sin + dy*cos + hh - 0.5
+			a := bilinearAlpha(src, sx, sy) * c.textOpacity
+			mask.SetAlpha(x, y, color.Alpha{A: uint8(math.Round(math.Min(255, a)))})
+		}
+	}
+	draw.DrawMask(c.dst, bbox, c.fdr.Src, image.Point{}, mask, bbox.Min, draw.Over)
+	return nil
+}
+
+func bilinearAlpha(img *image.Alpha, x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	at := func(x, y int) float64 {
+		if !(image.Point{x, y}.In(img.Bounds())) {
+			return 0
+		}
+		return float64(img.AlphaAt(x, y).A)
+	}
+	ix, iy := int(x0), int(y0)
+	top := at(ix, iy)*(1-fx) + at(ix+1, iy)*fx
+	bottom := at(ix, iy+1)*(1-fx) + at(ix+1, iy+1)*fx
+	return top*(1-fy) + bottom*fy
+}
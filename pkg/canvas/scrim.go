@@ -0,0 +1,91 @@
+package canvas
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+type scrim struct {
+	strength float64
+	blur     int
+	padding  int
+}
+
+// Scrim darkens and blurs the background just behind the text bounding box for contrast.
+// The strength (0-1) is the ratio of darkening, the blur is the radius(px) of the Gaussian blur,
+// and the box is extended by the padding(px). Zero strength and blur disable it.
+func Scrim(strength float64, blur, padding int) TextDrawOption {
+	return func(c *Canvas) error {
+		if strength < 0 || strength > 1 {
+			return fmt.Errorf("scrim strength must be between 0 and 1: %v", strength)
+		}
+		if blur < 0 || padding < 0 {
+			return fmt.Errorf("scrim blur and padding must not be negative: blur=%d, padding=%d", blur, padding)
+		}
+		c.scrim = nil
+		if strength > 0 || blur > 0 {
+			c.scrim = &scrim{strength: strength, blur: blur, padding: padding}
+		}
+		return nil
+	}
+}
+
+// drawScrim applies the scrim to the rectangle of the background.
+func (c *Canvas) drawScrim(r image.Rectangle) {
+	r = r.Inset(-c.scrim.padding).Intersect(c.dst.Bounds())
+	if r.Empty() {
+		return
+	}
+	gaussianBlurRGBA(c.dst, r, c.scrim.blur)
+	darkenRGBA(c.dst, r, c.scrim.strength)
+}
+
+// darkenRGBA darkens the rectangle of the image by the strength.
+func darkenRGBA(img *image.RGBA, r image.Rectangle, strength float64) {
+	k := 1 - strength
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			for j := 0; j < 3; j++ {
+				img.Pix[i+j] = uint8(math.Round(float64(img.Pix[i+j]) * k))
+			}
+		}
+	}
+}
+
+// gaussianBlurRGBA blurs the rectangle of the image. Pixels outside the rectangle are not sampled,
+// and the edge pixels are repeated instead.
+func gaussianBlurRGBA(img *image.RGBA, r image.Rectangle, radius int) {
+	if radius <= 0 {
+		return
+	}
+	kernel := gaussianKernel(radius)
+	w, h := r.Dx(), r.Dy()
+	clamp := func(v, n int) int { return min(max(v, 0), n-1) }
+	buf := make([]float64, w*h*4)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			for ch := 0; ch < 4; ch++ {
+				var sum float64
+				for k, wt := range kernel {
+					sx := clamp(x+k-radius, w)
+					sum += wt * float64(img.Pix[img.PixOffset(r.Min.X+sx, r.Min.Y+y)+ch])
+				}
+				buf[(y*w+x)*4+ch] = sum
+			}
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			for ch := 0; ch < 4; ch++ {
+				var sum float64
+				for k, wt := range kernel {
+					sy := clamp(y+k-radius, h)
+					sum += wt * buf[(sy*w+x)*4+ch]
+				}
+				img.Pix[img.PixOffset(r.Min.X+x, r.Min.Y+y)+ch] = uint8(math.Round(math.Min(255, sum)))
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestDrawTextAtPointScrim(t *testing.T) {
+	gray := color.RGBA{200, 200, 200, 255}
+	testCases := []struct {
+		desc         string
+		opts         []TextDrawOption
+		expectBehind color.RGBA
+	}{
+		{desc: "Background behind the text is darkened", opts: []TextDrawOption{Scrim(0.5, 0, 4)}, expectBehind: color.RGBA{100, 100, 100, 255}},
+		{desc: "Blur keeps a flat background", opts: []TextDrawOption{Scrim(0.5, 3, 4)}, expectBehind: color.RGBA{100, 100, 100, 255}},
+		{desc: "Zero strength and blur disables the scrim", opts: []TextDrawOption{Scrim(0, 0, 4)}, expectBehind: gray},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tpl := image.NewRGBA(image.Rect(0, 0, 400, 200))
+			draw.Draw(tpl, tpl.Bounds(), image.NewUniform(gray), image.Point{}, draw.Src)
+			c, err := CreateCanvasFromImage(tpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := append([]TextDrawOption{FontFace(newTestFace(t, 32)), FgColor(image.NewUniform(black))}, tc.opts...)
+			if err := c.DrawTextAtPoint("Title", config.Point{X: 50, Y: 50}, opts...); err != nil {
+				t.Fatal(err)
+			}
+			// above the glyphs, inside the padded bounding box
+			if got := c.dst.RGBAAt(48, 48); got != tc.expectBehind {
+				t.Fatalf("unexpected color behind the text: got=%v, want=%v", got, tc.expectBehind)
+			}
+			for _, p := range []image.Point{{40, 40}, {300, 60}, {60, 150}} {
+				if got := c.dst.RGBAAt(p.X, p.Y); got != gray {
+					t.Fatalf("surrounding background at %v is changed: got=%v", p, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package canvas
+
+import (
+	"image"
+
+	"golang.org/x/image/vector"
+)
+
+// kappa is the distance of control points to approximate a quarter circle by a cubic Bézier curve.
+const kappa = 0.5522847498
+
+// roundedRectMask returns an anti-aliased alpha mask of a rounded rectangle that has the
+// same size as r. The mask bounds start at the origin.
+// The radius is clamped so that it never exceeds half of the rectangle height or width.
+func roundedRectMask(r image.Rectangle, radius int) *image.Alpha {
+	w, h := r.Dx(), r.Dy()
+	radius = clampRadius(radius, w, h)
+
+	var (
+		fw, fh = float32(w), float32(h)
+		rad    = float32(radius)
+		k      = rad * (1 - kappa)
+		z      = vector.NewRasterizer(w, h)
+	)
+	z.MoveTo(rad, 0)
+	z.LineTo(fw-rad, 0)
+	z.CubeTo(fw-k, 0, fw, k, fw, rad)
+	z.LineTo(fw, fh-rad)
+	z.CubeTo(fw, fh-k, fw-k, fh, fw-rad, fh)
+	z.LineTo(rad, fh)
+	z.CubeTo(k, fh, 0, fh-k, 0, fh-rad)
+	z.LineTo(0, rad)
+	z.CubeTo(0, k, k, 0, rad, 0)
+	z.ClosePath()
+
+	mask := image.NewAlpha(image.Rect(0, 0, w, h))
+	z.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	return mask
+}
+
+func clampRadius(radius, w, h int) int {
+	if radius < 0 {
+		return 0
+	}
+	if radius > h/2 {
+		radius = h / 2
+	}
+	if radius > w/2 {
+		radius = w / 2
+	}
+	return radius
+}
+
+// roundedRingMask returns an anti-aliased alpha mask of the outline of a rounded rectangle
+// that has the same size as r. The outline of the width is drawn inside the rectangle.
+func roundedRingMask(r image.Rectangle, radius, width int) *image.Alpha {
+	radius = clampRadius(radius, r.Dx(), r.Dy())
+	mask := roundedRectMask(r, radius)
+
+	inner := image.Rect(width, width, r.Dx()-width, r.Dy()-width)
+	if inner.Empty() {
+		return mask
+	}
+	innerRadius := radius - width
+	if innerRadius < 0 {
+		innerRadius = 0
+	}
+	im := roundedRectMask(inner, innerRadius)
+	for y := 0; y < inner.Dy(); y++ {
+		for x := 0; x < inner.Dx(); x++ {
+			i := mask.PixOffset(x+width, y+width)
+			mask.Pix[i] -= min(mask.Pix[i], im.Pix[im.PixOffset(x, y)])
+		}
+	}
+	return mask
+}
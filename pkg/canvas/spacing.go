@@ -0,0 +1,102 @@
+package canvas
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// LetterSpacing sets the extra space(px) between letters. A negative value tightens the letters.
+func LetterSpacing(px int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.letterSpacing = px
+		return nil
+	}
+}
+
+// DisableKerning disables the kerning between letters if disabled is set, so that each letter advances by its
+// own advance width and the letters of a monospaced font align in a grid, e.g. the digits of dates.
+// The kerning is enabled by default.
+func DisableKerning(disabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.noKerning = disabled
+		return nil
+	}
+}
+
+// advance returns the advance width of the string including the letter spacing.
+func (c *Canvas) advance(s string) fixed.Int26_6 {
+	if c.emoji == nil {
+		return c.advanceText(s)
+	}
+	var adv fixed.Int26_6
+	for i, sp := range c.emojiSpans(s) {
+		if i > 0 {
+			adv += fixed.I(c.letterSpacing)
+		}
+		if sp.glyph != nil {
+			adv += sp.glyph.Advance
+			continue
+		}
+		adv += c.advanceText(sp.s)
+	}
+	return adv
+}
+
+// advanceText returns the advance width of the string drawn with the font face.
+func (c *Canvas) advanceText(s string) fixed.Int26_6 {
+	var adv fixed.Int26_6
+	if c.noKerning {
+		for _, r := range s {
+			a, _ := c.fdr.Face.GlyphAdvance(r)
+			adv += a
+		}
+	} else {
+		adv = c.fdr.MeasureString(s)
+	}
+	if n := utf8.RuneCountInString(s); c.letterSpacing != 0 && n > 1 {
+		adv += fixed.I(c.letterSpacing * (n - 1))
+	}
+	return adv
+}
+
+// drawSpaced draws the string with the drawer, adding the letter spacing between letters.
+// Emoji are drawn in their colors if color is set, otherwise in the color of the drawer.
+func (c *Canvas) drawSpaced(d *font.Drawer, s string, color bool) {
+	if c.emoji == nil {
+		c.drawSpacedText(d, s)
+		return
+	}
+	for i, sp := range c.emojiSpans(s) {
+		if i > 0 {
+			d.Dot.X += fixed.I(c.letterSpacing)
+		}
+		if sp.glyph != nil {
+			drawEmoji(d, sp.glyph, color)
+			continue
+		}
+		c.drawSpacedText(d, sp.s)
+	}
+}
+
+// drawSpacedText draws the string with the font face of the drawer. The letters are drawn one by one
+// without the kerning if it is disabled. It is identical to font.Drawer.DrawString if the letter spacing is 0
+// and the kerning is enabled.
+func (c *Canvas) drawSpacedText(d *font.Drawer, s string) {
+	if c.letterSpacing == 0 && !c.noKerning {
+		d.DrawString(s)
+		return
+	}
+	prev := rune(-1)
+	for _, r := range s {
+		if prev >= 0 {
+			if !c.noKerning {
+				d.Dot.X += d.Face.Kern(prev, r)
+			}
+			d.Dot.X += fixed.I(c.letterSpacing)
+		}
+		d.DrawString(string(r))
+		prev = r
+	}
+}
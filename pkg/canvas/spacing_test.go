@@ -0,0 +1,119 @@
+package canvas
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestLetterSpacing(t *testing.T) {
+	const text = "Letter spacing"
+	drawText := func(t *testing.T, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 400, 100)
+		opts = append([]TextDrawOption{FontFace(newTestFace(t, 22)), FgColor(image.NewUniform(black))}, opts...)
+		if err := c.DrawTextAtPoint(text, config.Point{X: 10, Y: 10}, opts...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("Zero spacing is identical to DrawString", func(t *testing.T) {
+		if !sameImage(drawText(t, LetterSpacing(0)).dst, drawText(t).dst) {
+			t.Fatal("zero letter spacing changes the output")
+		}
+	})
+	t.Run("Spacing is added between letters", func(t *testing.T) {
+		c := newTestCanvas(t, 400, 100)
+		w0, _, _, err := c.MeasureText(text, FontFace(newTestFace(t, 22)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, _, _, err := c.MeasureText(text, FontFace(newTestFace(t, 22)), LetterSpacing(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len([]rune(text)); w-w0 < 3*(n-1)-1 || w-w0 > 3*(n-1)+1 {
+			t.Fatalf("unexpected width: got=%d, want=%d", w, w0+3*(n-1))
+		}
+		if sameImage(drawText(t, LetterSpacing(3)).dst, drawText(t).dst) {
+			t.Fatal("letter spacing is not drawn")
+		}
+	})
+	t.Run("Wrapping accounts for the spacing", func(t *testing.T) {
+		c := newTestCanvas(t, 400, 100)
+		_, _, lines, err := c.MeasureText(text, FontFace(newTestFace(t, 22)), MaxWidth(200))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) != 1 {
+			t.Fatalf("text is expected to fit without spacing: %q", lines)
+		}
+		_, _, lines, err = c.MeasureText(text, FontFace(newTestFace(t, 22)), MaxWidth(200), LetterSpacing(10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) != 2 {
+			t.Fatalf("text is expected to wrap with spacing: %q", lines)
+		}
+	})
+}
+
+func TestDisableKerning(t *testing.T) {
+	const text = "AVAVAV"
+	// the Go fonts have no kerning pairs, so every pair is tightened by 4px
+	newFace := func(t *testing.T) font.Face {
+		return &kernedFace{Face: newTestFace(t, 32), kern: fixed.I(-4)}
+	}
+	drawText := func(t *testing.T, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 400, 100)
+		opts = append([]TextDrawOption{FontFace(newFace(t)), FgColor(image.NewUniform(black))}, opts...)
+		if err := c.DrawTextAtPoint(text, config.Point{X: 10, Y: 10}, opts...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("Kerning is kept by default", func(t *testing.T) {
+		if !sameImage(drawText(t, DisableKerning(false)).dst, drawText(t).dst) {
+			t.Fatal("enabled kerning changes the output")
+		}
+	})
+	t.Run("Letters advance by their own widths", func(t *testing.T) {
+		face := newFace(t)
+		var unkerned fixed.Int26_6
+		for _, r := range text {
+			a, _ := face.GlyphAdvance(r)
+			unkerned += a
+		}
+
+		c := newTestCanvas(t, 400, 100)
+		w0, _, _, err := c.MeasureText(text, FontFace(face))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, _, _, err := c.MeasureText(text, FontFace(face), DisableKerning(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w != unkerned.Ceil() || w-w0 != 4*(len(text)-1) {
+			t.Fatalf("unexpected width: got=%d, want=%d (kerned %d)", w, unkerned.Ceil(), w0)
+		}
+		if sameImage(drawText(t, DisableKerning(true)).dst, drawText(t).dst) {
+			t.Fatal("kerning is not disabled")
+		}
+	})
+}
+
+// kernedFace is the font face which kerns every pair of letters by kern.
+type kernedFace struct {
+	font.Face
+	kern fixed.Int26_6
+}
+
+func (f *kernedFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return f.kern
+}
@@ -0,0 +1,47 @@
+package canvas
+
+import (
+	"math"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+// Subpixel enables the subpixel positioning, which keeps the fractional positions of texts instead of
+// snapping them to whole pixels. It makes small texts evenly spaced, while pixel-snapped texts may look
+// crisper in large sizes. It is disabled by default.
+func Subpixel(enabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.subpixel = enabled
+		return nil
+	}
+}
+
+// DrawTextAtPointF draws text as DrawTextAtPoint does at the fractional point. The point is rounded to
+// whole pixels unless the subpixel positioning is enabled.
+func (c *Canvas) DrawTextAtPointF(text string, x, y float64, opts ...TextDrawOption) error {
+	if err := c.checkBounds(config.Point{X: int(math.Floor(x)), Y: int(math.Floor(y))}); err != nil {
+		return err
+	}
+	start := fixed.Point26_6{X: fixed.Int26_6(math.Round(x * 64)), Y: fixed.Int26_6(math.Round(y * 64))}
+	return c.withOptions(opts, func() error {
+		return c.drawTextAt(text, start)
+	})
+}
+
+// snap truncates the length to whole pixels unless the subpixel positioning is enabled.
+func (c *Canvas) snap(x fixed.Int26_6) fixed.Int26_6 {
+	if c.subpixel {
+		return x
+	}
+	return x / 64 * 64
+}
+
+// snapPoint rounds the point to whole pixels unless the subpixel positioning is enabled.
+func (c *Canvas) snapPoint(p fixed.Point26_6) fixed.Point26_6 {
+	if c.subpixel {
+		return p
+	}
+	return fixed.P(p.X.Round(), p.Y.Round())
+}
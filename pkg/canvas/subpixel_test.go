@@ -0,0 +1,64 @@
+package canvas
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestSubpixel(t *testing.T) {
+	const text = "Subpixel"
+	drawText := func(t *testing.T, x, y float64, opts ...TextDrawOption) *Canvas {
+		c := newTestCanvas(t, 200, 60)
+		opts = append([]TextDrawOption{FontFace(newTestFace(t, 14)), FgColor(image.NewUniform(black))}, opts...)
+		if err := c.DrawTextAtPointF(text, x, y, opts...); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("Fractional point is snapped by default", func(t *testing.T) {
+		c := newTestCanvas(t, 200, 60)
+		if err := c.DrawTextAtPoint(text, config.Point{X: 11, Y: 10}, FontFace(newTestFace(t, 14)), FgColor(image.NewUniform(black))); err != nil {
+			t.Fatal(err)
+		}
+		if !sameImage(drawText(t, 10.6, 10.2).dst, c.dst) {
+			t.Fatal("fractional point is not snapped to the nearest pixel")
+		}
+	})
+	t.Run("Fractional point is kept with subpixel positioning", func(t *testing.T) {
+		if sameImage(drawText(t, 10.5, 10, Subpixel(true)).dst, drawText(t, 10, 10, Subpixel(true)).dst) {
+			t.Fatal("fractional point is snapped with subpixel positioning")
+		}
+	})
+	t.Run("Point outside the canvas", func(t *testing.T) {
+		c := newTestCanvas(t, 200, 60)
+		if err := c.DrawTextAtPointF(text, -0.5, 10); !errors.Is(err, ErrOutOfBounds) {
+			t.Fatalf("unexpected error: got=%v, want=%v", err, ErrOutOfBounds)
+		}
+	})
+}
+
+func TestSubpixelBoxTexts(t *testing.T) {
+	texts := []string{"go", "hugo", "ogp"}
+	c := newTestCanvas(t, 400, 60)
+	if err := FontFace(newTestFace(t, 13))(c); err != nil {
+		t.Fatal(err)
+	}
+
+	var exact fixed.Int26_6
+	for _, s := range texts {
+		exact += c.measureString(s)
+	}
+	if got := c.measureTexts(texts); got&63 != 0 {
+		t.Fatalf("box texts are not snapped by default: got=%v", got)
+	}
+	c.subpixel = true
+	if got := c.measureTexts(texts); got != exact {
+		t.Fatalf("box texts are rounded with subpixel positioning: got=%v, want=%v", got, exact)
+	}
+}
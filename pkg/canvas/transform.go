@@ -0,0 +1,55 @@
+package canvas
+
+import "image"
+
+// FlipHorizontal mirrors the pixels of this canvas left to right.
+// The transforms apply to what is drawn so far, so call them on the canvas of a template before drawing
+// the texts and images. The positions of the draw calls are not transformed.
+func (c *Canvas) FlipHorizontal() {
+	c.transform(false, func(x, y, w, h int) (int, int) { return w - 1 - x, y })
+}
+
+// FlipVertical mirrors the pixels of this canvas top to bottom.
+func (c *Canvas) FlipVertical() {
+	c.transform(false, func(x, y, w, h int) (int, int) { return x, h - 1 - y })
+}
+
+// Rotate90 rotates the pixels of this canvas by 90 degrees clockwise. The width and height of the canvas
+// are swapped, and the positions of the later draw calls are in the coordinates of the rotated canvas
+// from its top-left corner, e.g. a 1200x630 template becomes a 630x1200 canvas laid out as portrait.
+func (c *Canvas) Rotate90() {
+	c.transform(true, func(x, y, w, h int) (int, int) { return h - 1 - y, x })
+}
+
+// Rotate180 rotates the pixels of this canvas by 180 degrees.
+func (c *Canvas) Rotate180() {
+	c.transform(false, func(x, y, w, h int) (int, int) { return w - 1 - x, h - 1 - y })
+}
+
+// Rotate270 rotates the pixels of this canvas by 270 degrees clockwise (90 degrees counterclockwise).
+// The width and height are swapped as Rotate90 does.
+func (c *Canvas) Rotate270() {
+	c.transform(true, func(x, y, w, h int) (int, int) { return y, w - 1 - x })
+}
+
+// transform moves each pixel of this canvas to the point mapped from its point relative to the top-left
+// corner, in the canvas of w x h. The width and height of the new canvas are swapped if swap is set.
+func (c *Canvas) transform(swap bool, mapPoint func(x, y, w, h int) (int, int)) {
+	b := c.dst.Bounds()
+	w, h := b.Dx(), b.Dy()
+	r := b
+	if swap {
+		r = image.Rect(b.Min.X, b.Min.Y, b.Min.X+h, b.Min.Y+w)
+	}
+	dst := image.NewRGBA(r)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := mapPoint(x, y, w, h)
+			si := c.dst.PixOffset(b.Min.X+x, b.Min.Y+y)
+			di := dst.PixOffset(r.Min.X+dx, r.Min.Y+dy)
+			copy(dst.Pix[di:di+4], c.dst.Pix[si:si+4])
+		}
+	}
+	c.dst = dst
+	c.fdr.Dst = dst
+}
@@ -0,0 +1,85 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	// 3x2 template: red at the top-left corner, blue at the bottom-right corner
+	newCanvas := func(t *testing.T) *Canvas {
+		tpl := image.NewRGBA(image.Rect(0, 0, 3, 2))
+		tpl.SetRGBA(0, 0, red)
+		tpl.SetRGBA(2, 1, blue)
+		c, err := CreateCanvasFromImage(tpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	testCases := []struct {
+		desc         string
+		transform    func(c *Canvas)
+		expectBounds image.Rectangle
+		expectRed    image.Point
+		expectBlue   image.Point
+	}{
+		{
+			desc:         "FlipHorizontal",
+			transform:    (*Canvas).FlipHorizontal,
+			expectBounds: image.Rect(0, 0, 3, 2),
+			expectRed:    image.Pt(2, 0),
+			expectBlue:   image.Pt(0, 1),
+		},
+		{
+			desc:         "FlipVertical",
+			transform:    (*Canvas).FlipVertical,
+			expectBounds: image.Rect(0, 0, 3, 2),
+			expectRed:    image.Pt(0, 1),
+			expectBlue:   image.Pt(2, 0),
+		},
+		{
+			desc:         "Rotate90",
+			transform:    (*Canvas).Rotate90,
+			expectBounds: image.Rect(0, 0, 2, 3),
+			expectRed:    image.Pt(1, 0),
+			expectBlue:   image.Pt(0, 2),
+		},
+		{
+			desc:         "Rotate180",
+			transform:    (*Canvas).Rotate180,
+			expectBounds: image.Rect(0, 0, 3, 2),
+			expectRed:    image.Pt(2, 1),
+			expectBlue:   image.Pt(0, 0),
+		},
+		{
+			desc:         "Rotate270",
+			transform:    (*Canvas).Rotate270,
+			expectBounds: image.Rect(0, 0, 2, 3),
+			expectRed:    image.Pt(0, 2),
+			expectBlue:   image.Pt(1, 0),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := newCanvas(t)
+			tc.transform(c)
+			if b := c.Bounds(); b != tc.expectBounds {
+				t.Fatalf("unexpected bounds: got=%v, want=%v", b, tc.expectBounds)
+			}
+			if got := c.Image().RGBAAt(tc.expectRed.X, tc.expectRed.Y); got != red {
+				t.Fatalf("unexpected color at %v: got=%v, want=%v", tc.expectRed, got, red)
+			}
+			if got := c.Image().RGBAAt(tc.expectBlue.X, tc.expectBlue.Y); got != blue {
+				t.Fatalf("unexpected color at %v: got=%v, want=%v", tc.expectBlue, got, blue)
+			}
+			if c.fdr.Dst != c.dst {
+				t.Fatal("the drawer does not draw on the transformed image")
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package canvas
+
+import (
+	"github.com/rivo/uniseg"
+	"golang.org/x/image/math/fixed"
+)
+
+// verticalForms are the presentation forms of the punctuation and brackets in vertical text.
+var verticalForms = map[string]string{
+	"、": "︑", "。": "︒", "，": "︐", "：": "︓", "；": "︔", "！": "︕", "？": "︖",
+	"…": "︙", "‥": "︰", "―": "︱", "—": "︱", "（": "︵", "）": "︶", "｛": "︷", "｝": "︸",
+	"〔": "︹", "〕": "︺", "【": "︻", "】": "︼", "《": "︽", "》": "︾", "〈": "︿", "〉": "﹀",
+	"「": "﹁", "」": "﹂", "『": "﹃", "』": "﹄",
+}
+
+// Vertical enables the vertical writing (tategaki), which draws the text top to bottom in columns
+// laid from right to left. The start point is the top right corner of the text block, and the columns
+// are broken at MaxHeight as lines are broken at MaxWidth.
+// The glyphs are drawn upright: punctuation and brackets are replaced with their vertical forms if the font
+// has them, and Latin letters are not rotated. Underline and strikethrough are not drawn in vertical text.
+func Vertical(enabled bool) TextDrawOption {
+	return func(c *Canvas) error {
+		c.vertical = enabled
+		return nil
+	}
+}
+
+// MaxHeight sets the maximum height of the columns of vertical text.
+// If the full column height exceeds the limit, drawer breaks the column.
+func MaxHeight(max int) TextDrawOption {
+	return func(c *Canvas) error {
+		c.maxHeight = max
+		return nil
+	}
+}
+
+// lineLimit returns the maximum length of the lines: the width, or the column height of vertical text.
+func (c *Canvas) lineLimit() int {
+	if c.vertical {
+		return c.maxHeight
+	}
+	return c.maxWidth
+}
+
+// verticalAdvance returns the height of the column which the string is drawn in.
+// Each grapheme cluster takes a square em box followed by the letter spacing.
+func (c *Canvas) verticalAdvance(s string) fixed.Int26_6 {
+	n := uniseg.GraphemeClusterCount(s)
+	if n == 0 {
+		return 0
+	}
+	return c.emSize()*fixed.Int26_6(n) + fixed.I(c.letterSpacing*(n-1))
+}
+
+func (c *Canvas) emSize() fixed.Int26_6 {
+	m := c.fdr.Face.Metrics()
+	return m.Ascent + m.Descent
+}
+
+// measureColumns returns the size of the block of the columns.
+func (c *Canvas) measureColumns(columns []string) (w, h fixed.Int26_6) {
+	for _, col := range columns {
+		h = max(h, c.verticalAdvance(col))
+	}
+	if n := len(columns); n > 0 {
+		w = c.fdr.Face.Metrics().Height + c.linePitch()*fixed.Int26_6(n-1)
+	}
+	return w, h
+}
+
+// drawColumns draws the columns in the block with the top left corner at the point, from the right edge.
+// Each grapheme cluster is centered horizontally in its column.
+func (c *Canvas) drawColumns(p fixed.Point26_6, w fixed.Int26_6, columns []string) {
+	m := c.fdr.Face.Metrics()
+	em := c.emSize()
+	pitch := c.linePitch()
+	for i, col := range columns {
+		center := p.X + w - pitch*fixed.Int26_6(i) - m.Height/2
+		y := p.Y
+		gr := uniseg.NewGraphemes(col)
+		for gr.Next() {
+			s := c.verticalForm(gr.Str())
+			c.fdr.Dot = fixed.Point26_6{X: center - c.advance(s)/2, Y: y + m.Ascent}
+			c.drawString(s)
+			y += em + fixed.I(c.letterSpacing)
+		}
+	}
+}
+
+// verticalForm returns the vertical presentation form of the grapheme cluster if the font has it.
+func (c *Canvas) verticalForm(s string) string {
+	vf, ok := verticalForms[s]
+	if !ok {
+		return s
+	}
+	for _, r := range vf {
+		if _, ok := c.fdr.Face.GlyphAdvance(r); !ok {
+			return s
+		}
+	}
+	return vf
+}
@@ -0,0 +1,74 @@
+package canvas
+
+import (
+	"image"
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+func TestVertical(t *testing.T) {
+	newCanvas := func(t *testing.T) *Canvas {
+		c := newTestCanvas(t, 300, 300)
+		if err := FontFace(newTestFace(t, 20))(c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("Disabled vertical text is identical to horizontal text", func(t *testing.T) {
+		draw := func(opts ...TextDrawOption) *Canvas {
+			c := newCanvas(t)
+			if err := c.DrawTextAtPoint("Horizontal", config.Point{X: 10, Y: 10}, append(opts, FgColor(image.NewUniform(black)))...); err != nil {
+				t.Fatal(err)
+			}
+			return c
+		}
+		if !sameImage(draw(Vertical(false)).dst, draw().dst) {
+			t.Fatal("disabled vertical text changes the output")
+		}
+	})
+	t.Run("Column is measured by the em boxes", func(t *testing.T) {
+		c := newCanvas(t)
+		w, h, lines, err := c.MeasureText("abc", Vertical(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := c.fdr.Face.Metrics()
+		if len(lines) != 1 || w != m.Height.Ceil() || h != (c.emSize()*3).Ceil() {
+			t.Fatalf("unexpected column: w=%d, h=%d, lines=%q", w, h, lines)
+		}
+	})
+	t.Run("Columns are broken at the maximum height", func(t *testing.T) {
+		c := newCanvas(t)
+		maxHeight := (c.emSize() * 3).Ceil()
+		_, _, lines, err := c.MeasureText("abcdef", Vertical(true), MaxHeight(maxHeight))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) != 2 || lines[0] != "abc" || lines[1] != "def" {
+			t.Fatalf("unexpected columns: %q", lines)
+		}
+	})
+	t.Run("Columns are laid from right to left", func(t *testing.T) {
+		c := newCanvas(t)
+		start := config.Point{X: 200, Y: 10}
+		col := c.fdr.Face.Metrics().Height.Ceil()
+		maxHeight := (c.emSize() * 3).Ceil()
+		if err := c.DrawTextAtPoint("IIIIII", start, Vertical(true), MaxHeight(maxHeight), FgColor(image.NewUniform(black))); err != nil {
+			t.Fatal(err)
+		}
+		b := inkBounds(c.dst)
+		if b.Max.X > start.X || b.Min.X < start.X-2*col || b.Min.Y < start.Y || b.Max.Y > start.Y+maxHeight {
+			t.Fatalf("vertical text is drawn out of the block: %v", b)
+		}
+		if !hasInk(c.dst, image.Rect(start.X-col, start.Y, start.X, start.Y+maxHeight)) ||
+			!hasInk(c.dst, image.Rect(start.X-2*col, start.Y, start.X-col, start.Y+maxHeight)) {
+			t.Fatal("columns are not drawn from right to left")
+		}
+	})
+}
+
+func hasInk(img *image.RGBA, r image.Rectangle) bool {
+	return !inkBounds(img.SubImage(r).(*image.RGBA)).Empty()
+}
@@ -0,0 +1,519 @@
+// Package card draws the card of a Hugo post on a template as configured.
+package card
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/canvas/resize"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+// Config is the configuration to generate cards.
+type Config struct {
+	// Drawing is the drawing configuration, which must be defaulted by config.Defaulting.
+	Drawing *config.DrawingConfig
+	// Fonts is the font family to draw texts.
+	Fonts *fontfamily.FontFamily
+	// Template is the background image of cards.
+	Template image.Image
+	// PostProcessors are applied to every card after the standard drawing.
+	PostProcessors []PostProcessor
+}
+
+// Option customizes the generation of a card.
+type Option func(*options)
+
+type options struct {
+	contentPath string
+	now         time.Time
+	layout      *canvas.Layout
+	loadImage   func(src string) (image.Image, error)
+
+	missingGlyph func(*canvas.MissingGlyphError) error
+}
+
+// ContentPath sets the path of the content which the front-matter is parsed from.
+// Relative resource paths in the front-matter (e.g. "avatar") are resolved from its directory,
+// otherwise from the working directory.
+func ContentPath(path string) Option {
+	return func(o *options) {
+		o.contentPath = path
+	}
+}
+
+// Now sets the time which relative dates are formatted against. The default is the current time.
+func Now(t time.Time) Option {
+	return func(o *options) {
+		o.now = t
+	}
+}
+
+// RecordLayout records the texts and the boxes of the card into the layout instead of drawing them, as
+// canvas.Canvas.Record does, so that the card can be rendered as vector graphics (e.g. by svg.Renderer)
+// on the returned canvas.
+func RecordLayout(l *canvas.Layout) Option {
+	return func(o *options) {
+		o.layout = l
+	}
+}
+
+// ImageLoader sets the function which loads the images drawn on the card, such as the avatar and the brand
+// logo, from their paths or URLs, e.g. to load each of them once for all the cards. The default is canvas.LoadImage.
+func ImageLoader(load func(src string) (image.Image, error)) Option {
+	return func(o *options) {
+		o.loadImage = load
+	}
+}
+
+// MissingGlyphs calls the handler with each character of the texts of the card which the fonts do not have,
+// as canvas.Canvas.ReportMissingGlyphs does. The card fails with the error of the handler if it returns one.
+func MissingGlyphs(handler func(*canvas.MissingGlyphError) error) Option {
+	return func(o *options) {
+		o.missingGlyph = handler
+	}
+}
+
+// Generate draws the card of the front-matter and returns the canvas, so that the caller can save or encode it.
+func Generate(cfg Config, fm *hugo.FrontMatter, opts ...Option) (*canvas.Canvas, error) {
+	c, cnf, o, err := newCard(cfg, fm, opts)
+	if err != nil {
+		return nil, err
+	}
+	ffa, pps := cfg.Fonts, cfg.PostProcessors
+	if o.layout != nil {
+		c.Record(o.layout)
+	}
+	c.ReportMissingGlyphs(o.missingGlyph)
+
+	/* Top border */
+	if *cnf.TopBorder.Enabled {
+		if err := drawTopBorder(c, fm, cnf.TopBorder); err != nil {
+			return nil, err
+		}
+	}
+	/* Brand logo */
+	if *cnf.Brand.Enabled && *cnf.Brand.Logo.Enabled && cnf.Brand.Logo.Src != "" {
+		if err := drawImage(c, o.loadImage, "logo", cnf.Brand.Logo.Src, cnf.Brand.Logo); err != nil {
+			return nil, err
+		}
+	}
+	/* Texts */
+	if err := layoutTexts(drawer{c: c}, ffa, cnf, fm, o.now); err != nil {
+		return nil, err
+	}
+
+	/* Avatar */
+	if *cnf.Avatar.Enabled {
+		if err := drawAvatar(c, o.loadImage, o.contentPath, fm, cnf.Avatar); err != nil {
+			return nil, err
+		}
+	}
+
+	/* Draft */
+	if fm.Draft && *cnf.Draft.Enabled {
+		if err := c.DrawRotatedText(
+			cnf.Draft.Text,
+			*cnf.Draft.Start,
+			*cnf.Draft.Angle,
+			canvas.FgHexColor(cnf.Draft.FgHexColor),
+			canvas.TextOpacity(*cnf.Draft.Opacity),
+			canvas.LetterSpacing(cnf.Draft.LetterSpacing),
+			canvas.TextUnderline(cnf.Draft.Underline),
+			canvas.TextStrikethrough(cnf.Draft.Strikethrough),
+			canvas.TextDirection(cnf.TextDirection),
+			canvas.FontHinting(cnf.Draft.Hinting),
+			canvas.ClipRect(cnf.Draft.ClipRect.Rectangle()),
+			canvas.FontFaceFromFFA(ffa, cnf.Draft.FontStyle, cnf.Draft.FontSize),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := postProcess(c, fm, pps); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newCard creates the canvas of the card on the template, and resolves the configuration of the post on it.
+func newCard(cfg Config, fm *hugo.FrontMatter, opts []Option) (*canvas.Canvas, *config.DrawingConfig, *options, error) {
+	if cfg.Drawing == nil || cfg.Fonts == nil || cfg.Template == nil {
+		return nil, nil, nil, errors.New("drawing configuration, fonts, and template are required")
+	}
+	o := &options{now: time.Now(), loadImage: func(src string) (image.Image, error) { return canvas.LoadImage(src) }}
+	for _, f := range opts {
+		f(o)
+	}
+	c, err := newCanvas(cfg.Template, cfg.Drawing.Size)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// the percentages of the positions are of the card, whose size is known once the template is resized,
+	// and the styles of the card are overridden by the front-matter of the post
+	size := c.Bounds().Size()
+	return c, cfg.Drawing.Override(fm.Overrides).Resolve(size.X, size.Y), o, nil
+}
+
+// textLayout lays out the texts of a card. Generate draws them, and Lint checks where they are laid out.
+type textLayout interface {
+	// text lays out the text at the start point. The field is the configuration field of the text.
+	text(field, s string, start config.Point, to *config.TextOption, maxLines int, opts ...canvas.TextDrawOption) error
+	// boxTexts lays out the texts in boxes, which are limited and title-cased as configured.
+	boxTexts(field string, texts []string, bto *config.BoxTextsOption, opts ...canvas.TextDrawOption) error
+}
+
+// layoutTexts lays out the texts of the front-matter with the resolved configuration in the drawing order.
+func layoutTexts(tl textLayout, ffa *fontfamily.FontFamily, cnf *config.DrawingConfig, fm *hugo.FrontMatter, now time.Time) error {
+	dir := directionOptions(fm, cnf)
+	/* Brand */
+	if *cnf.Brand.Enabled && cnf.Brand.Text != "" {
+		if err := tl.text("brand", cnf.Brand.Text, *cnf.Brand.Start, &cnf.Brand.TextOption, 0,
+			textOptions(ffa, &cnf.Brand.TextOption, dir...)...); err != nil {
+			return err
+		}
+	}
+	/* Title */
+	if err := tl.text("title", fm.Title, *cnf.Title.Start, &cnf.Title.TextOption, cnf.Title.MaxLines,
+		textOptions(ffa, &cnf.Title.TextOption, append(multiLineTextOptions(ffa, cnf.Title), dir...)...)...); err != nil {
+		return err
+	}
+	/* Description */
+	if *cnf.Description.Enabled && fm.Description != "" {
+		if err := tl.text("description", fm.Description, *cnf.Description.Start, &cnf.Description.TextOption, cnf.Description.MaxLines,
+			textOptions(ffa, &cnf.Description.TextOption, append(multiLineTextOptions(ffa, cnf.Description), dir...)...)...); err != nil {
+			return err
+		}
+	}
+	/* Category */
+	if *cnf.Categories.Enabled {
+		if err := tl.boxTexts("categories", fm.Categories, cnf.Categories, boxTextsOptions(ffa, cnf.Categories, dir...)...); err != nil {
+			return err
+		}
+	} else if err := tl.text("category", fm.Category, *cnf.Category.Start, cnf.Category, 0,
+		textOptions(ffa, cnf.Category, dir...)...); err != nil {
+		return err
+	}
+	// the authors are parsed in the logical order, and the canvas reorders them into the visual order
+	if err := tl.text("info", infoText(fm, cnf.Info, now), *cnf.Info.Start, cnf.Info, 0,
+		textOptions(ffa, cnf.Info, append([]canvas.TextDrawOption{canvas.VisualOrder(cnf.FrontMatter.Authors.VisualOrder)}, dir...)...)...); err != nil {
+		return err
+	}
+	/* Reading time */
+	if *cnf.ReadingTime.Enabled && fm.WordCount > 0 {
+		if err := tl.text("readingTime", readingTimeText(fm, cnf.ReadingTime), *cnf.ReadingTime.Start, &cnf.ReadingTime.TextOption, 0,
+			textOptions(ffa, &cnf.ReadingTime.TextOption, dir...)...); err != nil {
+			return err
+		}
+	}
+	/* Tags */
+	if *cnf.Tags.Enabled {
+		if err := tl.boxTexts("tags", fm.Tags, cnf.Tags, boxTextsOptions(ffa, cnf.Tags, dir...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawer draws the texts on the canvas.
+type drawer struct {
+	c *canvas.Canvas
+}
+
+func (d drawer) text(_, s string, start config.Point, _ *config.TextOption, _ int, opts ...canvas.TextDrawOption) error {
+	return d.c.DrawTextAtPoint(s, start, opts...)
+}
+
+func (d drawer) boxTexts(_ string, texts []string, bto *config.BoxTextsOption, opts ...canvas.TextDrawOption) error {
+	return d.c.DrawBoxTexts(boxTexts(texts, bto), *bto.Start, opts...)
+}
+
+// infoText returns the text of the authors and the date.
+func infoText(fm *hugo.FrontMatter, to *config.TextOption, now time.Time) string {
+	return fmt.Sprintf("%s%s%s", fm.Authors, to.Separator, formatDate(fm.Date, now, to))
+}
+
+// readingTimeText returns the text of the reading time.
+func readingTimeText(fm *hugo.FrontMatter, rto *config.ReadingTimeOption) string {
+	return fmt.Sprintf(rto.Format, fm.ReadingTime(rto.WordsPerMinute))
+}
+
+// formatDate formats the date as configured, relative to now if the relative date is enabled.
+func formatDate(t, now time.Time, to *config.TextOption) string {
+	if to.RelativeDate {
+		return hugo.FormatRelativeLocalized(t, now, to.TimeLocale)
+	}
+	return hugo.FormatLocalized(t, to.TimeLocale, to.TimeFormat)
+}
+
+// directionOptions returns the options of the direction and the language of the texts of the post.
+func directionOptions(fm *hugo.FrontMatter, cnf *config.DrawingConfig) []canvas.TextDrawOption {
+	return []canvas.TextDrawOption{
+		canvas.TextDirection(cnf.TextDirection),
+		canvas.Lang(postLang(fm, cnf)),
+	}
+}
+
+// postLang returns the language of the post, or the default language if the post does not define it.
+func postLang(fm *hugo.FrontMatter, cnf *config.DrawingConfig) string {
+	if fm.Lang != "" {
+		return fm.Lang
+	}
+	return cnf.FrontMatter.DefaultLang
+}
+
+// boxTexts returns the texts limited and title-cased as configured.
+func boxTexts(texts []string, bto *config.BoxTextsOption) []string {
+	lim := len(texts)
+	if l := bto.Limit; l > 0 && l <= lim {
+		lim = l
+	}
+	var bts []string
+	for _, t := range texts[:lim] {
+		if *bto.TitleCaseEnabled {
+			t = strings.Title(t)
+		}
+		bts = append(bts, t)
+	}
+	return bts
+}
+
+// boxTextsOptions returns the draw options of the texts in boxes followed by the extra options.
+func boxTextsOptions(ffa *fontfamily.FontFamily, bto *config.BoxTextsOption, extra ...canvas.TextDrawOption) []canvas.TextDrawOption {
+	opts := append([]canvas.TextDrawOption{
+		canvas.BgHexColor(bto.BgHexColor),
+		canvas.BoxPadding(*bto.BoxPadding),
+		canvas.BoxSpacing(*bto.BoxSpacing),
+		canvas.BoxAlign(bto.BoxAlign),
+		canvas.BoxTextVAlign(bto.BoxTextVAlign),
+		canvas.BoxCornerRadius(bto.BoxCornerRadius),
+		canvas.BoxMaxWidth(bto.BoxMaxWidth),
+		canvas.BoxRowMaxWidth(bto.MaxWidth),
+		canvas.BoxRowGap(*bto.RowGap),
+		canvas.BoxBorderHexColor(bto.BoxBorderHexColor),
+		canvas.BoxBorderWidth(bto.BoxBorderWidth),
+		canvas.MeasureBounds(bto.MeasureBounds),
+		canvas.BoxHexColors(bto.BoxHexColors),
+	}, extra...)
+	return textOptions(ffa, &bto.TextOption, opts...)
+}
+
+// drawTopBorder draws the top border in the color of the post category.
+func drawTopBorder(c *canvas.Canvas, fm *hugo.FrontMatter, bo *config.BorderOption) error {
+	hex := bo.HexColor
+	for _, cat := range fm.Categories {
+		if h, ok := bo.CategoryHexColors[cat]; ok {
+			hex = h
+			break
+		}
+	}
+	color, err := canvas.Hex(hex)
+	if err != nil {
+		return err
+	}
+	if bo.ThicknessPercent > 0 {
+		return c.DrawTopBorderPercent(color, bo.ThicknessPercent)
+	}
+	return c.DrawTopBorder(color, bo.Thickness)
+}
+
+// drawAvatar draws the avatar of the front-matter, or the configured one if the front-matter does not have it.
+// A relative avatar path in the front-matter is resolved from the content directory.
+func drawAvatar(c *canvas.Canvas, load func(string) (image.Image, error), contentPath string, fm *hugo.FrontMatter, imo *config.ImageOption) error {
+	src := imo.Src
+	if fm.Avatar != "" {
+		src = fm.Avatar
+		if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+			p, err := hugo.NewContent(contentPath).Resource(src)
+			if err != nil {
+				return fmt.Errorf("failed to find avatar: %w", err)
+			}
+			src = p
+		}
+	}
+	if src == "" {
+		return nil
+	}
+	return drawImage(c, load, "avatar", src, imo)
+}
+
+// drawImage draws the image loaded by the function from the path or URL as configured. The name is used in
+// the errors.
+func drawImage(c *canvas.Canvas, load func(string) (image.Image, error), name, src string, imo *config.ImageOption) error {
+	img, err := load(src)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", name, err)
+	}
+	return c.DrawImageAtPoint(img, *imo.Start,
+		canvas.ImageWidth(imo.Width),
+		canvas.ImageHeight(imo.Height),
+		canvas.CircleClip(*imo.Circle),
+	)
+}
+
+// Bounds returns the bounds of the card generated on the template, which is resized if the size is configured.
+func Bounds(tpl image.Image, so *config.SizeOption) image.Rectangle {
+	if so == nil || so.Width == 0 || so.Height == 0 {
+		return tpl.Bounds()
+	}
+	return image.Rect(0, 0, so.Width, so.Height)
+}
+
+// Background returns the background of the card of the size preset without a template, which is filled
+// with the color, or the gradient from top to bottom of the two colors.
+func Background(so *config.SizeOption) (image.Image, error) {
+	from, err := canvas.Hex(so.FillHexColors[0])
+	if err != nil {
+		return nil, err
+	}
+	to := from
+	if len(so.FillHexColors) > 1 {
+		if to, err = canvas.Hex(so.FillHexColors[1]); err != nil {
+			return nil, err
+		}
+	}
+	c, err := canvas.CreateCanvasWithGradient(so.Width, so.Height, from, to, 90)
+	if err != nil {
+		return nil, err
+	}
+	return c.Image(), nil
+}
+
+// HeroBackground returns the background of the card on the featured image of the post, which is resized to
+// cover the bounds of the card and darkened by the ratio (0-1).
+func HeroBackground(img image.Image, bounds image.Rectangle, darken float64) (image.Image, error) {
+	c, err := canvas.CreateCanvasFromImageResized(img, bounds.Dx(), bounds.Dy(), resize.ModeCover)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.ApplyOverlay(image.NewUniform(color.Black), darken); err != nil {
+		return nil, err
+	}
+	return c.Image(), nil
+}
+
+// newCanvas creates a canvas from the template, resized if the size is configured.
+func newCanvas(tpl image.Image, so *config.SizeOption) (*canvas.Canvas, error) {
+	tpl, err := Resize(tpl, so)
+	if err != nil {
+		return nil, err
+	}
+	return canvas.CreateCanvasFromImage(tpl)
+}
+
+// Resize returns the template resized to the configured size, or the template itself if the size is not
+// configured or the template is already of the size. Callers generating many cards on the same template
+// can resize it once, and the cards are generated on it without resizing.
+func Resize(tpl image.Image, so *config.SizeOption) (image.Image, error) {
+	if so == nil || so.Width == 0 || so.Height == 0 || tpl.Bounds() == image.Rect(0, 0, so.Width, so.Height) {
+		return tpl, nil
+	}
+	c, err := canvas.CreateCanvasFromImageResized(tpl, so.Width, so.Height, so.Mode)
+	if err != nil {
+		return nil, err
+	}
+	return c.Image(), nil
+}
+
+// RenderToImage draws the card of the content on the template and returns it without saving.
+// The clock provides the date of the content which does not define it, so that identical inputs
+// always produce the identical image with a fixed clock.
+func RenderToImage(contentPath string, cfg Config, clock func() time.Time) (*image.RGBA, error) {
+	if cfg.Drawing == nil {
+		return nil, errors.New("drawing configuration is required")
+	}
+	now := clock()
+	fm, err := hugo.ParseFrontMatter(io.Discard, contentPath, now, ParseOptions(cfg.Drawing)...)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Generate(cfg, fm, ContentPath(contentPath), Now(now))
+	if err != nil {
+		return nil, err
+	}
+	return c.Image(), nil
+}
+
+// ParseOptions returns the options to parse front-matters as configured.
+func ParseOptions(cnf *config.DrawingConfig) []hugo.ParseOption {
+	return []hugo.ParseOption{
+		hugo.AuthorsLimit(*cnf.FrontMatter.Authors.Limit),
+		hugo.AuthorsSeparator(cnf.FrontMatter.Authors.Separator),
+		hugo.AuthorsOverflowSuffix(cnf.FrontMatter.Authors.OverflowSuffix),
+		hugo.DefaultLang(cnf.FrontMatter.DefaultLang),
+		hugo.DateKeys(cnf.FrontMatter.DateKeys...),
+		hugo.RequireDate(cnf.FrontMatter.RequireDate),
+		hugo.DefaultDate(defaultDate(cnf.FrontMatter.DefaultDate)),
+		hugo.CategoryFromFirstTag(cnf.FrontMatter.CategoryFromFirstTag),
+		hugo.CategoryLimit(*cnf.FrontMatter.CategoryLimit),
+		hugo.OverflowMarker(cnf.FrontMatter.OverflowMarker),
+		hugo.TrimSpace(*cnf.FrontMatter.TrimSpace),
+		hugo.CollapseSpaces(cnf.FrontMatter.CollapseSpaces),
+		hugo.Quotes(cnf.FrontMatter.Quotes),
+		hugo.ExcerptLength(cnf.FrontMatter.ExcerptLength),
+		hugo.TruncateAtBoundary(cnf.FrontMatter.TruncateAtBoundary),
+		hugo.TitleFallback(cnf.FrontMatter.TitleFallback, cnf.FrontMatter.TitleFallbackText),
+		hugo.Widths(hugo.FieldWidths{
+			Title:       *cnf.FrontMatter.Widths.Title,
+			Description: *cnf.FrontMatter.Widths.Description,
+			Authors:     *cnf.FrontMatter.Widths.Authors,
+			Category:    *cnf.FrontMatter.Widths.Category,
+			Tag:         *cnf.FrontMatter.Widths.Tag,
+		}),
+	}
+}
+
+// defaultDate returns the parsed default date, or the zero time to use the current time if it is not set.
+// It is validated with the configuration.
+func defaultDate(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, _ := hugo.ParseDate(s)
+	return t
+}
+
+// textOptions returns the draw options of the text element followed by the extra options.
+func textOptions(ffa *fontfamily.FontFamily, to *config.TextOption, extra ...canvas.TextDrawOption) []canvas.TextDrawOption {
+	return append([]canvas.TextDrawOption{
+		canvas.FgHexColor(to.FgHexColor),
+		canvas.TextStrokeHex(to.StrokeHexColor, to.StrokeWidth),
+		canvas.TextShadowHex(to.ShadowHexColor, *to.ShadowOffset, to.ShadowBlur),
+		canvas.Scrim(to.ScrimStrength, to.ScrimBlur, to.ScrimPadding),
+		canvas.LetterSpacing(to.LetterSpacing),
+		canvas.DisableKerning(to.DisableKerning),
+		canvas.TextUnderline(to.Underline),
+		canvas.TextStrikethrough(to.Strikethrough),
+		canvas.Subpixel(to.Subpixel),
+		canvas.GammaCorrectText(to.GammaCorrect),
+		canvas.FontHinting(to.Hinting),
+		canvas.ClipRect(to.ClipRect.Rectangle()),
+		canvas.FontFaceFromFFA(ffa, to.FontStyle, to.FontSize),
+	}, extra...)
+}
+
+func multiLineTextOptions(ffa *fontfamily.FontFamily, mto *config.MultiLineTextOption) []canvas.TextDrawOption {
+	opts := []canvas.TextDrawOption{
+		canvas.MaxWidth(mto.MaxWidth),
+		canvas.LineSpacing(*mto.LineSpacing),
+		canvas.LineHeight(mto.LineHeight),
+		canvas.Hyphenate(mto.Hyphenate),
+		canvas.Vertical(mto.Vertical),
+		canvas.MaxHeight(mto.MaxHeight),
+	}
+	if mto.MaxWidthPercent > 0 {
+		opts = append(opts, canvas.MaxWidthPercent(mto.MaxWidthPercent))
+	}
+	if mto.MinFontSize > 0 {
+		opts = append(opts, canvas.AutoFit(ffa, mto.FontStyle, mto.MinFontSize, mto.FontSize, mto.MaxLines))
+	}
+	return opts
+}
@@ -0,0 +1,331 @@
+package card
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+	"github.com/shunk031/tcardgen/pkg/style"
+)
+
+func TestGenerate(t *testing.T) {
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(tpl, tpl.Bounds(), image.White, image.Point{}, draw.Src)
+	cnf := &config.DrawingConfig{}
+	config.Defaulting(cnf, "")
+	fm := &hugo.FrontMatter{
+		Title:    "Title",
+		Authors:  "alice",
+		Category: "program",
+		Tags:     []string{"go"},
+		Date:     time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	c, err := Generate(Config{Drawing: cnf, Fonts: testfont.New(t), Template: tpl}, fm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Bounds(); got != tpl.Bounds() {
+		t.Fatalf("unexpected bounds: got=%v, want=%v", got, tpl.Bounds())
+	}
+	// the title is drawn at the configured position
+	var drawn bool
+	for y := cnf.Title.Start.Y; y < cnf.Title.Start.Y+80 && !drawn; y++ {
+		for x := cnf.Title.Start.X; x < cnf.Title.Start.X+200; x++ {
+			if c.Image().RGBAAt(x, y) != (color.RGBA{255, 255, 255, 255}) {
+				drawn = true
+				break
+			}
+		}
+	}
+	if !drawn {
+		t.Fatal("title is not drawn")
+	}
+
+	if _, err := Generate(Config{Drawing: cnf, Template: tpl}, fm); err == nil {
+		t.Fatal("expected an error without fonts")
+	}
+	// the title is empty with the None fallback
+	fm.Title = ""
+	if _, err := Generate(Config{Drawing: cnf, Fonts: testfont.New(t), Template: tpl}, fm); err != nil {
+		t.Fatalf("failed to generate a card without the title: %v", err)
+	}
+}
+
+func TestGenerateResolvesAvatarFromContentPath(t *testing.T) {
+	dir := t.TempDir()
+	red := color.RGBA{255, 0, 0, 255}
+	avatar := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(avatar, avatar.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+	if err := canvas.SaveAsPNG(filepath.Join(dir, "avatar.png"), avatar); err != nil {
+		t.Fatal(err)
+	}
+
+	cnf := &config.DrawingConfig{Avatar: &config.ImageOption{
+		Enabled: ptrBool(true),
+		Start:   &config.Point{X: 10, Y: 10},
+		Width:   20,
+		Height:  20,
+		Circle:  ptrBool(false),
+	}}
+	config.Defaulting(cnf, "")
+	cfg := Config{Drawing: cnf, Fonts: testfont.New(t), Template: image.NewRGBA(image.Rect(0, 0, 1200, 630))}
+	fm := &hugo.FrontMatter{Title: "Title", Avatar: "avatar.png"}
+
+	c, err := Generate(cfg, fm, ContentPath(filepath.Join(dir, "post.md")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Image().RGBAAt(20, 20); got != red {
+		t.Fatalf("avatar is not drawn: got=%v", got)
+	}
+}
+
+func TestGenerateDrawsBrandLogo(t *testing.T) {
+	dir := t.TempDir()
+	red := color.RGBA{255, 0, 0, 255}
+	logo := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	draw.Draw(logo, logo.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+	src := filepath.Join(dir, "logo.png")
+	if err := canvas.SaveAsPNG(src, logo); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc   string
+		brand  *config.BrandOption
+		expect bool
+	}{
+		{
+			desc:   "Logo is drawn without the text",
+			brand:  &config.BrandOption{Logo: &config.ImageOption{Src: src, Start: &config.Point{X: 10, Y: 10}, Height: 20}},
+			expect: true,
+		},
+		{
+			desc:   "Logo is not drawn without the src",
+			brand:  &config.BrandOption{Logo: &config.ImageOption{Start: &config.Point{X: 10, Y: 10}, Height: 20}},
+			expect: false,
+		},
+		{
+			desc:   "Logo is not drawn when the brand is disabled",
+			brand:  &config.BrandOption{TextOption: config.TextOption{Enabled: ptrBool(false)}, Logo: &config.ImageOption{Src: src, Start: &config.Point{X: 10, Y: 10}, Height: 20}},
+			expect: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cnf := &config.DrawingConfig{Brand: tc.brand}
+			config.Defaulting(cnf, "")
+			tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+			c, err := Generate(Config{Drawing: cnf, Fonts: testfont.New(t), Template: tpl}, &hugo.FrontMatter{Title: "Title"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the logo is scaled to 40x20 keeping the aspect ratio
+			if got := c.Image().RGBAAt(45, 25) == red; got != tc.expect {
+				t.Fatalf("logo is drawn=%v, want=%v", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestGenerateDrawsAllCategories(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(tpl, tpl.Bounds(), image.White, image.Point{}, draw.Src)
+	cnf := &config.DrawingConfig{Categories: &config.BoxTextsOption{
+		Enabled:    ptrBool(true),
+		TextOption: config.TextOption{Start: &config.Point{X: 100, Y: 100}},
+		BgHexColor: "#FF0000",
+	}}
+	config.Defaulting(cnf, "")
+	fm := &hugo.FrontMatter{
+		Title:      "Title",
+		Category:   "program",
+		Categories: []string{"program", "go", "hugo"},
+	}
+
+	boxes := func(limit int) int {
+		t.Helper()
+		cnf.Categories.Limit = limit
+		c, err := Generate(Config{Drawing: cnf, Fonts: testfont.New(t), Template: tpl}, fm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// count the boxes on the row, which are separated by the white template
+		var n int
+		var inBox bool
+		for x := 0; x < c.Bounds().Dx(); x++ {
+			isRed := c.Image().RGBAAt(x, cnf.Categories.Start.Y+1) == red
+			if isRed && !inBox {
+				n++
+			}
+			inBox = isRed
+		}
+		return n
+	}
+	if got := boxes(0); got != 3 {
+		t.Fatalf("unexpected number of category boxes: got=%d, want=3", got)
+	}
+	if got := boxes(2); got != 2 {
+		t.Fatalf("unexpected number of limited category boxes: got=%d, want=2", got)
+	}
+}
+
+func TestGenerateDrawsDescription(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(tpl, tpl.Bounds(), image.White, image.Point{}, draw.Src)
+
+	testCases := []struct {
+		desc        string
+		enabled     bool
+		description string
+		expectInk   bool
+	}{
+		{desc: "Description is drawn when enabled", enabled: true, description: "Summary of the post", expectInk: true},
+		{desc: "Empty description is not drawn", enabled: true, description: "", expectInk: false},
+		{desc: "Description is not drawn when disabled", enabled: false, description: "Summary of the post", expectInk: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cnf := &config.DrawingConfig{Description: &config.MultiLineTextOption{
+				TextOption: config.TextOption{FgHexColor: "#FF0000"},
+				Enabled:    ptrBool(tc.enabled),
+			}}
+			config.Defaulting(cnf, "")
+			fm := &hugo.FrontMatter{Title: "Title", Description: tc.description}
+			c, err := Generate(Config{Drawing: cnf, Fonts: testfont.New(t), Template: tpl}, fm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ink bool
+			for y := 0; y < c.Bounds().Dy() && !ink; y++ {
+				for x := 0; x < c.Bounds().Dx(); x++ {
+					if c.Image().RGBAAt(x, y) == red {
+						ink = true
+						break
+					}
+				}
+			}
+			if ink != tc.expectInk {
+				t.Fatalf("unexpected description ink: got=%v, want=%v", ink, tc.expectInk)
+			}
+		})
+	}
+}
+
+func TestGenerateRecordsLayout(t *testing.T) {
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	draw.Draw(tpl, tpl.Bounds(), image.White, image.Point{}, draw.Src)
+	cnf := &config.DrawingConfig{}
+	config.Defaulting(cnf, "")
+	fm := &hugo.FrontMatter{Title: "Title", Authors: "alice", Tags: []string{"go", "svg"}}
+
+	var l canvas.Layout
+	if _, err := Generate(Config{Drawing: cnf, Fonts: testfont.New(t), Template: tpl}, fm, RecordLayout(&l)); err != nil {
+		t.Fatal(err)
+	}
+	var title bool
+	for _, run := range l.Texts {
+		if run.Text == "Title" && run.X == float64(cnf.Title.Start.X) {
+			title = true
+		}
+	}
+	if !title {
+		t.Fatalf("title is not recorded: %+v", l.Texts)
+	}
+	if len(l.Boxes) != len(fm.Tags) {
+		t.Fatalf("unexpected number of boxes: got=%d, want=%d", len(l.Boxes), len(fm.Tags))
+	}
+}
+
+func TestGenerateResolvesPercentages(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	cnf := &config.DrawingConfig{
+		Title: &config.MultiLineTextOption{TextOption: config.TextOption{Start: &config.Point{XPercent: pct(10), Y: 100}}},
+	}
+	config.Defaulting(cnf, "")
+	fm := &hugo.FrontMatter{Title: "Title", Authors: "alice"}
+
+	for _, w := range []int{1200, 1080} {
+		tpl := image.NewRGBA(image.Rect(0, 0, w, 630))
+		var l canvas.Layout
+		if _, err := Generate(Config{Drawing: cnf, Fonts: testfont.New(t), Template: tpl}, fm, RecordLayout(&l)); err != nil {
+			t.Fatal(err)
+		}
+		var title bool
+		for _, run := range l.Texts {
+			if run.Text == "Title" && run.X == float64(w/10) {
+				title = true
+			}
+		}
+		if !title {
+			t.Fatalf("title is not drawn at 10%% of the width %d: %+v", w, l.Texts)
+		}
+	}
+}
+
+func TestGenerateOverridesStyles(t *testing.T) {
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+	cnf := &config.DrawingConfig{}
+	config.Defaulting(cnf, "")
+	fm := &hugo.FrontMatter{
+		Title:     "Title",
+		Authors:   "alice",
+		Overrides: &style.Overrides{TitleColor: "#FF0000", TitleSize: 40},
+	}
+
+	var l canvas.Layout
+	if _, err := Generate(Config{Drawing: cnf, Fonts: testfont.New(t), Template: tpl}, fm, RecordLayout(&l)); err != nil {
+		t.Fatal(err)
+	}
+	var title bool
+	for _, run := range l.Texts {
+		if run.Text != "Title" {
+			continue
+		}
+		title = true
+		if r, g, b, _ := run.Color.RGBA(); r != 0xffff || g != 0 || b != 0 || run.FontSize != 40 {
+			t.Fatalf("title is not overridden: color=%v, size=%v", run.Color, run.FontSize)
+		}
+	}
+	if !title {
+		t.Fatalf("title is not recorded: %+v", l.Texts)
+	}
+	if cnf.Title.FontSize == 40 {
+		t.Fatal("the configuration is modified by the overrides")
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	now := time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC)
+	date := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		desc   string
+		to     *config.TextOption
+		expect string
+	}{
+		{desc: "Absolute date", to: &config.TextOption{TimeFormat: "Jan 2"}, expect: "Jan 2"},
+		{desc: "Relative date", to: &config.TextOption{RelativeDate: true}, expect: "3 days ago"},
+		{desc: "Localized relative date", to: &config.TextOption{RelativeDate: true, TimeLocale: "ja"}, expect: "3日前"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := formatDate(date, now, tc.to); got != tc.expect {
+				t.Fatalf("formatDate() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func ptrBool(b bool) *bool {
+	return &b
+}
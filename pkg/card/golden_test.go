@@ -0,0 +1,130 @@
+package card
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/config"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+const (
+	// goldenChannelTolerance is the maximum difference of a color channel regarded as the same.
+	goldenChannelTolerance = 2
+	// goldenPixelTolerance is the maximum ratio of pixels allowed to differ from the golden file.
+	goldenPixelTolerance = 0.001
+)
+
+func TestRenderToImageGolden(t *testing.T) {
+	clock := func() time.Time { return time.Date(2021, 7, 4, 12, 0, 0, 0, time.UTC) }
+	testCases := []struct {
+		name string
+		post string
+	}{
+		{name: "post", post: `---
+title: "First post"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["hugo"]
+categories: ["program"]
+---`},
+		{name: "undated", post: `---
+title: "A post without the date which is long enough to be wrapped"
+authors: ["alice", "bob", "carol"]
+tags: ["go", "hugo", "ogp"]
+categories: ["tech"]
+---`},
+		{name: "draft", post: `---
+title: "Draft post"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["hugo"]
+categories: ["program"]
+draft: true
+---`},
+	}
+
+	ffa := testfont.New(t)
+	tpl, err := canvas.LoadFromFile(filepath.Join("..", "..", config.DefaultTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cnf := &config.DrawingConfig{Draft: &config.WatermarkOption{TextOption: config.TextOption{Enabled: ptrBool(true)}}}
+	config.Defaulting(cnf, "")
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := filepath.Join(t.TempDir(), "post.md")
+			if err := os.WriteFile(in, []byte(tc.post), 0644); err != nil {
+				t.Fatal(err)
+			}
+			img, err := RenderToImage(in, Config{Drawing: cnf, Fonts: ffa, Template: tpl}, clock)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "golden", tc.name+".png")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := canvas.SaveAsPNG(golden, img); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := canvas.LoadFromFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create the golden file)", err)
+			}
+			assertImagesMatch(t, img, want)
+
+			// rendering is deterministic
+			again, err := RenderToImage(in, Config{Drawing: cnf, Fonts: ffa, Template: tpl}, clock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again.Pix) != string(img.Pix) {
+				t.Fatal("rendering the same input twice produced different images")
+			}
+		})
+	}
+}
+
+// assertImagesMatch fails unless the images have the same bounds and almost all pixels match
+// within the tolerance.
+func assertImagesMatch(t *testing.T, got, want image.Image) {
+	t.Helper()
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("unexpected bounds: got=%v, want=%v", got.Bounds(), want.Bounds())
+	}
+	b := got.Bounds()
+	var diff int
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			g := color.NRGBAModel.Convert(got.At(x, y)).(color.NRGBA)
+			w := color.NRGBAModel.Convert(want.At(x, y)).(color.NRGBA)
+			if absDiff(g.R, w.R) > goldenChannelTolerance || absDiff(g.G, w.G) > goldenChannelTolerance ||
+				absDiff(g.B, w.B) > goldenChannelTolerance || absDiff(g.A, w.A) > goldenChannelTolerance {
+				diff++
+			}
+		}
+	}
+	if ratio := float64(diff) / float64(b.Dx()*b.Dy()); ratio > goldenPixelTolerance {
+		t.Fatalf("%d pixels (%.3f%%) differ from the golden file", diff, ratio*100)
+	}
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,100 @@
+package card
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+// Problem is a text of a card which does not fit in its region as configured.
+type Problem struct {
+	// Field is the configuration field of the text, e.g. "title" or "tags".
+	Field string
+	// Message describes how the text does not fit.
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Field, p.Message)
+}
+
+// Lint lays out the texts of the card of the front-matter as Generate does without drawing them, and reports
+// the texts which do not fit: the texts overflowing the card or their clip rectangles, the multi-line texts
+// wrapped into more lines than maxLines, and the texts truncated in boxes. Nothing is reported for a card
+// which fits.
+func Lint(cfg Config, fm *hugo.FrontMatter, opts ...Option) ([]Problem, error) {
+	c, cnf, o, err := newCard(cfg, fm, opts)
+	if err != nil {
+		return nil, err
+	}
+	l := &linter{c: c}
+	if err := layoutTexts(l, cfg.Fonts, cnf, fm, o.now); err != nil {
+		return nil, err
+	}
+	return l.problems, nil
+}
+
+// linter collects the problems of the texts laid out on the canvas.
+type linter struct {
+	c        *canvas.Canvas
+	problems []Problem
+}
+
+func (l *linter) report(field, format string, args ...any) {
+	l.problems = append(l.problems, Problem{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// text checks the text laid out at the start point. The lines are not checked if maxLines is 0.
+func (l *linter) text(field, s string, start config.Point, to *config.TextOption, maxLines int, opts ...canvas.TextDrawOption) error {
+	if s == "" {
+		return nil
+	}
+	bounds, lines, err := l.c.TextBounds(s, start, opts...)
+	if err != nil {
+		return err
+	}
+	if maxLines > 0 && len(lines) > maxLines {
+		l.report(field, "wraps into %d lines, more than maxLines %d", len(lines), maxLines)
+	}
+	l.bounds(field, bounds, to)
+	return nil
+}
+
+// boxTexts checks the texts laid out in boxes.
+func (l *linter) boxTexts(field string, texts []string, bto *config.BoxTextsOption, opts ...canvas.TextDrawOption) error {
+	texts = boxTexts(texts, bto)
+	if len(texts) == 0 {
+		return nil
+	}
+	bounds, boxed, err := l.c.BoxTextsBounds(texts, *bto.Start, opts...)
+	if err != nil {
+		return err
+	}
+	for i, s := range boxed {
+		if s != texts[i] {
+			l.report(field, "%q is truncated to %q by boxMaxWidth %d", texts[i], s, bto.BoxMaxWidth)
+		}
+	}
+	l.bounds(field, bounds, &bto.TextOption)
+	return nil
+}
+
+// bounds checks that the bounds of the text are in the card and in the clip rectangle of the text.
+func (l *linter) bounds(field string, bounds image.Rectangle, to *config.TextOption) {
+	card := l.c.Bounds()
+	if d := bounds.Max.X - card.Max.X; d > 0 {
+		l.report(field, "overflows the right edge of the card by %dpx", d)
+	}
+	if d := card.Min.X - bounds.Min.X; d > 0 {
+		l.report(field, "overflows the left edge of the card by %dpx", d)
+	}
+	if d := bounds.Max.Y - card.Max.Y; d > 0 {
+		l.report(field, "overflows the bottom edge of the card by %dpx", d)
+	}
+	if clip := to.ClipRect.Rectangle(); !clip.Empty() && !bounds.In(clip) {
+		l.report(field, "is clipped by clipRect %v", clip)
+	}
+}
@@ -0,0 +1,77 @@
+package card
+
+import (
+	"image"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/config"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+func TestLint(t *testing.T) {
+	ffa := testfont.New(t)
+	tpl := image.NewRGBA(image.Rect(0, 0, 1200, 630))
+
+	testCases := []struct {
+		desc   string
+		cnf    *config.DrawingConfig
+		fm     *hugo.FrontMatter
+		expect []string
+	}{
+		{
+			desc:   "Card which fits has no problems",
+			cnf:    &config.DrawingConfig{},
+			fm:     &hugo.FrontMatter{Title: "Title", Authors: "alice", Category: "program", Tags: []string{"go"}},
+			expect: nil,
+		},
+		{
+			desc:   "Title wrapped into more lines than maxLines and overflowing the bottom edge",
+			cnf:    &config.DrawingConfig{},
+			fm:     &hugo.FrontMatter{Title: strings.Repeat("A very long title ", 30), Authors: "alice"},
+			expect: []string{"title: wraps into 19 lines, more than maxLines 3", "title: overflows the bottom edge of the card by"},
+		},
+		{
+			desc:   "Category overflowing the right edge",
+			cnf:    &config.DrawingConfig{Category: &config.TextOption{Start: &config.Point{X: 1150, Y: 100}}},
+			fm:     &hugo.FrontMatter{Title: "Title", Authors: "alice", Category: "programming"},
+			expect: []string{"category: overflows the right edge of the card by"},
+		},
+		{
+			desc: "Truncated tag",
+			cnf: &config.DrawingConfig{Tags: &config.BoxTextsOption{
+				TextOption:  config.TextOption{Start: &config.Point{X: 100, Y: 500}},
+				BoxMaxWidth: 80,
+			}},
+			fm:     &hugo.FrontMatter{Title: "Title", Authors: "alice", Tags: []string{"go", "kubernetes"}},
+			expect: []string{`tags: "Kubernetes" is truncated to "Ku…"`},
+		},
+		{
+			desc: "Title clipped by clipRect",
+			cnf: &config.DrawingConfig{Title: &config.MultiLineTextOption{TextOption: config.TextOption{
+				ClipRect: &config.Rect{X: 0, Y: 0, Width: 200, Height: 100},
+			}}},
+			fm:     &hugo.FrontMatter{Title: "Title", Authors: "alice"},
+			expect: []string{"title: is clipped by clipRect"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			config.Defaulting(tc.cnf, "")
+			problems, err := Lint(Config{Drawing: tc.cnf, Fonts: ffa, Template: tpl}, tc.fm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(problems) != len(tc.expect) {
+				t.Fatalf("unexpected problems: got=%v, want=%q", problems, tc.expect)
+			}
+			for _, want := range tc.expect {
+				if !slices.ContainsFunc(problems, func(p Problem) bool { return strings.HasPrefix(p.String(), want) }) {
+					t.Fatalf("problem %q is not reported: %v", want, problems)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package card
+
+import (
+	"image"
+
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+// PostProcessor applies a custom effect to the rendered card.
+// It runs after the standard drawing and before the card is encoded.
+type PostProcessor interface {
+	Process(dst *image.RGBA, fm *hugo.FrontMatter) error
+}
+
+// PostProcessorFunc is an adapter to allow the use of ordinary functions as PostProcessor.
+type PostProcessorFunc func(dst *image.RGBA, fm *hugo.FrontMatter) error
+
+// Process calls f(dst, fm).
+func (f PostProcessorFunc) Process(dst *image.RGBA, fm *hugo.FrontMatter) error {
+	return f(dst, fm)
+}
+
+// postProcess runs the post processors on the card of the front-matter in order.
+func postProcess(c *canvas.Canvas, fm *hugo.FrontMatter, pps []PostProcessor) error {
+	for _, pp := range pps {
+		if err := pp.Process(c.Image(), fm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/shunk031/tcardgen/pkg/style"
+)
+
+// validateColors checks all the color hexes of the configuration as written in the file, so that an invalid
+// color is reported with its field instead of failing to draw the cards.
+func validateColors(c *DrawingConfig) error {
+	v := &validator{}
+	v.colors(c)
+	return errors.Join(v.errs...)
+}
+
+// colors checks all the color hexes of the configuration, including the ones of the presets and the disabled
+// elements. It is the only check of the colors, which both loading and Validate run.
+func (v *validator) colors(c *DrawingConfig) {
+	if c.Size != nil {
+		v.fillColors("size", c.Size)
+	}
+	if c.Brand != nil {
+		v.textColors("brand", &c.Brand.TextOption)
+	}
+	if c.Title != nil {
+		v.textColors("title", &c.Title.TextOption)
+	}
+	if c.Description != nil {
+		v.textColors("description", &c.Description.TextOption)
+	}
+	if c.Category != nil {
+		v.textColors("category", c.Category)
+	}
+	if c.Info != nil {
+		v.textColors("info", c.Info)
+	}
+	if c.ReadingTime != nil {
+		v.textColors("readingTime", &c.ReadingTime.TextOption)
+	}
+	if c.Categories != nil {
+		v.textColors("categories", &c.Categories.TextOption)
+		v.boxColors("categories", c.Categories)
+		v.boxTextColors("categories", c.Categories)
+	}
+	if c.Tags != nil {
+		v.textColors("tags", &c.Tags.TextOption)
+		v.boxColors("tags", c.Tags)
+		v.boxTextColors("tags", c.Tags)
+	}
+	if c.Draft != nil {
+		v.textColors("draft", &c.Draft.TextOption)
+	}
+	if c.TopBorder != nil {
+		v.borderColors("topBorder", c.TopBorder)
+	}
+	if c.Dark != nil {
+		v.variantColors("dark", c.Dark)
+	}
+	for _, name := range sortedKeys(c.Presets) {
+		if so := c.Presets[name]; so != nil {
+			field := "presets." + name
+			v.color(field+".fgHexColor", so.FgHexColor)
+			v.color(field+".strokeHexColor", so.StrokeHexColor)
+			v.color(field+".shadowHexColor", so.ShadowHexColor)
+		}
+	}
+}
+
+// color checks the color hex if it is set. An empty hex is left to the defaults.
+func (v *validator) color(field, hex string) {
+	if hex == "" {
+		return
+	}
+	if _, err := style.ParseHexColor(hex); err != nil {
+		v.errorf(field, "invalid hex color %q", hex)
+	}
+}
+
+func (v *validator) textColors(field string, to *TextOption) {
+	v.color(field+".fgHexColor", to.FgHexColor)
+	v.color(field+".strokeHexColor", to.StrokeHexColor)
+	v.color(field+".shadowHexColor", to.ShadowHexColor)
+}
+
+func (v *validator) boxColors(field string, bto *BoxTextsOption) {
+	v.color(field+".bgHexColor", bto.BgHexColor)
+	v.color(field+".boxBorderHexColor", bto.BoxBorderHexColor)
+}
+
+// boxTextColors checks the colors of the boxes of the texts.
+func (v *validator) boxTextColors(field string, bto *BoxTextsOption) {
+	for _, text := range sortedKeys(bto.BoxHexColors) {
+		if bco := bto.BoxHexColors[text]; bco != nil {
+			v.color(fmt.Sprintf("%s.boxHexColors.%s.bgHexColor", field, text), bco.BgHexColor)
+			v.color(fmt.Sprintf("%s.boxHexColors.%s.fgHexColor", field, text), bco.FgHexColor)
+		}
+	}
+}
+
+func (v *validator) fillColors(field string, so *SizeOption) {
+	for i, hex := range so.FillHexColors {
+		v.color(fmt.Sprintf("%s.fillHexColors[%d]", field, i), hex)
+	}
+}
+
+func (v *validator) borderColors(field string, bo *BorderOption) {
+	v.color(field+".hexColor", bo.HexColor)
+	for _, cat := range sortedKeys(bo.CategoryHexColors) {
+		v.color(fmt.Sprintf("%s.categoryHexColors.%s", field, cat), bo.CategoryHexColors[cat])
+	}
+}
+
+func (v *validator) variantColors(field string, vo *VariantOption) {
+	o := vo.Overrides
+	for _, c := range []struct{ key, hex string }{
+		{"titleColor", o.TitleColor},
+		{"descriptionColor", o.DescriptionColor},
+		{"categoryColor", o.CategoryColor},
+		{"infoColor", o.InfoColor},
+		{"tagsColor", o.TagsColor},
+		{"tagsBgColor", o.TagsBgColor},
+	} {
+		v.color(field+"."+c.key, c.hex)
+	}
+}
+
+// sortedKeys returns the keys of the map in order, so that the errors are reported in a stable order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,21 @@
+package config
+
+import "testing"
+
+func TestLoadConfigColors(t *testing.T) {
+	input := `
+presets:
+  accent:
+    fgHexColor: "#FF000"
+tags:
+  enabled: false
+  bgHexColor: "#12345"
+category:
+  preset: accent
+`
+	_, err := parseConfig([]byte(input))
+	expect := "tags.bgHexColor: invalid hex color \"#12345\"\npresets.accent.fgHexColor: invalid hex color \"#FF000\""
+	if err == nil || err.Error() != expect {
+		t.Fatalf("unexpected error: got=%v, want=%s", err, expect)
+	}
+}
@@ -1,26 +1,81 @@
 package config
 
 import (
+	"image"
+
 	"github.com/shunk031/tcardgen/pkg/canvas/box"
 	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/canvas/resize"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+	"github.com/shunk031/tcardgen/pkg/style"
 )
 
 type DrawingConfig struct {
-	Template string               `json:"template,omitempty"`
-	Title    *MultiLineTextOption `json:"title,omitempty"`
-	Category *TextOption          `json:"category,omitempty"`
-	Info     *TextOption          `json:"info,omitempty"`
-	Tags     *BoxTextsOption      `json:"tags,omitempty"`
+	Template       string               `json:"template,omitempty"`
+	BundleTemplate string               `json:"bundleTemplate,omitempty"`
+	Size           *SizeOption          `json:"size,omitempty"`
+	HeroImage      *HeroImageOption     `json:"heroImage,omitempty"`
+	Brand          *BrandOption         `json:"brand,omitempty"`
+	Title          *MultiLineTextOption `json:"title,omitempty"`
+	Description    *MultiLineTextOption `json:"description,omitempty"`
+	Category       *TextOption          `json:"category,omitempty"`
+	Categories     *BoxTextsOption      `json:"categories,omitempty"`
+	Info           *TextOption          `json:"info,omitempty"`
+	ReadingTime    *ReadingTimeOption   `json:"readingTime,omitempty"`
+	Tags           *BoxTextsOption      `json:"tags,omitempty"`
+	Draft          *WatermarkOption     `json:"draft,omitempty"`
+	Avatar         *ImageOption         `json:"avatar,omitempty"`
+	TopBorder      *BorderOption        `json:"topBorder,omitempty"`
+	TextDirection  box.Direction        `json:"textDirection,omitempty"`
+
+	FrontMatter *FrontMatterOption `json:"frontMatter,omitempty"`
+
+	// Dark is the dark variant of the cards, which is generated besides each card if it is set.
+	Dark *VariantOption `json:"dark,omitempty"`
+
+	Presets map[string]*StyleOption `json:"presets,omitempty"`
 }
 
 type TextOption struct {
+	Preset     string           `json:"preset,omitempty"`
 	Start      *Point           `json:"start,omitempty"`
 	FgHexColor string           `json:"fgHexColor,omitempty"`
 	FontSize   float64          `json:"fontSize,omitempty"`
 	FontStyle  fontfamily.Style `json:"fontStyle,omitempty"`
 	Separator  string           `json:"separator,omitempty"`
 	TimeFormat string           `json:"timeFormat,omitempty"`
+	TimeLocale string           `json:"timeLocale,omitempty"`
 	Enabled    *bool            `json:"enabled,omitempty"`
+
+	// RelativeDate formats the date relative to the generation time (e.g. "3 days ago").
+	RelativeDate bool `json:"relativeDate,omitempty"`
+
+	LetterSpacing int `json:"letterSpacing,omitempty"`
+	// DisableKerning advances each letter by its own width, which aligns the digits of dates in a grid.
+	DisableKerning bool `json:"disableKerning,omitempty"`
+
+	StrokeHexColor string `json:"strokeHexColor,omitempty"`
+	StrokeWidth    int    `json:"strokeWidth,omitempty"`
+	ShadowHexColor string `json:"shadowHexColor,omitempty"`
+	ShadowOffset   *Point `json:"shadowOffset,omitempty"`
+	ShadowBlur     int    `json:"shadowBlur,omitempty"`
+
+	// Scrim darkens and blurs the background behind the text for contrast.
+	ScrimStrength float64 `json:"scrimStrength,omitempty"`
+	ScrimBlur     int     `json:"scrimBlur,omitempty"`
+	ScrimPadding  int     `json:"scrimPadding,omitempty"`
+
+	Underline     bool `json:"underline,omitempty"`
+	Strikethrough bool `json:"strikethrough,omitempty"`
+
+	// Subpixel keeps the fractional positions of the text instead of snapping them to whole pixels.
+	Subpixel bool `json:"subpixel,omitempty"`
+	// GammaCorrect blends the edges of the glyphs in the linear color space, which is slower.
+	GammaCorrect bool `json:"gammaCorrect,omitempty"`
+	// Hinting fits the glyphs to the pixel grid: None (default), Vertical, or Full.
+	Hinting fontfamily.Hinting `json:"hinting,omitempty"`
+	// ClipRect discards the pixels of the text, including its boxes and effects, outside of the rectangle.
+	ClipRect *Rect `json:"clipRect,omitempty"`
 }
 
 type MultiLineTextOption struct {
@@ -28,22 +83,224 @@ type MultiLineTextOption struct {
 	MaxWidth    int   `json:"maxWidth,omitempty"`
 	LineSpacing *int  `json:"lineSpacing,omitempty"`
 	Enabled     *bool `json:"enabled,omitempty"`
+
+	// LineHeight is the distance between the baselines as a multiple of the font size (e.g. 1.5).
+	// It takes precedence over LineSpacing.
+	LineHeight float64 `json:"lineHeight,omitempty"`
+
+	// MaxWidthPercent is the maximum width as a percentage of the canvas width.
+	// It takes precedence over MaxWidth.
+	MaxWidthPercent float64 `json:"maxWidthPercent,omitempty"`
+	// MinFontSize enables auto-fit, which shrinks the font size down to MinFontSize
+	// until the text fits in MaxLines lines.
+	MinFontSize float64 `json:"minFontSize,omitempty"`
+	MaxLines    int     `json:"maxLines,omitempty"`
+	// Hyphenate breaks Latin words wider than the maximum width with a hyphen.
+	Hyphenate bool `json:"hyphenate,omitempty"`
+	// Vertical draws the text top to bottom in columns from right to left, and the start point is
+	// the top right corner. The columns are broken at MaxHeight.
+	Vertical  bool `json:"vertical,omitempty"`
+	MaxHeight int  `json:"maxHeight,omitempty"`
 }
 
 type BoxTextsOption struct {
 	TextOption
-	BgHexColor       string    `json:"bgHexColor,omitempty"`
-	BoxPadding       *Padding  `json:"boxPadding,omitempty"`
-	BoxSpacing       *int      `json:"boxSpacing,omitempty"`
-	BoxAlign         box.Align `json:"boxAlign,omitempty"`
-	Enabled          *bool     `json:"enabled,omitempty"`
-	Limit            int       `json:"limit,omitempty"`
-	TitleCaseEnabled *bool     `json:"titleCaseEnabled,omitempty"`
+	BgHexColor string    `json:"bgHexColor,omitempty"`
+	BoxPadding *Padding  `json:"boxPadding,omitempty"`
+	BoxSpacing *int      `json:"boxSpacing,omitempty"`
+	BoxAlign   box.Align `json:"boxAlign,omitempty"`
+	// BoxTextVAlign aligns the texts vertically in the boxes: Top, Middle (default), or Bottom.
+	BoxTextVAlign     box.VAlign `json:"boxTextVAlign,omitempty"`
+	BoxCornerRadius   int        `json:"boxCornerRadius,omitempty"`
+	BoxMaxWidth       int        `json:"boxMaxWidth,omitempty"`
+	BoxBorderHexColor string     `json:"boxBorderHexColor,omitempty"`
+	BoxBorderWidth    int        `json:"boxBorderWidth,omitempty"`
+	Enabled           *bool      `json:"enabled,omitempty"`
+	Limit             int        `json:"limit,omitempty"`
+	TitleCaseEnabled  *bool      `json:"titleCaseEnabled,omitempty"`
+	// MeasureBounds aligns boxes by the drawn glyph bounds instead of the advance width.
+	MeasureBounds bool `json:"measureBounds,omitempty"`
+	// MaxWidth wraps the boxes into rows no wider than it, which are laid out below each other by RowGap
+	// (BoxSpacing by default). The boxes are laid out in a row if it is 0.
+	MaxWidth int  `json:"maxWidth,omitempty"`
+	RowGap   *int `json:"rowGap,omitempty"`
+	// BoxHexColors overrides the colors of the boxes by their texts, which are matched case-insensitively.
+	BoxHexColors map[string]*BoxColorOption `json:"boxHexColors,omitempty"`
+}
+
+// BoxColorOption is the colors of a box. An empty color falls back to the color of the boxes.
+type BoxColorOption struct {
+	BgHexColor string `json:"bgHexColor,omitempty"`
+	FgHexColor string `json:"fgHexColor,omitempty"`
+}
+
+// BrandOption is a text drawn on every card regardless of the post's front-matter
+// (e.g. a site name). The logo is drawn if its src is set, with or without the text.
+type BrandOption struct {
+	TextOption
+	Text string       `json:"text,omitempty"`
+	Logo *ImageOption `json:"logo,omitempty"`
+}
+
+// ReadingTimeOption is the reading time of the post computed from the word count of its body.
+// Format is a fmt format which receives the minutes (e.g. "%d min read").
+type ReadingTimeOption struct {
+	TextOption
+	Format         string `json:"format,omitempty"`
+	WordsPerMinute int    `json:"wordsPerMinute,omitempty"`
+}
+
+// FrontMatterOption configures how front-matter values are converted before drawing.
+type FrontMatterOption struct {
+	Authors     *AuthorsOption `json:"authors,omitempty"`
+	DefaultLang string         `json:"defaultLang,omitempty"`
+	DateKeys    []string       `json:"dateKeys,omitempty"`
+	RequireDate bool           `json:"requireDate,omitempty"`
+	// DefaultDate is the date of the posts without it instead of the current time, e.g. "2020-01-01".
+	DefaultDate string `json:"defaultDate,omitempty"`
+	// CategoryFromFirstTag promotes the first tag to the category when a post has no categories.
+	CategoryFromFirstTag bool `json:"categoryFromFirstTag,omitempty"`
+	// CategoryLimit is the maximum number of the categories drawn as a text, which are followed by " ..." if
+	// a post has more. A limit of 0 draws all of them.
+	CategoryLimit *int `json:"categoryLimit,omitempty"`
+	// OverflowMarker is appended to the strings truncated to their widths.
+	OverflowMarker string `json:"overflowMarker,omitempty"`
+	// ExcerptLength enables the excerpt of the body as the description of the post without it,
+	// which is truncated to the length (display width).
+	ExcerptLength int `json:"excerptLength,omitempty"`
+	// TrimSpace trims the leading and trailing whitespace of the title and authors.
+	TrimSpace *bool `json:"trimSpace,omitempty"`
+	// CollapseSpaces collapses the runs of whitespace in the title and authors into single spaces.
+	CollapseSpaces bool `json:"collapseSpaces,omitempty"`
+	// Quotes converts the quotes of the title and authors: Curly, Straight, or empty to keep them.
+	Quotes hugo.QuoteStyle `json:"quotes,omitempty"`
+	// Widths are the maximum display widths of the strings, which are truncated with OverflowMarker.
+	Widths *WidthsOption `json:"widths,omitempty"`
+	// TruncateAtBoundary truncates the strings at the last boundary of the words before their widths.
+	TruncateAtBoundary bool `json:"truncateAtBoundary,omitempty"`
+	// TitleFallback is the title of the posts without it: None, Slug, Literal, or empty to fail.
+	TitleFallback hugo.TitleSource `json:"titleFallback,omitempty"`
+	// TitleFallbackText is the title of the Literal fallback.
+	TitleFallbackText string `json:"titleFallbackText,omitempty"`
 }
 
+// WidthsOption is the maximum display widths of the front-matter strings. A width of 0 or less disables
+// the truncation. Wide characters such as CJK count as two.
+type WidthsOption struct {
+	Title       *int `json:"title,omitempty"`
+	Description *int `json:"description,omitempty"`
+	// Authors is the width of the authors written as a string. A list of authors is not truncated.
+	Authors *int `json:"authors,omitempty"`
+	// Category is the width of the category, and each of the categories drawn in boxes.
+	Category *int `json:"category,omitempty"`
+	// Tag is the width of each tag.
+	Tag *int `json:"tag,omitempty"`
+}
+
+type AuthorsOption struct {
+	// Limit is the maximum number of the authors drawn, which are followed by OverflowSuffix if the post
+	// has more. A limit of 0 draws all of them.
+	Limit          *int   `json:"limit,omitempty"`
+	Separator      string `json:"separator,omitempty"`
+	OverflowSuffix string `json:"overflowSuffix,omitempty"`
+	// VisualOrder draws the right-to-left author names of a left-to-right card in the visual order.
+	VisualOrder bool `json:"visualOrder,omitempty"`
+}
+
+// WatermarkOption is a rotated text stamped across the card.
+// The start point is used as the center of the watermark.
+type WatermarkOption struct {
+	TextOption
+	Text    string   `json:"text,omitempty"`
+	Angle   *float64 `json:"angle,omitempty"`
+	Opacity *float64 `json:"opacity,omitempty"`
+}
+
+// ImageOption is an image drawn over the card. The start point is the top-left corner of the image.
+// If either the width or height is 0, it is scaled to keep the aspect ratio.
+type ImageOption struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	Start   *Point `json:"start,omitempty"`
+	Src     string `json:"src,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+	Circle  *bool  `json:"circle,omitempty"`
+}
+
+// BorderOption is a full-width accent bar at the top of the card.
+// The color of the first category found in CategoryHexColors is used, falling back to HexColor.
+// ThicknessPercent is a percentage of the card height and takes precedence over Thickness.
+type BorderOption struct {
+	Enabled           *bool             `json:"enabled,omitempty"`
+	HexColor          string            `json:"hexColor,omitempty"`
+	CategoryHexColors map[string]string `json:"categoryHexColors,omitempty"`
+	Thickness         int               `json:"thickness,omitempty"`
+	ThicknessPercent  float64           `json:"thicknessPercent,omitempty"`
+}
+
+// SizeOption resizes the template to the card size. The points and sizes of the other options
+// are absolute pixels of the resized card unless they are percentages; they are not scaled with the template.
+type SizeOption struct {
+	Width  int         `json:"width,omitempty"`
+	Height int         `json:"height,omitempty"`
+	Mode   resize.Mode `json:"mode,omitempty"`
+	// Preset is the named size of a social platform, which takes precedence over Width and Height.
+	// Without a template, the card is filled with FillHexColors instead.
+	Preset SizePreset `json:"preset,omitempty"`
+	// FillHexColors is the background of a preset without a template: one color to fill it, or two colors
+	// of a gradient from top to bottom. The default is white.
+	FillHexColors []string `json:"fillHexColors,omitempty"`
+}
+
+// VariantOption is a variant of the cards generated besides them, e.g. a dark one. Its colors and font sizes
+// override the configuration as the "tcard" front-matter does, which overrides them in turn.
+type VariantOption struct {
+	style.Overrides
+	// Suffix is appended to the names of the cards of the variant, e.g. "-dark" for "first-dark.png".
+	Suffix string `json:"suffix,omitempty"`
+	// Template is the default template of the variant instead of the one of the cards.
+	Template string `json:"template,omitempty"`
+}
+
+// HeroImageOption uses the featured image of each post as the background of its card instead of the
+// default template. The image is resized to cover the card, and darkened by the ratio (0-1) of Darken for
+// the legibility of the texts. The posts without an image found fall back to the default template.
+type HeroImageOption struct {
+	Enabled *bool    `json:"enabled,omitempty"`
+	Darken  *float64 `json:"darken,omitempty"`
+}
+
+// Point is a position in pixels. Each coordinate may be set as a percentage of the width or height of the
+// card in the configuration file instead (e.g. px: "50%"), which is resolved by Resolve.
 type Point struct {
 	X int `json:"px"`
 	Y int `json:"py"`
+	// XPercent and YPercent are the percentages of the coordinates, which take precedence over X and Y.
+	XPercent *float64 `json:"-"`
+	YPercent *float64 `json:"-"`
+}
+
+// Rect is a rectangle whose top-left corner is the point. Each of the coordinates and the size may be
+// set as a percentage of the width or height of the card as well as Point.
+type Rect struct {
+	X      int `json:"px"`
+	Y      int `json:"py"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// XPercent, YPercent, WidthPercent and HeightPercent are the percentages of the coordinates and the size,
+	// which take precedence over the pixels.
+	XPercent      *float64 `json:"-"`
+	YPercent      *float64 `json:"-"`
+	WidthPercent  *float64 `json:"-"`
+	HeightPercent *float64 `json:"-"`
+}
+
+// Rectangle returns the rectangle as image.Rectangle. A nil rectangle is empty.
+func (r *Rect) Rectangle() image.Rectangle {
+	if r == nil {
+		return image.Rectangle{}
+	}
+	return image.Rect(r.X, r.Y, r.X+r.Width, r.Y+r.Height)
 }
 
 type Padding struct {
@@ -3,11 +3,30 @@ package config
 import (
 	"github.com/shunk031/tcardgen/pkg/canvas/box"
 	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/canvas/resize"
 )
 
 const DefaultTemplate = "example/template.png"
 
+// DefaultDarkSuffix is the suffix of the names of the cards of the dark variant.
+const DefaultDarkSuffix = "-dark"
+
 var defaultCnf = DrawingConfig{
+	Brand: &BrandOption{
+		TextOption: TextOption{
+			Enabled:    ptrBool(true),
+			Start:      &Point{X: 40, Y: 30},
+			FgHexColor: "#8D8D8D",
+			FontSize:   32,
+			FontStyle:  fontfamily.Regular,
+		},
+		Logo: &ImageOption{
+			Enabled: ptrBool(true),
+			Start:   &Point{X: 40, Y: 30},
+			Height:  32,
+			Circle:  ptrBool(false),
+		},
+	},
 	Title: &MultiLineTextOption{
 		TextOption: TextOption{
 			Start:      &Point{X: 123, Y: 165},
@@ -17,6 +36,18 @@ var defaultCnf = DrawingConfig{
 		},
 		MaxWidth:    946,
 		LineSpacing: ptrInt(10),
+		MaxLines:    3,
+	},
+	Description: &MultiLineTextOption{
+		TextOption: TextOption{
+			Start:      &Point{X: 126, Y: 390},
+			FgHexColor: "#8D8D8D",
+			FontSize:   30,
+			FontStyle:  fontfamily.Regular,
+		},
+		Enabled:     ptrBool(false),
+		MaxWidth:    946,
+		LineSpacing: ptrInt(6),
 	},
 	Category: &TextOption{
 		Enabled:    ptrBool(true),
@@ -25,6 +56,23 @@ var defaultCnf = DrawingConfig{
 		FontSize:   42,
 		FontStyle:  fontfamily.Regular,
 	},
+	Categories: &BoxTextsOption{
+		Enabled:          ptrBool(false),
+		Limit:            0,
+		TitleCaseEnabled: ptrBool(false),
+		TextOption: TextOption{
+			Start:      &Point{X: 126, Y: 110},
+			FgHexColor: "#FFFFFF",
+			FontSize:   26,
+			FontStyle:  fontfamily.Medium,
+		},
+		BgHexColor: "#8D8D8D",
+		BoxPadding: &Padding{Top: 6, Right: 10, Bottom: 6, Left: 10},
+		BoxSpacing: ptrInt(6),
+		BoxAlign:   box.AlignLeft,
+
+		BoxBorderHexColor: "#FFFFFF",
+	},
 	Info: &TextOption{
 		Enabled:    ptrBool(true),
 		Start:      &Point{X: 227, Y: 441},
@@ -34,6 +82,17 @@ var defaultCnf = DrawingConfig{
 		Separator:  "・",
 		TimeFormat: "Jan 2",
 	},
+	ReadingTime: &ReadingTimeOption{
+		TextOption: TextOption{
+			Enabled:    ptrBool(false),
+			Start:      &Point{X: 227, Y: 490},
+			FgHexColor: "#8D8D8D",
+			FontSize:   28,
+			FontStyle:  fontfamily.Regular,
+		},
+		Format:         "%d min read",
+		WordsPerMinute: 200,
+	},
 	Tags: &BoxTextsOption{
 		Enabled:          ptrBool(true),
 		Limit:            0,
@@ -48,35 +107,164 @@ var defaultCnf = DrawingConfig{
 		BoxPadding: &Padding{Top: 6, Right: 10, Bottom: 6, Left: 10},
 		BoxSpacing: ptrInt(6),
 		BoxAlign:   box.AlignRight,
+
+		BoxBorderHexColor: "#FFFFFF",
+	},
+	Draft: &WatermarkOption{
+		TextOption: TextOption{
+			Enabled:    ptrBool(false),
+			Start:      &Point{X: 600, Y: 315},
+			FgHexColor: "#FF0000",
+			FontSize:   160,
+			FontStyle:  fontfamily.Bold,
+		},
+		Text:    "DRAFT",
+		Angle:   ptrFloat64(30),
+		Opacity: ptrFloat64(0.25),
+	},
+	Avatar: &ImageOption{
+		Enabled: ptrBool(false),
+		Start:   &Point{X: 123, Y: 425},
+		Width:   80,
+		Height:  80,
+		Circle:  ptrBool(true),
+	},
+	HeroImage: &HeroImageOption{
+		Enabled: ptrBool(false),
+		Darken:  ptrFloat64(0.4),
+	},
+	TopBorder: &BorderOption{
+		Enabled:   ptrBool(false),
+		HexColor:  "#60BCE0",
+		Thickness: 12,
+	},
+	FrontMatter: &FrontMatterOption{
+		Authors: &AuthorsOption{
+			Limit:          ptrInt(2),
+			Separator:      ", ",
+			OverflowSuffix: " et al.",
+		},
+		DefaultLang:    "en",
+		DateKeys:       []string{"date", "lastmod", "publishDate"},
+		CategoryLimit:  ptrInt(2),
+		OverflowMarker: "...",
+		TrimSpace:      ptrBool(true),
+		Widths: &WidthsOption{
+			Title:       ptrInt(89),
+			Description: ptrInt(89),
+			Authors:     ptrInt(89),
+			Category:    ptrInt(0),
+			Tag:         ptrInt(0),
+		},
 	},
+	TextDirection: box.DirectionLTR,
 }
 
 func Defaulting(cnf *DrawingConfig, tplImg string) {
+	if cnf.Size != nil {
+		defaultingSize(cnf.Size)
+	}
+
+	// a size preset is filled without a template instead of resizing the default one
 	if tplImg != "" {
 		cnf.Template = tplImg
-	} else if cnf.Template == "" {
+	} else if cnf.Template == "" && !cnf.Size.hasPreset() {
 		cnf.Template = DefaultTemplate
 	}
 
+	if cnf.TextDirection == "" {
+		cnf.TextDirection = defaultCnf.TextDirection
+	}
+
+	if cnf.Brand == nil {
+		cnf.Brand = &BrandOption{}
+	}
+	defaultingBrand(cnf.Brand)
+
 	if cnf.Title == nil {
 		cnf.Title = &MultiLineTextOption{}
 	}
 	defaultingTitle(cnf.Title)
 
+	if cnf.Description == nil {
+		cnf.Description = &MultiLineTextOption{}
+	}
+	defaultingDescription(cnf.Description)
+
 	if cnf.Category == nil {
 		cnf.Category = &TextOption{}
 	}
 	defaultingCategory(cnf.Category)
 
+	if cnf.Categories == nil {
+		cnf.Categories = &BoxTextsOption{}
+	}
+	defaultingBoxTexts(cnf.Categories, defaultCnf.Categories)
+
 	if cnf.Info == nil {
 		cnf.Info = &TextOption{}
 	}
 	defaultingInfo(cnf.Info)
 
+	if cnf.ReadingTime == nil {
+		cnf.ReadingTime = &ReadingTimeOption{}
+	}
+	defaultingReadingTime(cnf.ReadingTime)
+
 	if cnf.Tags == nil {
 		cnf.Tags = &BoxTextsOption{}
 	}
-	defaultTags(cnf.Tags)
+	defaultingBoxTexts(cnf.Tags, defaultCnf.Tags)
+
+	if cnf.Draft == nil {
+		cnf.Draft = &WatermarkOption{}
+	}
+	defaultingDraft(cnf.Draft)
+
+	if cnf.Avatar == nil {
+		cnf.Avatar = &ImageOption{}
+	}
+	defaultingAvatar(cnf.Avatar)
+
+	if cnf.TopBorder == nil {
+		cnf.TopBorder = &BorderOption{}
+	}
+	defaultingTopBorder(cnf.TopBorder)
+
+	if cnf.HeroImage == nil {
+		cnf.HeroImage = &HeroImageOption{}
+	}
+	defaultingHeroImage(cnf.HeroImage)
+
+	if cnf.FrontMatter == nil {
+		cnf.FrontMatter = &FrontMatterOption{}
+	}
+	defaultingFrontMatter(cnf.FrontMatter)
+
+	if cnf.Dark != nil && cnf.Dark.Suffix == "" {
+		cnf.Dark.Suffix = DefaultDarkSuffix
+	}
+}
+
+func defaultingBrand(bo *BrandOption) {
+	setArgsAsDefaultTextOption(&bo.TextOption, &defaultCnf.Brand.TextOption)
+	if bo.Logo == nil {
+		bo.Logo = &ImageOption{}
+	}
+	lo, def := bo.Logo, defaultCnf.Brand.Logo
+	if lo.Enabled == nil {
+		lo.Enabled = def.Enabled
+	}
+	if lo.Start == nil {
+		lo.Start = &Point{X: def.Start.X, Y: def.Start.Y}
+	}
+	if lo.Width == 0 && lo.Height == 0 {
+		lo.Width = def.Width
+		lo.Height = def.Height
+	}
+	if lo.Circle == nil {
+		lo.Circle = def.Circle
+	}
 }
 
 func defaultingTitle(mto *MultiLineTextOption) {
@@ -87,6 +275,22 @@ func defaultingTitle(mto *MultiLineTextOption) {
 	if mto.LineSpacing == nil {
 		mto.LineSpacing = defaultCnf.Title.LineSpacing
 	}
+	if mto.MaxLines == 0 {
+		mto.MaxLines = defaultCnf.Title.MaxLines
+	}
+}
+
+func defaultingDescription(mto *MultiLineTextOption) {
+	setArgsAsDefaultTextOption(&mto.TextOption, &defaultCnf.Description.TextOption)
+	if mto.Enabled == nil {
+		mto.Enabled = defaultCnf.Description.Enabled
+	}
+	if mto.MaxWidth == 0 {
+		mto.MaxWidth = defaultCnf.Description.MaxWidth
+	}
+	if mto.LineSpacing == nil {
+		mto.LineSpacing = defaultCnf.Description.LineSpacing
+	}
 }
 
 func defaultingCategory(to *TextOption) {
@@ -97,33 +301,156 @@ func defaultingInfo(to *TextOption) {
 	setArgsAsDefaultTextOption(to, defaultCnf.Info)
 }
 
-func defaultTags(bto *BoxTextsOption) {
-	if bto == nil {
-		bto = &BoxTextsOption{}
+func defaultingReadingTime(rto *ReadingTimeOption) {
+	setArgsAsDefaultTextOption(&rto.TextOption, &defaultCnf.ReadingTime.TextOption)
+	if rto.Format == "" {
+		rto.Format = defaultCnf.ReadingTime.Format
+	}
+	if rto.WordsPerMinute == 0 {
+		rto.WordsPerMinute = defaultCnf.ReadingTime.WordsPerMinute
 	}
+}
+
+func defaultingBoxTexts(bto *BoxTextsOption, dbto *BoxTextsOption) {
 	if bto.Enabled == nil {
-		bto.Enabled = defaultCnf.Tags.Enabled
+		bto.Enabled = dbto.Enabled
 	}
 	if bto.Limit < 0 {
-		bto.Limit = defaultCnf.Tags.Limit
+		bto.Limit = dbto.Limit
 	}
 	if bto.TitleCaseEnabled == nil {
-		bto.TitleCaseEnabled = defaultCnf.Tags.TitleCaseEnabled
+		bto.TitleCaseEnabled = dbto.TitleCaseEnabled
 	}
 
-	setArgsAsDefaultTextOption(&bto.TextOption, &defaultCnf.Tags.TextOption)
+	setArgsAsDefaultTextOption(&bto.TextOption, &dbto.TextOption)
 
 	if bto.BgHexColor == "" {
-		bto.BgHexColor = defaultCnf.Tags.BgHexColor
+		bto.BgHexColor = dbto.BgHexColor
 	}
 	if bto.BoxPadding == nil {
-		bto.BoxPadding = defaultCnf.Tags.BoxPadding
+		bto.BoxPadding = dbto.BoxPadding
 	}
 	if bto.BoxSpacing == nil {
-		bto.BoxSpacing = defaultCnf.Tags.BoxSpacing
+		bto.BoxSpacing = dbto.BoxSpacing
+	}
+	if bto.RowGap == nil {
+		bto.RowGap = ptrInt(*bto.BoxSpacing)
 	}
 	if bto.BoxAlign == "" {
-		bto.BoxAlign = defaultCnf.Tags.BoxAlign
+		bto.BoxAlign = dbto.BoxAlign
+	}
+	if bto.BoxBorderHexColor == "" {
+		bto.BoxBorderHexColor = dbto.BoxBorderHexColor
+	}
+}
+
+func defaultingSize(so *SizeOption) {
+	if w, h, ok := so.Preset.Size(); ok {
+		so.Width, so.Height = w, h
+	}
+	if so.Mode == "" {
+		so.Mode = resize.ModeCover
+	}
+	if len(so.FillHexColors) == 0 {
+		so.FillHexColors = []string{"#FFFFFF"}
+	}
+}
+
+func defaultingDraft(wo *WatermarkOption) {
+	setArgsAsDefaultTextOption(&wo.TextOption, &defaultCnf.Draft.TextOption)
+	if wo.Text == "" {
+		wo.Text = defaultCnf.Draft.Text
+	}
+	if wo.Angle == nil {
+		wo.Angle = defaultCnf.Draft.Angle
+	}
+	if wo.Opacity == nil {
+		wo.Opacity = defaultCnf.Draft.Opacity
+	}
+}
+
+func defaultingAvatar(imo *ImageOption) {
+	if imo.Enabled == nil {
+		imo.Enabled = defaultCnf.Avatar.Enabled
+	}
+	if imo.Start == nil {
+		imo.Start = &Point{X: defaultCnf.Avatar.Start.X, Y: defaultCnf.Avatar.Start.Y}
+	}
+	if imo.Width == 0 && imo.Height == 0 {
+		imo.Width = defaultCnf.Avatar.Width
+		imo.Height = defaultCnf.Avatar.Height
+	}
+	if imo.Circle == nil {
+		imo.Circle = defaultCnf.Avatar.Circle
+	}
+}
+
+func defaultingTopBorder(bo *BorderOption) {
+	if bo.Enabled == nil {
+		bo.Enabled = defaultCnf.TopBorder.Enabled
+	}
+	if bo.HexColor == "" {
+		bo.HexColor = defaultCnf.TopBorder.HexColor
+	}
+	if bo.Thickness == 0 && bo.ThicknessPercent == 0 {
+		bo.Thickness = defaultCnf.TopBorder.Thickness
+	}
+}
+
+func defaultingHeroImage(hio *HeroImageOption) {
+	if hio.Enabled == nil {
+		hio.Enabled = defaultCnf.HeroImage.Enabled
+	}
+	if hio.Darken == nil {
+		hio.Darken = defaultCnf.HeroImage.Darken
+	}
+}
+
+func defaultingFrontMatter(fmo *FrontMatterOption) {
+	if fmo.Authors == nil {
+		fmo.Authors = &AuthorsOption{}
+	}
+	if fmo.Authors.Limit == nil {
+		fmo.Authors.Limit = defaultCnf.FrontMatter.Authors.Limit
+	}
+	if fmo.Authors.Separator == "" {
+		fmo.Authors.Separator = defaultCnf.FrontMatter.Authors.Separator
+	}
+	if fmo.Authors.OverflowSuffix == "" {
+		fmo.Authors.OverflowSuffix = defaultCnf.FrontMatter.Authors.OverflowSuffix
+	}
+	if fmo.DefaultLang == "" {
+		fmo.DefaultLang = defaultCnf.FrontMatter.DefaultLang
+	}
+	if len(fmo.DateKeys) == 0 {
+		fmo.DateKeys = defaultCnf.FrontMatter.DateKeys
+	}
+	if fmo.CategoryLimit == nil {
+		fmo.CategoryLimit = defaultCnf.FrontMatter.CategoryLimit
+	}
+	if fmo.OverflowMarker == "" {
+		fmo.OverflowMarker = defaultCnf.FrontMatter.OverflowMarker
+	}
+	if fmo.TrimSpace == nil {
+		fmo.TrimSpace = defaultCnf.FrontMatter.TrimSpace
+	}
+	if fmo.Widths == nil {
+		fmo.Widths = &WidthsOption{}
+	}
+	if fmo.Widths.Title == nil {
+		fmo.Widths.Title = defaultCnf.FrontMatter.Widths.Title
+	}
+	if fmo.Widths.Description == nil {
+		fmo.Widths.Description = defaultCnf.FrontMatter.Widths.Description
+	}
+	if fmo.Widths.Authors == nil {
+		fmo.Widths.Authors = defaultCnf.FrontMatter.Widths.Authors
+	}
+	if fmo.Widths.Category == nil {
+		fmo.Widths.Category = defaultCnf.FrontMatter.Widths.Category
+	}
+	if fmo.Widths.Tag == nil {
+		fmo.Widths.Tag = defaultCnf.FrontMatter.Widths.Tag
 	}
 }
 
@@ -149,6 +476,21 @@ func setArgsAsDefaultTextOption(to *TextOption, dto *TextOption) {
 	if to.TimeFormat == "" {
 		to.TimeFormat = dto.TimeFormat
 	}
+	if to.TimeLocale == "" {
+		to.TimeLocale = dto.TimeLocale
+	}
+	if to.StrokeHexColor == "" {
+		to.StrokeHexColor = dto.StrokeHexColor
+	}
+	if to.ShadowHexColor == "" {
+		to.ShadowHexColor = dto.ShadowHexColor
+	}
+	if to.ShadowOffset == nil {
+		to.ShadowOffset = &Point{}
+		if dto.ShadowOffset != nil {
+			*to.ShadowOffset = *dto.ShadowOffset
+		}
+	}
 }
 
 func ptrInt(x int) *int {
@@ -158,3 +500,7 @@ func ptrInt(x int) *int {
 func ptrBool(b bool) *bool {
 	return &b
 }
+
+func ptrFloat64(f float64) *float64 {
+	return &f
+}
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"fmt"
+	"io/fs"
 	"os"
 
 	"github.com/ghodss/yaml"
+
+	"github.com/shunk031/tcardgen/pkg/hugo"
 )
 
 func LoadConfig(filename string) (*DrawingConfig, error) {
@@ -11,9 +15,48 @@ func LoadConfig(filename string) (*DrawingConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parseConfig(f)
+}
+
+// LoadConfigFS loads the drawing configuration file of the name from the file system, e.g. an embed.FS
+// to ship the configuration inside a binary.
+func LoadConfigFS(fsys fs.FS, name string) (*DrawingConfig, error) {
+	f, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(f)
+}
+
+// parseConfig parses the YAML drawing configuration, validates its colors, and resolves its presets.
+func parseConfig(f []byte) (*DrawingConfig, error) {
 	c := &DrawingConfig{}
 	if err := yaml.Unmarshal(f, c); err != nil {
 		return nil, err
 	}
+	// the colors are checked before the presets are applied to report the fields of the presets
+	if err := validateColors(c); err != nil {
+		return nil, err
+	}
+	if err := resolvePresets(c); err != nil {
+		return nil, err
+	}
+	if err := validateTimeFormat("info", c.Info); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
+
+func validateTimeFormat(field string, to *TextOption) error {
+	if to == nil || (to.TimeFormat == "" && to.TimeLocale == "") {
+		return nil
+	}
+	layout := to.TimeFormat
+	if layout == "" {
+		layout = defaultCnf.Info.TimeFormat
+	}
+	if err := hugo.ValidateDateLayout(layout, to.TimeLocale); err != nil {
+		return fmt.Errorf("%s.timeFormat: %w", field, err)
+	}
+	return nil
+}
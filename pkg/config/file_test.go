@@ -0,0 +1,98 @@
+package config
+
+import (
+	"embed"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//go:embed testdata/embed.config.yaml
+var testFS embed.FS
+
+func TestLoadConfigPresets(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		expectErr string
+	}{
+		{
+			desc: "Elements referring to the same preset are styled identically",
+			input: `
+presets:
+  accent:
+    fgHexColor: "#FF0000"
+    fontSize: 30
+    fontStyle: Medium
+    strokeHexColor: "#FFFFFF"
+    strokeWidth: 2
+category:
+  preset: accent
+  start: {px: 10, py: 10}
+info:
+  preset: accent
+  start: {px: 10, py: 100}
+`,
+		},
+		{
+			desc: "Unknown preset is an error",
+			input: `
+category:
+  preset: unknown
+`,
+			expectErr: `category.preset: unknown preset "unknown"`,
+		},
+		{
+			desc: "Empty preset is an error",
+			input: `
+presets:
+  accent:
+category:
+  preset: accent
+`,
+			expectErr: `presets.accent: must not be empty`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fn := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(fn, []byte(tc.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cnf, err := LoadConfig(fn)
+			if tc.expectErr != "" {
+				if err == nil || err.Error() != tc.expectErr {
+					t.Fatalf("unexpected error: got=%v, want=%s", err, tc.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			Defaulting(cnf, "")
+
+			cat, info := cnf.Category, cnf.Info
+			if cat.FgHexColor != "#FF0000" || cat.FontSize != 30 || cat.FontStyle != "Medium" || cat.StrokeWidth != 2 {
+				t.Fatalf("preset is not applied: %+v", cat)
+			}
+			if cat.FgHexColor != info.FgHexColor || cat.FontSize != info.FontSize || cat.FontStyle != info.FontStyle ||
+				cat.StrokeHexColor != info.StrokeHexColor || cat.StrokeWidth != info.StrokeWidth ||
+				*cat.ShadowOffset != *info.ShadowOffset {
+				t.Fatalf("elements are styled differently: category=%+v, info=%+v", cat, info)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFS(t *testing.T) {
+	cnf, err := LoadConfigFS(testFS, "testdata/embed.config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cnf.Template != "templates/card.png" || cnf.Title.Start.X != 100 || cnf.Title.FontSize != 64 {
+		t.Fatalf("unexpected configuration: template=%q, title=%+v", cnf.Template, cnf.Title)
+	}
+	if _, err := LoadConfigFS(testFS, "testdata/missing.yaml"); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import "github.com/shunk031/tcardgen/pkg/style"
+
+// Override returns a copy of the configuration whose colors and font sizes are overridden by the values
+// of the front-matter of a post which are set. The configuration is returned as is for nil overrides, and
+// is never modified, so that it is shared by the cards of the other posts. The configuration must be defaulted.
+func (c *DrawingConfig) Override(o *style.Overrides) *DrawingConfig {
+	if o == nil {
+		return c
+	}
+	text := func(to *TextOption, color string, size float64) {
+		if color != "" {
+			to.FgHexColor = color
+		}
+		if size > 0 {
+			to.FontSize = size
+		}
+	}
+
+	r := *c
+	r.Title = modified(c.Title, func(mto *MultiLineTextOption) { text(&mto.TextOption, o.TitleColor, o.TitleSize) })
+	r.Description = modified(c.Description, func(mto *MultiLineTextOption) {
+		text(&mto.TextOption, o.DescriptionColor, o.DescriptionSize)
+	})
+	r.Category = modified(c.Category, func(to *TextOption) { text(to, o.CategoryColor, o.CategorySize) })
+	r.Categories = modified(c.Categories, func(bto *BoxTextsOption) { text(&bto.TextOption, o.CategoryColor, o.CategorySize) })
+	r.Info = modified(c.Info, func(to *TextOption) { text(to, o.InfoColor, o.InfoSize) })
+	r.Tags = modified(c.Tags, func(bto *BoxTextsOption) {
+		text(&bto.TextOption, o.TagsColor, o.TagsSize)
+		if o.TagsBgColor != "" {
+			bto.BgHexColor = o.TagsBgColor
+		}
+	})
+	return &r
+}
+
+// Variant returns a copy of the configuration of the cards of the variant, which is overridden by it and does
+// not have the variants. The template of the variant replaces the default template if it is set.
+func (c *DrawingConfig) Variant(v *VariantOption) *DrawingConfig {
+	r := c.Override(&v.Overrides)
+	if v.Template != "" {
+		r.Template = v.Template
+	}
+	r.Dark = nil
+	return r
+}
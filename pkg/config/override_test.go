@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/shunk031/tcardgen/pkg/style"
+)
+
+func TestDrawingConfigOverride(t *testing.T) {
+	cnf := &DrawingConfig{}
+	Defaulting(cnf, "")
+	base := *cnf.Title
+
+	if got := cnf.Override(nil); got != cnf {
+		t.Fatal("configuration is copied without overrides")
+	}
+
+	r := cnf.Override(&style.Overrides{TitleColor: "#E0245E", TitleSize: 64, CategorySize: 20, TagsBgColor: "#000000"})
+	if r.Title.FgHexColor != "#E0245E" || r.Title.FontSize != 64 {
+		t.Fatalf("title is not overridden: %+v", r.Title)
+	}
+	if r.Category.FontSize != 20 || r.Categories.FontSize != 20 {
+		t.Fatalf("category is not overridden: category=%+v, categories=%+v", r.Category, r.Categories)
+	}
+	if r.Tags.BgHexColor != "#000000" || r.Tags.FgHexColor != cnf.Tags.FgHexColor || r.Tags.FontSize != cnf.Tags.FontSize {
+		t.Fatalf("tags are overridden unexpectedly: %+v", r.Tags)
+	}
+	if r.Description.FgHexColor != cnf.Description.FgHexColor || r.Info.FontSize != cnf.Info.FontSize {
+		t.Fatal("values which are not set are overridden")
+	}
+	if cnf.Title.FgHexColor != base.FgHexColor || cnf.Title.FontSize != base.FontSize {
+		t.Fatalf("the configuration is modified by Override: %+v", cnf.Title)
+	}
+}
+
+func TestDrawingConfigVariant(t *testing.T) {
+	cnf := &DrawingConfig{Dark: &VariantOption{}}
+	Defaulting(cnf, "template.png")
+	if cnf.Dark.Suffix != DefaultDarkSuffix {
+		t.Fatalf("unexpected suffix: %q", cnf.Dark.Suffix)
+	}
+
+	testCases := []struct {
+		desc           string
+		variant        *VariantOption
+		expectTemplate string
+		expectColor    string
+	}{
+		{
+			desc:           "Template of the cards is kept",
+			variant:        &VariantOption{Overrides: style.Overrides{TitleColor: "#FFFFFF"}},
+			expectTemplate: "template.png",
+			expectColor:    "#FFFFFF",
+		},
+		{
+			desc:           "Template is replaced",
+			variant:        &VariantOption{Template: "dark.png"},
+			expectTemplate: "dark.png",
+			expectColor:    cnf.Title.FgHexColor,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := cnf.Variant(tc.variant)
+			if r.Template != tc.expectTemplate || r.Title.FgHexColor != tc.expectColor {
+				t.Fatalf("unexpected variant: template=%q, title=%+v", r.Template, r.Title)
+			}
+			if r.Dark != nil {
+				t.Fatal("variant has the variants")
+			}
+			if cnf.Dark == nil || cnf.Template != "template.png" {
+				t.Fatal("the configuration is modified by Variant")
+			}
+		})
+	}
+}
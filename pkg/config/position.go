@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalJSON parses the point, whose coordinates are the pixels or the percentages such as "50%".
+func (p *Point) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		X json.RawMessage `json:"px"`
+		Y json.RawMessage `json:"py"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	var err error
+	*p = Point{}
+	if p.X, p.XPercent, err = parseCoordinate("px", raw.X); err != nil {
+		return err
+	}
+	if p.Y, p.YPercent, err = parseCoordinate("py", raw.Y); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnmarshalJSON parses the rectangle, whose coordinates and size are the pixels or the percentages.
+func (r *Rect) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		X      json.RawMessage `json:"px"`
+		Y      json.RawMessage `json:"py"`
+		Width  json.RawMessage `json:"width"`
+		Height json.RawMessage `json:"height"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	var err error
+	*r = Rect{}
+	if r.X, r.XPercent, err = parseCoordinate("px", raw.X); err != nil {
+		return err
+	}
+	if r.Y, r.YPercent, err = parseCoordinate("py", raw.Y); err != nil {
+		return err
+	}
+	if r.Width, r.WidthPercent, err = parseCoordinate("width", raw.Width); err != nil {
+		return err
+	}
+	if r.Height, r.HeightPercent, err = parseCoordinate("height", raw.Height); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseCoordinate parses the value of the key, which is the pixels as a number, or the percentage as
+// a string ending with "%" (e.g. "50%"). An absent value is 0 pixels.
+func parseCoordinate(key string, raw json.RawMessage) (int, *float64, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var px int
+		if err := json.Unmarshal(raw, &px); err != nil {
+			return 0, nil, fmt.Errorf("%s: must be pixels or a percentage such as \"50%%\": %s", key, raw)
+		}
+		return px, nil, nil
+	}
+	num, ok := strings.CutSuffix(strings.TrimSpace(s), "%")
+	if !ok {
+		return 0, nil, fmt.Errorf("%s: must be pixels or a percentage such as \"50%%\": %q", key, s)
+	}
+	pct, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || math.IsNaN(pct) || math.IsInf(pct, 0) {
+		return 0, nil, fmt.Errorf("%s: invalid percentage %q", key, s)
+	}
+	return 0, &pct, nil
+}
+
+// Resolve returns the point in pixels, whose percentages are resolved against the width and height.
+func (p Point) Resolve(width, height int) Point {
+	return Point{X: resolvePercent(p.X, p.XPercent, width), Y: resolvePercent(p.Y, p.YPercent, height)}
+}
+
+// Resolve returns the rectangle in pixels, whose percentages are resolved against the width and height.
+// A nil rectangle is returned as nil.
+func (r *Rect) Resolve(width, height int) *Rect {
+	if r == nil {
+		return nil
+	}
+	return &Rect{
+		X:      resolvePercent(r.X, r.XPercent, width),
+		Y:      resolvePercent(r.Y, r.YPercent, height),
+		Width:  resolvePercent(r.Width, r.WidthPercent, width),
+		Height: resolvePercent(r.Height, r.HeightPercent, height),
+	}
+}
+
+// resolvePercent returns the percentage of the length rounded to pixels if it is set, otherwise the pixels.
+func resolvePercent(px int, pct *float64, length int) int {
+	if pct == nil {
+		return px
+	}
+	return int(math.Round(float64(length) * *pct / 100))
+}
+
+// resolvePoint returns the resolved copy of the point, or nil for a nil point.
+func resolvePoint(p *Point, width, height int) *Point {
+	if p == nil {
+		return nil
+	}
+	r := p.Resolve(width, height)
+	return &r
+}
+
+// Resolve returns a copy of the configuration whose points and rectangles set as percentages are resolved
+// into the pixels of the card of the width and height, which is known once the template is loaded and
+// resized. The configuration is not modified, so that it can be resolved for the cards of other sizes.
+func (c *DrawingConfig) Resolve(width, height int) *DrawingConfig {
+	text := func(to *TextOption) {
+		to.Start = resolvePoint(to.Start, width, height)
+		to.ShadowOffset = resolvePoint(to.ShadowOffset, width, height)
+		to.ClipRect = to.ClipRect.Resolve(width, height)
+	}
+
+	r := *c
+	r.Brand = modified(c.Brand, func(o *BrandOption) {
+		text(&o.TextOption)
+		o.Logo = modified(o.Logo, func(lo *ImageOption) { lo.Start = resolvePoint(lo.Start, width, height) })
+	})
+	r.Title = modified(c.Title, func(o *MultiLineTextOption) { text(&o.TextOption) })
+	r.Description = modified(c.Description, func(o *MultiLineTextOption) { text(&o.TextOption) })
+	r.Category = modified(c.Category, text)
+	r.Categories = modified(c.Categories, func(o *BoxTextsOption) { text(&o.TextOption) })
+	r.Info = modified(c.Info, text)
+	r.ReadingTime = modified(c.ReadingTime, func(o *ReadingTimeOption) { text(&o.TextOption) })
+	r.Tags = modified(c.Tags, func(o *BoxTextsOption) { text(&o.TextOption) })
+	r.Draft = modified(c.Draft, func(o *WatermarkOption) { text(&o.TextOption) })
+	r.Avatar = modified(c.Avatar, func(o *ImageOption) { o.Start = resolvePoint(o.Start, width, height) })
+	return &r
+}
+
+// modified returns the copy of the option modified by the function, or nil for a nil option.
+func modified[T any](o *T, modify func(*T)) *T {
+	if o == nil {
+		return nil
+	}
+	r := *o
+	modify(&r)
+	return &r
+}
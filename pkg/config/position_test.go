@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestUnmarshalPercentages(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		input     string
+		expect    Point
+		expectErr bool
+	}{
+		{desc: "Pixels", input: "{px: 100, py: 50}", expect: Point{X: 100, Y: 50}},
+		{desc: "Percentages", input: `{px: "50%", py: 10%}`, expect: Point{X: 600, Y: 63}},
+		{desc: "Mixed", input: `{px: 25%, py: 40}`, expect: Point{X: 300, Y: 40}},
+		{desc: "Fractional percentage", input: `{px: "12.5%", py: 0}`, expect: Point{X: 150, Y: 0}},
+		{desc: "Missing coordinate", input: `{px: 10}`, expect: Point{X: 10, Y: 0}},
+		{desc: "No percent sign", input: `{px: "50", py: 0}`, expectErr: true},
+		{desc: "Invalid percentage", input: `{px: "half%", py: 0}`, expectErr: true},
+		{desc: "Fractional pixels", input: `{px: 1.5, py: 0}`, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var p Point
+			err := yaml.Unmarshal([]byte(tc.input), &p)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error for %s", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.Resolve(1200, 630); got != tc.expect {
+				t.Fatalf("unexpected point: got=%+v, want=%+v", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestDrawingConfigResolve(t *testing.T) {
+	cnf, err := parseConfig([]byte(`
+title:
+  start: {px: 10%, py: 50%}
+  clipRect: {px: 0, py: "50%", width: "100%", height: 25%}
+avatar:
+  start: {px: 90%, py: 20}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Defaulting(cnf, "")
+
+	for _, size := range []struct{ w, h int }{{1200, 630}, {1080, 1080}} {
+		r := cnf.Resolve(size.w, size.h)
+		if want := (Point{X: size.w / 10, Y: size.h / 2}); *r.Title.Start != want {
+			t.Fatalf("unexpected title start of %dx%d: got=%+v, want=%+v", size.w, size.h, *r.Title.Start, want)
+		}
+		if want := (Rect{X: 0, Y: size.h / 2, Width: size.w, Height: (size.h + 2) / 4}); *r.Title.ClipRect != want {
+			t.Fatalf("unexpected clip rectangle of %dx%d: got=%+v, want=%+v", size.w, size.h, *r.Title.ClipRect, want)
+		}
+		if want := (Point{X: size.w * 9 / 10, Y: 20}); *r.Avatar.Start != want {
+			t.Fatalf("unexpected avatar start of %dx%d: got=%+v, want=%+v", size.w, size.h, *r.Avatar.Start, want)
+		}
+		// the absolute positions are kept
+		if *r.Info.Start != *cnf.Info.Start {
+			t.Fatalf("absolute position is changed: got=%+v, want=%+v", *r.Info.Start, *cnf.Info.Start)
+		}
+	}
+	if cnf.Title.Start.XPercent == nil {
+		t.Fatal("the configuration is modified by Resolve")
+	}
+}
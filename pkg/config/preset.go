@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+)
+
+// StyleOption is a named style preset. Elements refer to it by the "preset" key,
+// and the values set in the element take precedence over the preset.
+type StyleOption struct {
+	FgHexColor     string           `json:"fgHexColor,omitempty"`
+	FontSize       float64          `json:"fontSize,omitempty"`
+	FontStyle      fontfamily.Style `json:"fontStyle,omitempty"`
+	LetterSpacing  int              `json:"letterSpacing,omitempty"`
+	StrokeHexColor string           `json:"strokeHexColor,omitempty"`
+	StrokeWidth    int              `json:"strokeWidth,omitempty"`
+	ShadowHexColor string           `json:"shadowHexColor,omitempty"`
+	ShadowOffset   *Point           `json:"shadowOffset,omitempty"`
+	ShadowBlur     int              `json:"shadowBlur,omitempty"`
+	// LineSpacing and LineHeight are applied to multi-line texts, and BoxSpacing to box texts.
+	LineSpacing *int    `json:"lineSpacing,omitempty"`
+	LineHeight  float64 `json:"lineHeight,omitempty"`
+	BoxSpacing  *int    `json:"boxSpacing,omitempty"`
+}
+
+// resolvePresets applies the presets referred to by the elements.
+func resolvePresets(c *DrawingConfig) error {
+	resolve := func(field string, to *TextOption) (*StyleOption, error) {
+		if to == nil || to.Preset == "" {
+			return nil, nil
+		}
+		so, ok := c.Presets[to.Preset]
+		if !ok {
+			return nil, fmt.Errorf("%s.preset: unknown preset %q", field, to.Preset)
+		}
+		if so == nil {
+			// a preset without any key (e.g. "accent:") is parsed as null
+			return nil, fmt.Errorf("presets.%s: must not be empty", to.Preset)
+		}
+		applyPreset(to, so)
+		return so, nil
+	}
+
+	if c.Brand != nil {
+		if _, err := resolve("brand", &c.Brand.TextOption); err != nil {
+			return err
+		}
+	}
+	for _, m := range []struct {
+		field string
+		mto   *MultiLineTextOption
+	}{{"title", c.Title}, {"description", c.Description}} {
+		mto := m.mto
+		if mto == nil {
+			continue
+		}
+		so, err := resolve(m.field, &mto.TextOption)
+		if err != nil {
+			return err
+		}
+		if so != nil && mto.LineSpacing == nil {
+			mto.LineSpacing = so.LineSpacing
+		}
+		if so != nil && mto.LineHeight == 0 {
+			mto.LineHeight = so.LineHeight
+		}
+	}
+	if _, err := resolve("category", c.Category); err != nil {
+		return err
+	}
+	if _, err := resolve("info", c.Info); err != nil {
+		return err
+	}
+	if c.ReadingTime != nil {
+		if _, err := resolve("readingTime", &c.ReadingTime.TextOption); err != nil {
+			return err
+		}
+	}
+	for _, b := range []struct {
+		field string
+		bto   *BoxTextsOption
+	}{{"categories", c.Categories}, {"tags", c.Tags}} {
+		bto := b.bto
+		if bto == nil {
+			continue
+		}
+		so, err := resolve(b.field, &bto.TextOption)
+		if err != nil {
+			return err
+		}
+		if so != nil && bto.BoxSpacing == nil {
+			bto.BoxSpacing = so.BoxSpacing
+		}
+	}
+	if c.Draft != nil {
+		if _, err := resolve("draft", &c.Draft.TextOption); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyPreset(to *TextOption, so *StyleOption) {
+	if to.FgHexColor == "" {
+		to.FgHexColor = so.FgHexColor
+	}
+	if to.FontSize == 0 {
+		to.FontSize = so.FontSize
+	}
+	if to.FontStyle == "" {
+		to.FontStyle = so.FontStyle
+	}
+	if to.LetterSpacing == 0 {
+		to.LetterSpacing = so.LetterSpacing
+	}
+	if to.StrokeHexColor == "" {
+		to.StrokeHexColor = so.StrokeHexColor
+	}
+	if to.StrokeWidth == 0 {
+		to.StrokeWidth = so.StrokeWidth
+	}
+	if to.ShadowHexColor == "" {
+		to.ShadowHexColor = so.ShadowHexColor
+	}
+	if to.ShadowOffset == nil && so.ShadowOffset != nil {
+		p := *so.ShadowOffset
+		to.ShadowOffset = &p
+	}
+	if to.ShadowBlur == 0 {
+		to.ShadowBlur = so.ShadowBlur
+	}
+}
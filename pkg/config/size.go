@@ -0,0 +1,39 @@
+package config
+
+import "image"
+
+// SizePreset is a named card size recommended by a social platform.
+type SizePreset string
+
+const (
+	// SizeOGP is the size of the Open Graph images, 1200x630.
+	SizeOGP = SizePreset("OGP")
+	// SizeTwitterLarge is the size of the Twitter summary_large_image cards, 1200x600.
+	SizeTwitterLarge = SizePreset("TwitterLarge")
+	// SizeLinkedIn is the size of the LinkedIn shared link images, 1200x627.
+	SizeLinkedIn = SizePreset("LinkedIn")
+	// SizeSquare is the size of the square images, 1200x1200.
+	SizeSquare = SizePreset("Square")
+)
+
+var sizePresets = map[SizePreset]image.Point{
+	SizeOGP:          {X: 1200, Y: 630},
+	SizeTwitterLarge: {X: 1200, Y: 600},
+	SizeLinkedIn:     {X: 1200, Y: 627},
+	SizeSquare:       {X: 1200, Y: 1200},
+}
+
+// Size returns the width and height(px) of the preset, and false if it is unknown.
+func (p SizePreset) Size() (width, height int, ok bool) {
+	s, ok := sizePresets[p]
+	return s.X, s.Y, ok
+}
+
+// hasPreset reports whether the size is of a known preset.
+func (so *SizeOption) hasPreset() bool {
+	if so == nil {
+		return false
+	}
+	_, _, ok := so.Preset.Size()
+	return ok
+}
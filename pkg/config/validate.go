@@ -0,0 +1,213 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/shunk031/tcardgen/pkg/canvas/box"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/hugo"
+)
+
+// Validate checks the defaulted configuration against the canvas bounds and the loaded font family,
+// so that the problems are reported before drawing. All the problems found are joined into one error,
+// and each of them is prefixed with the offending field name.
+func (c *DrawingConfig) Validate(bounds image.Rectangle, ffa *fontfamily.FontFamily) error {
+	v := &validator{bounds: bounds, ffa: ffa}
+
+	switch c.TextDirection {
+	case "", box.DirectionLTR, box.DirectionRTL, box.DirectionAuto:
+	default:
+		v.errorf("textDirection", "must be one of LTR, RTL, or Auto: %q", c.TextDirection)
+	}
+
+	if c.Size != nil {
+		if _, _, ok := c.Size.Preset.Size(); c.Size.Preset != "" && !ok {
+			v.errorf("size.preset", "must be one of OGP, TwitterLarge, LinkedIn, or Square: %q", c.Size.Preset)
+		}
+		if n := len(c.Size.FillHexColors); n > 2 {
+			v.errorf("size.fillHexColors", "must be one or two colors: %d colors", n)
+		}
+	}
+
+	if c.HeroImage != nil && isEnabled(c.HeroImage.Enabled) && c.HeroImage.Darken != nil {
+		if d := *c.HeroImage.Darken; d < 0 || d > 1 {
+			v.errorf("heroImage.darken", "must be between 0 and 1: %v", d)
+		}
+	}
+
+	if c.FrontMatter != nil {
+		switch c.FrontMatter.Quotes {
+		case hugo.QuoteAsIs, hugo.QuoteCurly, hugo.QuoteStraight:
+		default:
+			v.errorf("frontMatter.quotes", "must be one of Curly or Straight: %q", c.FrontMatter.Quotes)
+		}
+		if c.FrontMatter.DefaultDate != "" {
+			if _, err := hugo.ParseDate(c.FrontMatter.DefaultDate); err != nil {
+				v.errorf("frontMatter.defaultDate", "%v", err)
+			}
+		}
+		switch c.FrontMatter.TitleFallback {
+		case hugo.TitleRequired, hugo.TitleNone, hugo.TitleSlug:
+		case hugo.TitleLiteral:
+			if c.FrontMatter.TitleFallbackText == "" {
+				v.errorf("frontMatter.titleFallbackText", "must not be empty for the Literal fallback")
+			}
+		default:
+			v.errorf("frontMatter.titleFallback", "must be one of None, Slug, or Literal: %q", c.FrontMatter.TitleFallback)
+		}
+	}
+
+	if c.Brand != nil && isEnabled(c.Brand.Enabled) && c.Brand.Text != "" {
+		v.text("brand", &c.Brand.TextOption)
+	}
+	if c.Brand != nil && isEnabled(c.Brand.Enabled) && c.Brand.Logo != nil && isEnabled(c.Brand.Logo.Enabled) && c.Brand.Logo.Src != "" {
+		v.point("brand.logo.start", c.Brand.Logo.Start)
+		v.nonNegative("brand.logo.width", c.Brand.Logo.Width)
+		v.nonNegative("brand.logo.height", c.Brand.Logo.Height)
+	}
+	if c.Title != nil {
+		v.multiLineText("title", c.Title)
+	}
+	if c.Description != nil && isEnabled(c.Description.Enabled) {
+		v.multiLineText("description", c.Description)
+	}
+	if c.Category != nil {
+		v.text("category", c.Category)
+	}
+	if c.Info != nil {
+		v.text("info", c.Info)
+	}
+	if c.ReadingTime != nil && isEnabled(c.ReadingTime.Enabled) {
+		v.text("readingTime", &c.ReadingTime.TextOption)
+		if c.ReadingTime.WordsPerMinute <= 0 {
+			v.errorf("readingTime.wordsPerMinute", "must be positive: %d", c.ReadingTime.WordsPerMinute)
+		}
+	}
+	if c.Categories != nil && isEnabled(c.Categories.Enabled) {
+		v.boxTexts("categories", c.Categories)
+	}
+	if c.Tags != nil && isEnabled(c.Tags.Enabled) {
+		v.boxTexts("tags", c.Tags)
+	}
+	if c.Draft != nil && isEnabled(c.Draft.Enabled) {
+		v.text("draft", &c.Draft.TextOption)
+	}
+	if c.Avatar != nil && isEnabled(c.Avatar.Enabled) {
+		v.point("avatar.start", c.Avatar.Start)
+		v.nonNegative("avatar.width", c.Avatar.Width)
+		v.nonNegative("avatar.height", c.Avatar.Height)
+	}
+	if c.Dark != nil {
+		v.variant("dark", c.Dark)
+	}
+	v.colors(c)
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	bounds image.Rectangle
+	ffa    *fontfamily.FontFamily
+	errs   []error
+}
+
+func (v *validator) errorf(field, format string, a ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, a...)))
+}
+
+func (v *validator) text(field string, to *TextOption) {
+	v.point(field+".start", to.Start)
+	switch to.Hinting {
+	case "", fontfamily.HintingNone, fontfamily.HintingVertical, fontfamily.HintingFull:
+	default:
+		v.errorf(field+".hinting", "must be one of None, Vertical, or Full: %q", to.Hinting)
+	}
+	if v.ffa != nil {
+		if _, err := v.ffa.Resolve(to.FontStyle); err != nil {
+			v.errorf(field+".fontStyle", "font family %q does not contain %q style font", v.ffa.Name, to.FontStyle)
+		}
+	}
+	v.nonNegative(field+".scrimPadding", to.ScrimPadding)
+	if r := to.ClipRect.Resolve(v.bounds.Dx(), v.bounds.Dy()); r != nil && (r.Width <= 0 || r.Height <= 0) {
+		v.errorf(field+".clipRect", "must have a positive size: %dx%d", r.Width, r.Height)
+	}
+}
+
+func (v *validator) multiLineText(field string, mto *MultiLineTextOption) {
+	v.text(field, &mto.TextOption)
+	if mto.LineSpacing != nil {
+		v.nonNegative(field+".lineSpacing", *mto.LineSpacing)
+	}
+	if mto.LineHeight < 0 {
+		v.errorf(field+".lineHeight", "must not be negative: %v", mto.LineHeight)
+	}
+	if mto.MinFontSize > mto.FontSize {
+		v.errorf(field+".minFontSize", "must not be larger than the font size %v: %v", mto.FontSize, mto.MinFontSize)
+	}
+}
+
+func (v *validator) boxTexts(field string, bto *BoxTextsOption) {
+	v.text(field, &bto.TextOption)
+	switch bto.BoxTextVAlign {
+	case "", box.VAlignTop, box.VAlignMiddle, box.VAlignBottom:
+	default:
+		v.errorf(field+".boxTextVAlign", "must be one of Top, Middle, or Bottom: %q", bto.BoxTextVAlign)
+	}
+	if p := bto.BoxPadding; p != nil {
+		v.nonNegative(field+".boxPadding.top", p.Top)
+		v.nonNegative(field+".boxPadding.right", p.Right)
+		v.nonNegative(field+".boxPadding.bottom", p.Bottom)
+		v.nonNegative(field+".boxPadding.left", p.Left)
+	}
+	if bto.BoxSpacing != nil {
+		v.nonNegative(field+".boxSpacing", *bto.BoxSpacing)
+	}
+	v.nonNegative(field+".maxWidth", bto.MaxWidth)
+	if bto.RowGap != nil {
+		v.nonNegative(field+".rowGap", *bto.RowGap)
+	}
+}
+
+// variant checks the suffix and the sizes of the overrides of the variant.
+func (v *validator) variant(field string, vo *VariantOption) {
+	if vo.Suffix == "" || strings.ContainsAny(vo.Suffix, `/\`) {
+		v.errorf(field+".suffix", "must be a non-empty name without path separators: %q", vo.Suffix)
+	}
+	o := vo.Overrides
+	for _, s := range []struct {
+		key  string
+		size float64
+	}{
+		{"titleSize", o.TitleSize},
+		{"descriptionSize", o.DescriptionSize},
+		{"categorySize", o.CategorySize},
+		{"infoSize", o.InfoSize},
+		{"tagsSize", o.TagsSize},
+	} {
+		if s.size < 0 {
+			v.errorf(field+"."+s.key, "must not be negative: %v", s.size)
+		}
+	}
+}
+
+func (v *validator) point(field string, p *Point) {
+	if p == nil {
+		return
+	}
+	px := p.Resolve(v.bounds.Dx(), v.bounds.Dy())
+	if !image.Pt(px.X, px.Y).In(v.bounds) {
+		v.errorf(field, "(%d, %d) is out of the canvas bounds %v", px.X, px.Y, v.bounds)
+	}
+}
+
+func (v *validator) nonNegative(field string, n int) {
+	if n < 0 {
+		v.errorf(field, "must not be negative: %d", n)
+	}
+}
+
+func isEnabled(b *bool) bool {
+	return b != nil && *b
+}
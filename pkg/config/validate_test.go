@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/style"
+)
+
+func TestValidate(t *testing.T) {
+	ffa := testfont.New(t)
+	bounds := image.Rect(0, 0, 1200, 630)
+	testCases := []struct {
+		desc         string
+		cnf          *DrawingConfig
+		expectFields []string
+	}{
+		{
+			desc: "Default configuration is valid",
+			cnf:  &DrawingConfig{},
+		},
+		{
+			desc: "All the problems are reported",
+			cnf: &DrawingConfig{
+				Title: &MultiLineTextOption{TextOption: TextOption{Start: &Point{X: 1300, Y: 10}}, LineSpacing: ptrInt(-10), LineHeight: -1},
+				Category: &TextOption{
+					FgHexColor: "blue",
+					FontStyle:  "Wide",
+					Hinting:    "Slight",
+					ClipRect:   &Rect{X: 10, Y: 10, Width: 100},
+				},
+				Categories: &BoxTextsOption{
+					Enabled:    ptrBool(true),
+					BgHexColor: "gray",
+				},
+				Tags: &BoxTextsOption{
+					BgHexColor:    "#12",
+					BoxPadding:    &Padding{Top: -1},
+					BoxSpacing:    ptrInt(-6),
+					MaxWidth:      -100,
+					RowGap:        ptrInt(-2),
+					BoxTextVAlign: "Center",
+					BoxHexColors:  map[string]*BoxColorOption{"go": {FgHexColor: "green"}},
+				},
+				TopBorder: &BorderOption{
+					Enabled:           ptrBool(true),
+					CategoryHexColors: map[string]string{"news": "#GGGGGG", "tech": "#60BCE0"},
+				},
+				TextDirection: "TTB",
+				Size:          &SizeOption{Preset: "Story", FillHexColors: []string{"white"}},
+				HeroImage:     &HeroImageOption{Enabled: ptrBool(true), Darken: ptrFloat64(1.5)},
+				FrontMatter:   &FrontMatterOption{Quotes: "Smart", DefaultDate: "yesterday", TitleFallback: "Literal"},
+			},
+			expectFields: []string{
+				"textDirection",
+				"size.preset",
+				"heroImage.darken",
+				"frontMatter.quotes",
+				"frontMatter.defaultDate",
+				"frontMatter.titleFallbackText",
+				"title.start",
+				"title.lineSpacing",
+				"title.lineHeight",
+				"category.hinting",
+				"category.fontStyle",
+				"category.clipRect",
+				"tags.boxTextVAlign",
+				"tags.boxPadding.top",
+				"tags.boxSpacing",
+				"tags.maxWidth",
+				"tags.rowGap",
+				// the colors are checked at last
+				"size.fillHexColors[0]",
+				"category.fgHexColor",
+				"categories.bgHexColor",
+				"tags.bgHexColor",
+				"tags.boxHexColors.go.fgHexColor",
+				"topBorder.categoryHexColors.news",
+			},
+		},
+		{
+			desc: "Percentages are validated against the bounds",
+			cnf: &DrawingConfig{
+				Title:    &MultiLineTextOption{TextOption: TextOption{Start: &Point{XPercent: ptrFloat64(50), YPercent: ptrFloat64(50)}}},
+				Category: &TextOption{Start: &Point{X: 10, YPercent: ptrFloat64(120)}},
+				Info:     &TextOption{ClipRect: &Rect{Width: 100, HeightPercent: ptrFloat64(0)}},
+			},
+			expectFields: []string{
+				"category.start",
+				"info.clipRect",
+			},
+		},
+		{
+			desc: "Dark variant is validated",
+			cnf: &DrawingConfig{
+				Dark: &VariantOption{Suffix: "dark/", Overrides: style.Overrides{TitleColor: "#12", TagsSize: -1}},
+			},
+			expectFields: []string{
+				"dark.suffix",
+				"dark.tagsSize",
+				"dark.titleColor",
+			},
+		},
+		{
+			desc: "Brand logo is validated only with the src",
+			cnf: &DrawingConfig{
+				Brand: &BrandOption{Logo: &ImageOption{Src: "logo.png", Start: &Point{X: 1300, Y: 10}, Width: -1}},
+			},
+			expectFields: []string{
+				"brand.logo.start",
+				"brand.logo.width",
+			},
+		},
+		{
+			desc: "Size of the avatar is validated",
+			cnf: &DrawingConfig{
+				Avatar: &ImageOption{Enabled: ptrBool(true), Width: -1, Height: -1},
+			},
+			expectFields: []string{
+				"avatar.width",
+				"avatar.height",
+			},
+		},
+		{
+			desc: "Minimum font size must not be larger than the font size",
+			cnf: &DrawingConfig{
+				Title:       &MultiLineTextOption{TextOption: TextOption{FontSize: 40}, MinFontSize: 60},
+				Description: &MultiLineTextOption{TextOption: TextOption{FontSize: 40}, MinFontSize: 20},
+			},
+			expectFields: []string{
+				"title.minFontSize",
+			},
+		},
+		{
+			desc: "Disabled elements are not validated",
+			cnf: &DrawingConfig{
+				Tags: &BoxTextsOption{Enabled: ptrBool(false), MaxWidth: -100},
+				Avatar: &ImageOption{
+					Enabled: ptrBool(false),
+					Start:   &Point{X: -1, Y: -1},
+				},
+			},
+		},
+		{
+			desc: "Colors of disabled elements are validated as when they are loaded",
+			cnf: &DrawingConfig{
+				Tags: &BoxTextsOption{Enabled: ptrBool(false), BgHexColor: "invalid"},
+			},
+			expectFields: []string{
+				"tags.bgHexColor",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			Defaulting(tc.cnf, "")
+			err := tc.cnf.Validate(bounds, ffa)
+			if len(tc.expectFields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var je interface{ Unwrap() []error }
+			if !errors.As(err, &je) {
+				t.Fatalf("expected joined errors, got=%v", err)
+			}
+			errs := je.Unwrap()
+			if len(errs) != len(tc.expectFields) {
+				t.Fatalf("unexpected number of errors: got=%d, want=%d\n%v", len(errs), len(tc.expectFields), err)
+			}
+			for i, field := range tc.expectFields {
+				if !strings.HasPrefix(errs[i].Error(), field+": ") {
+					t.Fatalf("error #%d is not about %q: %v", i, field, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateWordsPerMinute(t *testing.T) {
+	// zero set after the defaulting, e.g. by a library user, would divide the word count by it
+	cnf := &DrawingConfig{ReadingTime: &ReadingTimeOption{TextOption: TextOption{Enabled: ptrBool(true)}}}
+	Defaulting(cnf, "")
+	cnf.ReadingTime.WordsPerMinute = 0
+	err := cnf.Validate(image.Rect(0, 0, 1200, 630), nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "readingTime.wordsPerMinute: ") {
+		t.Fatalf("zero words per minute is not rejected: %v", err)
+	}
+}
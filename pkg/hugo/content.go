@@ -0,0 +1,148 @@
+package hugo
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	bundleIndexName       = "index"
+	branchBundleIndexName = "_index"
+)
+
+var contentExts = []string{".md", ".markdown", ".org", ".adoc", ".asciidoc"}
+
+// Content is a Hugo content file.
+type Content struct {
+	// Path is the path to the content file.
+	Path string
+	// Bundle is the directory of the page bundle.
+	// It is empty if the content is not a page bundle.
+	Bundle string
+}
+
+// NewContent returns a Content for the file. An "index.md" or "_index.md" file is identified as a page bundle.
+func NewContent(path string) *Content {
+	c := &Content{Path: path}
+	if name := trimExt(filepath.Base(path)); name == bundleIndexName || name == branchBundleIndexName {
+		c.Bundle = filepath.Dir(path)
+	}
+	return c
+}
+
+// Name returns the content name used to name its card.
+// The directory name is used for a page bundle, otherwise the filename without extension.
+func (c *Content) Name() string {
+	if c.Bundle != "" {
+		return filepath.Base(c.Bundle)
+	}
+	return trimExt(filepath.Base(c.Path))
+}
+
+// CardName returns the name of the card of the content, which matches the permalink of the post:
+// the slug of the front-matter, the last segment of its url without the extension, or the content name.
+func (c *Content) CardName(fm *FrontMatter) string {
+	if fm != nil {
+		if name := path.Base(strings.TrimSpace(fm.Slug)); isName(name) {
+			return name
+		}
+		if name := trimExt(path.Base(strings.TrimSuffix(fm.URL, "/"))); isName(name) {
+			return name
+		}
+	}
+	return c.Name()
+}
+
+// isName reports whether the path segment can name a file.
+func isName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// BundleResource resolves the resource path in the page bundle.
+// It reports false if the content is not a page bundle or the resource does not exist.
+func (c *Content) BundleResource(name string) (string, bool) {
+	if c.Bundle == "" || name == "" {
+		return "", false
+	}
+	p := filepath.Join(c.Bundle, name)
+	if fi, err := os.Stat(p); err != nil || fi.IsDir() {
+		return "", false
+	}
+	return p, true
+}
+
+// Resource resolves the resource path relative to the directory of the content
+// (the bundle directory for a page bundle). It returns fs.ErrNotExist if the resource does not exist.
+func (c *Content) Resource(name string) (string, error) {
+	p := name
+	if !filepath.IsAbs(name) {
+		p = filepath.Join(filepath.Dir(c.Path), name)
+	}
+	if _, err := os.Stat(p); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// FindContents returns contents of the specified paths. Directories are walked recursively,
+// and the other files in a leaf bundle ("index.md") are treated as its resources.
+// The contents in a branch bundle ("_index.md") are walked as well.
+// The paths which cannot be read do not stop finding the others: their errors are joined and returned
+// with the contents found.
+func FindContents(paths ...string) ([]*Content, error) {
+	var contents []*Content
+	var errs []error
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !fi.IsDir() {
+			contents = append(contents, NewContent(p))
+			continue
+		}
+
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// the directory which cannot be read is skipped
+				errs = append(errs, err)
+				return nil
+			}
+			if d.IsDir() {
+				if index, ok := findBundleIndex(path); ok {
+					contents = append(contents, NewContent(index))
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if slices.Contains(contentExts, filepath.Ext(path)) {
+				contents = append(contents, NewContent(path))
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return contents, errors.Join(errs...)
+}
+
+func findBundleIndex(dir string) (string, bool) {
+	for _, ext := range contentExts {
+		index := filepath.Join(dir, bundleIndexName+ext)
+		if fi, err := os.Stat(index); err == nil && !fi.IsDir() {
+			return index, true
+		}
+	}
+	return "", false
+}
+
+func trimExt(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
@@ -0,0 +1,172 @@
+package hugo
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindContents(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"post/first.md",
+		"post/my-bundle/index.md",
+		"post/my-bundle/cover.png",
+		"post/my-bundle/appendix.md",
+		"post/nested/second.md",
+		"post/nested/notes.txt",
+		"post/series/_index.md",
+		"post/series/part1.md",
+	} {
+		mustWriteFile(t, filepath.Join(dir, f))
+	}
+
+	contents, err := FindContents(filepath.Join(dir, "post"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range contents {
+		names = append(names, c.Name())
+	}
+	sort.Strings(names)
+	expect := []string{"first", "my-bundle", "part1", "second", "series"}
+	if len(names) != len(expect) {
+		t.Fatalf("unexpected contents: got=%v, want=%v", names, expect)
+	}
+	for i := range expect {
+		if names[i] != expect[i] {
+			t.Fatalf("unexpected contents: got=%v, want=%v", names, expect)
+		}
+	}
+}
+
+func TestContentCardName(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		path   string
+		fm     *FrontMatter
+		expect string
+	}{
+		{
+			desc:   "Content name without the front-matter",
+			path:   "post/first.md",
+			expect: "first",
+		},
+		{
+			desc:   "Content name without the slug and url",
+			path:   "post/my-bundle/index.md",
+			fm:     &FrontMatter{},
+			expect: "my-bundle",
+		},
+		{
+			desc:   "Slug is preferred",
+			path:   "post/first.md",
+			fm:     &FrontMatter{Slug: "hello-world", URL: "/posts/other/"},
+			expect: "hello-world",
+		},
+		{
+			desc:   "Last segment of the url",
+			path:   "post/first.md",
+			fm:     &FrontMatter{URL: "/posts/hello-world/"},
+			expect: "hello-world",
+		},
+		{
+			desc:   "Extension of the url is trimmed",
+			path:   "post/first.md",
+			fm:     &FrontMatter{URL: "/posts/hello-world.html"},
+			expect: "hello-world",
+		},
+		{
+			desc:   "Slug escaping the directory is ignored",
+			path:   "post/first.md",
+			fm:     &FrontMatter{Slug: "..", URL: "/"},
+			expect: "first",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := NewContent(filepath.FromSlash(tc.path)).CardName(tc.fm); got != tc.expect {
+				t.Fatalf("unexpected card name: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestFindContentsSkipsFailedPaths(t *testing.T) {
+	dir := t.TempDir()
+	post := filepath.Join(dir, "first.md")
+	mustWriteFile(t, post)
+	missing := filepath.Join(dir, "missing.md")
+
+	contents, err := FindContents(missing, post)
+	if !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), missing) {
+		t.Fatalf("missing path is not reported: %v", err)
+	}
+	if len(contents) != 1 || contents[0].Path != post {
+		t.Fatalf("other paths are not found: %+v", contents)
+	}
+}
+
+func TestContentBundle(t *testing.T) {
+	dir := t.TempDir()
+	index := filepath.Join(dir, "my-bundle", "index.md")
+	cover := filepath.Join(dir, "my-bundle", "cover.png")
+	mustWriteFile(t, index)
+	mustWriteFile(t, cover)
+
+	contents, err := FindContents(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := contents[0]
+	if c.Bundle != filepath.Join(dir, "my-bundle") {
+		t.Fatalf("content is not identified as a page bundle: %+v", c)
+	}
+	if got := c.Name(); got != "my-bundle" {
+		t.Fatalf("unexpected name: got=%q, want=%q", got, "my-bundle")
+	}
+	got, err := c.Resource("cover.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != cover {
+		t.Fatalf("unexpected resource path: got=%q, want=%q", got, cover)
+	}
+	if _, err := c.Resource("missing.png"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("missing resource is resolved: %v", err)
+	}
+	if got, ok := c.BundleResource("cover.png"); !ok || got != cover {
+		t.Fatalf("unexpected bundle resource: got=%q, %v", got, ok)
+	}
+	if _, ok := c.BundleResource("missing.png"); ok {
+		t.Fatal("missing bundle resource is resolved")
+	}
+	if _, ok := NewContent(filepath.Join(dir, "post.md")).BundleResource("cover.png"); ok {
+		t.Fatal("bundle resource is resolved for a content which is not a page bundle")
+	}
+}
+
+func TestContentBranchBundle(t *testing.T) {
+	c := NewContent(filepath.Join("post", "series", "_index.md"))
+	if c.Bundle != filepath.Join("post", "series") {
+		t.Fatalf("content is not identified as a page bundle: %+v", c)
+	}
+	if got := c.Name(); got != "series" {
+		t.Fatalf("unexpected name: got=%q, want=%q", got, "series")
+	}
+}
+
+func mustWriteFile(t *testing.T, filename string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
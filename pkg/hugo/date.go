@@ -0,0 +1,174 @@
+package hugo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateNames holds localized month and weekday names.
+type dateNames struct {
+	longMonths    [12]string
+	shortMonths   [12]string
+	longWeekdays  [7]string
+	shortWeekdays [7]string
+	relative      *relativeNames
+}
+
+// relativeNames holds localized words of relative dates.
+type relativeNames struct {
+	justNow string
+	// past and future are formats of the count and the unit
+	past, future string
+	// units are the singular and plural names of relativeUnits
+	units [len(relativeUnits)][2]string
+}
+
+// relativeUnits are the units of relative dates, largest first.
+// Months and years are approximated by 30 and 365 days.
+var relativeUnits = [...]time.Duration{
+	365 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	24 * time.Hour,
+	time.Hour,
+	time.Minute,
+}
+
+var englishRelativeNames = &relativeNames{
+	justNow: "just now",
+	past:    "%d %s ago",
+	future:  "in %d %s",
+	units: [len(relativeUnits)][2]string{
+		{"year", "years"},
+		{"month", "months"},
+		{"week", "weeks"},
+		{"day", "days"},
+		{"hour", "hours"},
+		{"minute", "minutes"},
+	},
+}
+
+var localizedDateNames = map[string]*dateNames{
+	"ja": {
+		longMonths:    [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		shortMonths:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		longWeekdays:  [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		shortWeekdays: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		relative: &relativeNames{
+			justNow: "たった今",
+			past:    "%d%s前",
+			future:  "%d%s後",
+			units: [len(relativeUnits)][2]string{
+				{"年", "年"},
+				{"か月", "か月"},
+				{"週間", "週間"},
+				{"日", "日"},
+				{"時間", "時間"},
+				{"分", "分"},
+			},
+		},
+	},
+}
+
+// layout elements which have localized names, longest first so that "January" is not
+// matched as "Jan".
+var localizedLayoutElems = []string{"January", "Monday", "Jan", "Mon"}
+
+// IsSupportedLocale reports whether the locale can be used by FormatLocalized.
+// An empty locale and "en" use Go's English names.
+func IsSupportedLocale(locale string) bool {
+	if locale == "" || locale == "en" {
+		return true
+	}
+	_, ok := localizedDateNames[locale]
+	return ok
+}
+
+// ValidateDateLayout checks that the layout contains at least one element of Go's
+// reference time and that the locale is supported.
+func ValidateDateLayout(layout, locale string) error {
+	if !IsSupportedLocale(locale) {
+		return fmt.Errorf("unsupported locale %q", locale)
+	}
+	ref := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	if ref.Format(layout) == layout {
+		return fmt.Errorf("%q does not contain any element of the reference time (e.g. \"Jan 2, 2006\")", layout)
+	}
+	return nil
+}
+
+// FormatLocalized formats the time with Go's reference time layout and replaces
+// month and weekday names with the ones of the locale.
+// Unsupported locales fall back to English.
+func FormatLocalized(t time.Time, locale, layout string) string {
+	names, ok := localizedDateNames[locale]
+	if !ok {
+		return t.Format(layout)
+	}
+
+	var sb strings.Builder
+	for layout != "" {
+		idx, elem := nextLocalizedLayoutElem(layout)
+		if idx < 0 {
+			sb.WriteString(t.Format(layout))
+			break
+		}
+		if idx > 0 {
+			sb.WriteString(t.Format(layout[:idx]))
+		}
+		switch elem {
+		case "January":
+			sb.WriteString(names.longMonths[t.Month()-1])
+		case "Jan":
+			sb.WriteString(names.shortMonths[t.Month()-1])
+		case "Monday":
+			sb.WriteString(names.longWeekdays[t.Weekday()])
+		case "Mon":
+			sb.WriteString(names.shortWeekdays[t.Weekday()])
+		}
+		layout = layout[idx+len(elem):]
+	}
+	return sb.String()
+}
+
+func nextLocalizedLayoutElem(layout string) (int, string) {
+	idx, elem := -1, ""
+	for _, e := range localizedLayoutElems {
+		if i := strings.Index(layout, e); i >= 0 && (idx < 0 || i < idx) {
+			idx, elem = i, e
+		}
+	}
+	return idx, elem
+}
+
+// FormatRelative formats the time relative to now in English, such as "3 days ago" or "in 2 hours".
+func FormatRelative(t, now time.Time) string {
+	return FormatRelativeLocalized(t, now, "")
+}
+
+// FormatRelativeLocalized formats the time relative to now in the largest unit which fits in the
+// difference. Unsupported locales fall back to English.
+func FormatRelativeLocalized(t, now time.Time, locale string) string {
+	rn := englishRelativeNames
+	if names, ok := localizedDateNames[locale]; ok && names.relative != nil {
+		rn = names.relative
+	}
+
+	d, format := now.Sub(t), rn.past
+	if d < 0 {
+		d, format = -d, rn.future
+	}
+	for i, u := range relativeUnits {
+		n := int(d / u)
+		if n < 1 {
+			continue
+		}
+		unit := rn.units[i][1]
+		if n == 1 {
+			unit = rn.units[i][0]
+		}
+		return fmt.Sprintf(format, n, unit)
+	}
+	return rn.justNow
+}
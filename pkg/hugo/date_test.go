@@ -0,0 +1,87 @@
+package hugo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatLocalized(t *testing.T) {
+	date := time.Date(2020, time.June, 21, 3, 56, 24, 0, time.UTC)
+
+	testCases := []struct {
+		desc   string
+		locale string
+		layout string
+		expect string
+	}{
+		{desc: "English long date", locale: "en", layout: "January 2, 2006", expect: "June 21, 2020"},
+		{desc: "English short date", locale: "", layout: "Jan 2", expect: "Jun 21"},
+		{desc: "Japanese date", locale: "ja", layout: "2006年1月2日", expect: "2020年6月21日"},
+		{desc: "Japanese month and weekday", locale: "ja", layout: "January 2日 (Mon)", expect: "6月 21日 (日)"},
+		{desc: "Japanese long weekday", locale: "ja", layout: "Monday", expect: "日曜日"},
+		{desc: "Unsupported locale falls back to English", locale: "xx", layout: "Jan 2", expect: "Jun 21"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := FormatLocalized(date, tc.locale, tc.layout); got != tc.expect {
+				t.Fatalf("FormatLocalized() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestFormatRelativeLocalized(t *testing.T) {
+	now := time.Date(2020, time.June, 21, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc   string
+		date   time.Time
+		locale string
+		expect string
+	}{
+		{desc: "Just now", date: now.Add(-30 * time.Second), expect: "just now"},
+		{desc: "Minute", date: now.Add(-time.Minute), expect: "1 minute ago"},
+		{desc: "Hours", date: now.Add(-5 * time.Hour), expect: "5 hours ago"},
+		{desc: "Days", date: now.AddDate(0, 0, -3), expect: "3 days ago"},
+		{desc: "Weeks", date: now.AddDate(0, 0, -15), expect: "2 weeks ago"},
+		{desc: "Months", date: now.AddDate(0, -4, 0), expect: "4 months ago"},
+		{desc: "Years", date: now.AddDate(-2, 0, 0), expect: "2 years ago"},
+		{desc: "Future", date: now.AddDate(0, 0, 1), expect: "in 1 day"},
+		{desc: "Japanese past", date: now.AddDate(0, 0, -3), locale: "ja", expect: "3日前"},
+		{desc: "Japanese future", date: now.Add(2 * time.Hour), locale: "ja", expect: "2時間後"},
+		{desc: "Japanese just now", date: now, locale: "ja", expect: "たった今"},
+		{desc: "Unsupported locale falls back to English", date: now.AddDate(0, -1, 0), locale: "xx", expect: "1 month ago"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := FormatRelativeLocalized(tc.date, now, tc.locale); got != tc.expect {
+				t.Fatalf("FormatRelativeLocalized() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+	if got := FormatRelative(now.AddDate(0, 0, -3), now); got != "3 days ago" {
+		t.Fatalf("FormatRelative() returns unexpected value: got=%q", got)
+	}
+}
+
+func TestValidateDateLayout(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		layout    string
+		locale    string
+		expectErr bool
+	}{
+		{desc: "Valid layout", layout: "Jan 2"},
+		{desc: "Valid Japanese layout", layout: "2006年1月2日", locale: "ja"},
+		{desc: "Layout without reference time", layout: "YYYY-MM-DD", expectErr: true},
+		{desc: "Unsupported locale", layout: "Jan 2", locale: "xx", expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := ValidateDateLayout(tc.layout, tc.locale)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("ValidateDateLayout() returns unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -32,3 +32,24 @@ type FMInvalidTypeError struct {
 func (e *FMInvalidTypeError) Error() string {
 	return fmt.Sprintf("%q type of %v can not convert to %s", e.Key, reflect.TypeOf(e.Got), e.WantType)
 }
+
+// FMError is an error of the front-matter in the content file. The underlying error, such as
+// FMNotExistError or FMInvalidTypeError, can be detected by errors.Is and errors.As.
+type FMError struct {
+	File string
+	// Line is the line number of the front-matter key, or the line of the row of a CSV/TSV file.
+	// It is 0 if the line is unknown, e.g. the key is not defined.
+	Line int
+	Err  error
+}
+
+func (e *FMError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+func (e *FMError) Unwrap() error {
+	return e.Err
+}
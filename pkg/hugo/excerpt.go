@@ -0,0 +1,82 @@
+package hugo
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mdFence       = regexp.MustCompile("^(```|~~~)")
+	mdHeading     = regexp.MustCompile(`^#{1,6}(\s|$)`)
+	mdRule        = regexp.MustCompile(`^([-*_]\s*){3,}$`)
+	mdLinkDef     = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+	mdBlockPrefix = regexp.MustCompile(`^(>\s?)+|^([-*+]|\d+[.)])\s+`)
+	mdImage       = regexp.MustCompile(`!\[([^\]]*)\](\([^)]*\)|\[[^\]]*\])`)
+	mdLink        = regexp.MustCompile(`\[([^\]]*)\](\([^)]*\)|\[[^\]]*\])`)
+	mdShortcode   = regexp.MustCompile(`\{\{[<%].*?[%>]\}\}`)
+	mdHTML        = regexp.MustCompile(`<[^>]+>`)
+	mdCode        = regexp.MustCompile("`+([^`]*)`+")
+	mdStrong      = regexp.MustCompile(`(\*\*|__|~~)(\S(?:.*?\S)?)(\*\*|__|~~)`)
+	mdEmphasis    = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:[^*_]*\S)?)[*_]([^\w*]|$)`)
+	mdImageSrc    = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^\s)>]+)>?`)
+)
+
+// Excerpt returns the plain text of the Markdown content body truncated to fit in the length (display width)
+// with the marker, which is used as the description of the post without it.
+// The headings, code blocks, images, and Hugo shortcodes are removed, and the links and emphasized texts are
+// replaced with their texts. Wide characters such as CJK are counted as two in the width.
+func Excerpt(body string, length int, marker string) string {
+	return makeFixedWidthString(plainText(body), length, marker)
+}
+
+// FirstImage returns the source (a path or URL) of the first inline image in the Markdown content body,
+// or "" if it does not have any. The images in code blocks are ignored.
+func FirstImage(body string) string {
+	for _, line := range proseLines(body) {
+		if m := mdImageSrc.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// proseLines returns the trimmed lines of the Markdown content body except the code blocks.
+func proseLines(body string) []string {
+	var lines []string
+	var fence string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if m := mdFence.FindString(line); m != "" {
+			fence = m
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// plainText removes the Markdown syntax from the body, and joins the lines with spaces.
+func plainText(body string) string {
+	var words []string
+	for _, line := range proseLines(body) {
+		if mdHeading.MatchString(line) || mdRule.MatchString(line) || mdLinkDef.MatchString(line) {
+			continue
+		}
+		line = mdBlockPrefix.ReplaceAllString(line, "")
+		line = mdShortcode.ReplaceAllString(line, "")
+		line = mdImage.ReplaceAllString(line, "")
+		line = mdLink.ReplaceAllString(line, "$1")
+		line = mdHTML.ReplaceAllString(line, "")
+		line = mdCode.ReplaceAllString(line, "$1")
+		line = mdStrong.ReplaceAllString(line, "$2")
+		line = mdEmphasis.ReplaceAllString(line, "$1$2$3")
+		words = append(words, strings.Fields(line)...)
+	}
+	return strings.Join(words, " ")
+}
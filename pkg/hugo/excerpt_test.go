@@ -0,0 +1,103 @@
+package hugo
+
+import "testing"
+
+func TestExcerpt(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		body   string
+		length int
+		expect string
+	}{
+		{
+			desc:   "Plain text is joined into a line",
+			body:   "First line\nsecond line.\n\nNext paragraph.\n",
+			length: 100,
+			expect: "First line second line. Next paragraph.",
+		},
+		{
+			desc:   "Headings and code blocks are removed",
+			body:   "# Title\n\n```go\nfunc main() {}\n```\n\n## Section\nText with `code`.\n",
+			length: 100,
+			expect: "Text with code.",
+		},
+		{
+			desc:   "Links, images, and emphasis are replaced with their texts",
+			body:   "See [the docs](https://example.com) ![logo](logo.png) for **bold**, *italic*, _underscored_ and ~~struck~~ texts.\n",
+			length: 100,
+			expect: "See the docs for bold, italic, underscored and struck texts.",
+		},
+		{
+			desc:   "Snake case words are kept",
+			body:   "Set snake_case_name and 2*3*4.\n",
+			length: 100,
+			expect: "Set snake_case_name and 2*3*4.",
+		},
+		{
+			desc:   "Lists, quotes, shortcodes, and HTML are removed",
+			body:   "> Quoted <em>text</em>\n\n- item one\n1. item two\n\n{{< figure src=\"a.png\" >}}\n---\n[ref]: https://example.com\n",
+			length: 100,
+			expect: "Quoted text item one item two",
+		},
+		{
+			desc:   "Long text is truncated with the marker",
+			body:   "The quick brown fox jumps over the lazy dog.",
+			length: 20,
+			expect: "The quick brown f...",
+		},
+		{
+			desc:   "CJK text is truncated by the width",
+			body:   "日本語の本文から抜粋を作成します。",
+			length: 15,
+			expect: "日本語の本文...",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := Excerpt(tc.body, tc.length, "..."); got != tc.expect {
+				t.Fatalf("Excerpt() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestFirstImage(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		body   string
+		expect string
+	}{
+		{
+			desc:   "First inline image",
+			body:   "Text ![a](a.png) and ![b](b.png)\n![c](c.png)\n",
+			expect: "a.png",
+		},
+		{
+			desc:   "Image in a link with a title",
+			body:   "[![Hero](https://example.com/hero.jpg \"The hero\")](https://example.com)\n",
+			expect: "https://example.com/hero.jpg",
+		},
+		{
+			desc:   "Destination in angle brackets",
+			body:   "![hero](<images/hero.jpg>)\n",
+			expect: "images/hero.jpg",
+		},
+		{
+			desc:   "Images in code blocks are ignored",
+			body:   "```md\n![code](code.png)\n```\n![text](text.png)\n",
+			expect: "text.png",
+		},
+		{
+			desc:   "No images",
+			body:   "See [the docs](https://example.com).\n",
+			expect: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := FirstImage(tc.body); got != tc.expect {
+				t.Fatalf("FirstImage() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package hugo
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/parser/pageparser"
+)
+
+// asciiDocExts are the extensions of AsciiDoc contents, whose document header is parsed
+// when they do not have a Hugo front-matter.
+var asciiDocExts = []string{".adoc", ".asciidoc"}
+
+// keywordAliases maps the lower-cased Org-mode keywords and AsciiDoc attributes onto the front-matter keys.
+var keywordAliases = map[string]string{
+	"author":        fmAuthors,
+	"doctitle":      fmTitle,
+	"filetags":      fmTags,
+	"keywords":      fmTags,
+	"publishdate":   fmPublishDate,
+	"revdate":       fmDate,
+	"tcardtemplate": fmTemplate,
+}
+
+var (
+	asciiDocAttribute = regexp.MustCompile(`^:([^:!]+):\s*(.*)$`)
+	asciiDocEmail     = regexp.MustCompile(`\s*<[^>]*>`)
+)
+
+// parseAsciiDoc parses the Hugo front-matter of the AsciiDoc content, or its document header if it does not have one.
+func parseAsciiDoc(w io.Writer, r io.Reader, currentTime time.Time, opts ...ParseOption) (*FrontMatter, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfm, err := pageparser.ParseFrontMatterAndContent(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if cfm.FrontMatterFormat == "" {
+		cfm = parseAsciiDocHeader(b)
+	}
+	return newFrontMatter(w, cfm, currentTime, newParseOptions(opts...))
+}
+
+// parseAsciiDocHeader parses the document header: the title ("= Title"), the author line, the revision line,
+// and the attribute entries (":name: value"), which end at the first blank line.
+// The authors are separated by ";", and the tags and categories by ",".
+func parseAsciiDocHeader(b []byte) pageparser.ContentFrontMatter {
+	values := map[string]interface{}{}
+	lines := strings.SplitAfter(string(b), "\n")
+	var started, titled bool
+	// next is the implicit line which may follow: "author" after the title, and "revision" after the author line
+	var next string
+	var n int
+header:
+	for ; n < len(lines); n++ {
+		line := strings.TrimSpace(lines[n])
+		expect := next
+		next = ""
+		switch {
+		case line == "":
+			if started {
+				break header
+			}
+		case strings.HasPrefix(line, "//"):
+			next = expect
+		case asciiDocAttribute.MatchString(line):
+			m := asciiDocAttribute.FindStringSubmatch(line)
+			values[strings.ToLower(strings.TrimSpace(m[1]))] = strings.TrimSpace(m[2])
+		case !titled && strings.HasPrefix(line, "= "):
+			values[fmTitle] = strings.TrimSpace(line[2:])
+			titled = true
+			next = "author"
+		case expect == "author":
+			values[fmAuthors] = line
+			next = "revision"
+		case expect == "revision":
+			if date := asciiDocRevisionDate(line); date != "" {
+				values["revdate"] = date
+			}
+		default:
+			// the body starts without a blank line, or the document has no header
+			break header
+		}
+		started = started || line != ""
+	}
+
+	values = normalizeKeywords(values)
+	if s, ok := values[fmAuthors].(string); ok {
+		values[fmAuthors] = splitKeyword(asciiDocEmail.ReplaceAllString(s, ""), ";")
+	}
+	for _, key := range []string{fmCategories, fmTags} {
+		if s, ok := values[key].(string); ok {
+			values[key] = splitKeyword(s, ",")
+		}
+	}
+	return pageparser.ContentFrontMatter{FrontMatter: values, Content: []byte(strings.Join(lines[n:], ""))}
+}
+
+// asciiDocRevisionDate returns the date of the revision line ("v1.0, 2020-06-21: remark").
+func asciiDocRevisionDate(line string) string {
+	if i := strings.Index(line, ": "); i >= 0 {
+		line = line[:i]
+	}
+	if i := strings.Index(line, ","); i >= 0 {
+		return strings.TrimSpace(line[i+1:])
+	}
+	if strings.HasPrefix(line, "v") {
+		// only the revision number
+		return ""
+	}
+	return line
+}
+
+// normalizeOrg maps the Org-mode keywords onto the front-matter keys.
+// The file tags (":tag1:tag2:") are split into the tags.
+func normalizeOrg(values map[string]interface{}) map[string]interface{} {
+	if s, ok := values["filetags"].(string); ok {
+		values["filetags"] = splitKeyword(s, ":")
+	}
+	return normalizeKeywords(values)
+}
+
+// normalizeKeywords renames the keywords by keywordAliases and converts the string lists into the list type
+// of the other front-matter formats. The keys already defined take precedence over the aliases.
+func normalizeKeywords(values map[string]interface{}) map[string]interface{} {
+	fm := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		if ss, ok := v.([]string); ok {
+			items := make([]interface{}, len(ss))
+			for i, s := range ss {
+				items[i] = s
+			}
+			v = items
+		}
+		fm[k] = v
+	}
+	aliases := make([]string, 0, len(keywordAliases))
+	for alias := range keywordAliases {
+		aliases = append(aliases, alias)
+	}
+	slices.Sort(aliases)
+	for _, alias := range aliases {
+		key := keywordAliases[alias]
+		if _, ok := fm[key]; ok {
+			continue
+		}
+		if v, ok := fm[alias]; ok {
+			fm[key] = v
+		}
+	}
+	return fm
+}
+
+func splitKeyword(s, sep string) []interface{} {
+	var items []interface{}
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func isOrg(cfm *pageparser.ContentFrontMatter) bool {
+	return cfm.FrontMatterFormat == metadecoders.ORG
+}
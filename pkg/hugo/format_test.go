@@ -0,0 +1,138 @@
+package hugo
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFrontMatterFormats(t *testing.T) {
+	currentTime := time.Now()
+
+	testCases := []struct {
+		desc      string
+		filename  string
+		input     string
+		expectFM  *FrontMatter
+		expectErr error
+	}{
+		{
+			desc:     "Parse Org-mode keywords",
+			filename: "post.org",
+			input: `#+title: Title
+#+author: @shunk031
+#+date: <2020-06-21 Sun>
+#+categories[]: program
+#+filetags: :hugo:go:
+#+options: toc:nil
+
+* Heading
+`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "program",
+				Categories: []string{"program"},
+				Tags:       []string{"hugo", "go"},
+				Date:       mustParseRFC3339(t, "2020-06-21T00:00:00Z"),
+				WordCount:  1,
+			},
+		},
+		{
+			desc:     "Parse AsciiDoc document header",
+			filename: "post.adoc",
+			input: `= Title
+Alice <alice@example.com>; Bob
+v1.0, 2020-06-21: First draft
+:categories: program, go
+:keywords: hugo, OGP
+:toc:
+
+Body text.
+`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "Alice, Bob",
+				Category:   "program, go",
+				Categories: []string{"program", "go"},
+				Tags:       []string{"hugo", "OGP"},
+				Date:       mustParseRFC3339(t, "2020-06-21T00:00:00Z"),
+				WordCount:  2,
+			},
+		},
+		{
+			desc:     "Parse AsciiDoc attributes without the implicit lines",
+			filename: "post.asciidoc",
+			input: `// a comment
+= Title
+:author: Alice
+:revdate: 2020-06-21
+:tags: hugo
+:categories: program
+Body`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "Alice",
+				Category:   "program",
+				Categories: []string{"program"},
+				Tags:       []string{"hugo"},
+				Date:       mustParseRFC3339(t, "2020-06-21T00:00:00Z"),
+				WordCount:  1,
+			},
+		},
+		{
+			desc:     "Parse AsciiDoc content with Hugo front-matter",
+			filename: "post.adoc",
+			input: `---
+title: "Title"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["hugo"]
+categories: ["program"]
+---
+= Heading
+`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "program",
+				Categories: []string{"program"},
+				Tags:       []string{"hugo"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				WordCount:  1,
+			},
+		},
+		{
+			desc:      "Missing title in AsciiDoc",
+			filename:  "post.adoc",
+			input:     "Body text without header.\n",
+			expectErr: NewFMNotExistError(fmTitle),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), tc.filename)
+			if err := os.WriteFile(p, []byte(tc.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+			fm, err := ParseFrontMatter(io.Discard, p, currentTime)
+			if tc.expectErr != nil {
+				var fe *FMNotExistError
+				if !errors.As(err, &fe) || fe.Error() != tc.expectErr.Error() {
+					t.Fatalf("ParseFrontMatter() returns unexpected error: got=%v, want=%v", err, tc.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if !reflect.DeepEqual(fm, tc.expectFM) {
+				t.Fatalf("ParseFrontMatter() returns unexpected value: got=%#+v, want=%#+v", *fm, *tc.expectFM)
+			}
+		})
+	}
+}
@@ -6,18 +6,36 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gohugoio/hugo/parser/pageparser"
-	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+
+	"github.com/shunk031/tcardgen/pkg/style"
+	"github.com/shunk031/tcardgen/pkg/text"
 )
 
 const (
-	fmTitle      = "title"
-	fmAuthors    = "authors"
-	fmCategories = "categories"
-	fmTags       = "tags"
+	fmTitle       = "title"
+	fmSlug        = "slug"
+	fmURL         = "url"
+	fmDescription = "description"
+	fmAuthors     = "authors"
+	fmCategories  = "categories"
+	fmTags        = "tags"
+	fmLang        = "lang"
+	fmDraft       = "draft"
+	fmAvatar      = "avatar"
+	fmTemplate    = "tcardTemplate"
+	fmOverrides   = "tcard"
+	fmFeatured    = "featured_image"
+	fmImages      = "images"
 
 	fmDate        = "date"        // priority high
 	fmLastmod     = "lastmod"     // priority middle
@@ -26,57 +44,241 @@ const (
 
 var timeFormats = []string{
 	time.RFC3339,
+	time.DateTime,
+	"2006-01-02T15:04:05",
 	time.DateOnly,
 }
 
+// ParseDate parses the date string in one of the formats of the front-matter dates:
+// RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", or "2006-01-02".
+func ParseDate(s string) (t time.Time, err error) {
+	for _, layout := range timeFormats {
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse time: %s, supported formats are %s", err, strings.Join(timeFormats, ", "))
+}
+
+// localTime is implemented by the TOML local date types (e.g. toml.LocalDate and
+// toml.LocalDateTime), which do not carry a time zone.
+type localTime interface {
+	AsTime(zone *time.Location) time.Time
+}
+
 type FrontMatter struct {
-	Title    string
-	Authors  string
-	Category string
-	Tags     []string
-	Date     time.Time
+	Title string `json:"title"`
+	// Description is the summary of the post, or the excerpt of the body if it is not defined and
+	// the excerpt is enabled.
+	Description string `json:"description"`
+	Authors     string `json:"authors"`
+	Category    string `json:"category"`
+	// Categories are all the categories, while Category is a summary of them for drawing.
+	Categories []string  `json:"categories"`
+	Tags       []string  `json:"tags"`
+	Date       time.Time `json:"date"`
+	Lang       string    `json:"lang"`
+	Draft      bool      `json:"draft"`
+	// Avatar is a path or URL of the author's avatar image.
+	Avatar string `json:"avatar"`
+	// Template is a path of the template image which overrides the default one.
+	Template string `json:"tcardTemplate"`
+	// Image is a path or URL of the featured image of the post: the featured_image front-matter,
+	// the first of the images front-matter, or the first image in the content body.
+	Image string `json:"image"`
+	// WordCount is the number of words in the content body.
+	WordCount int `json:"wordCount"`
+	// Slug and URL are the "slug" and "url" front-matters, which Hugo builds the permalink of the post from.
+	Slug string `json:"slug,omitempty"`
+	URL  string `json:"url,omitempty"`
+	// Overrides are the styles of the card of this post which override the drawing configuration,
+	// or nil if the post does not define them.
+	Overrides *style.Overrides `json:"tcard,omitempty"`
 }
 
 // ParseFrontMatter parses the frontmatter of the specified Hugo content.
-func ParseFrontMatter(w io.Writer, filename string, currentTime time.Time) (*FrontMatter, error) {
-	file, err := os.Open(filename)
+// The front-matter format (YAML, TOML, JSON, or Org-mode keywords) is detected from its delimiter as Hugo does.
+// The document header of an AsciiDoc content (".adoc" or ".asciidoc") is parsed if it does not have a front-matter.
+// The errors of the front-matter are FMError, which has the filename and the line of the key.
+func ParseFrontMatter(w io.Writer, filename string, currentTime time.Time, opts ...ParseOption) (*FrontMatter, error) {
+	b, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	return parseFrontMatter(w, file, currentTime)
+	opts = append(opts, contentSlug(NewContent(filename).Name()))
+	var fm *FrontMatter
+	if slices.Contains(asciiDocExts, strings.ToLower(filepath.Ext(filename))) {
+		fm, err = parseAsciiDoc(w, bytes.NewReader(b), currentTime, opts...)
+	} else {
+		fm, err = parseFrontMatter(w, bytes.NewReader(b), currentTime, opts...)
+	}
+	if err != nil {
+		return nil, &FMError{File: filename, Line: keyLine(b, errorKey(err)), Err: err}
+	}
+	return fm, nil
+}
+
+// errorKey returns the front-matter key of the error, or an empty string if the error is not of a key.
+func errorKey(err error) string {
+	var ne *FMNotExistError
+	var ie *FMInvalidTypeError
+	switch {
+	case errors.As(err, &ne):
+		return ne.Key
+	case errors.As(err, &ie):
+		return ie.Key
+	}
+	return ""
+}
+
+// keyLine returns the line number of the key in the front-matter, which ends at the closing delimiter
+// ("---", "+++", or "}"), or at the first blank line of Org-mode keywords and AsciiDoc headers.
+// It returns 0 if the key is not found.
+func keyLine(b []byte, key string) int {
+	if key == "" {
+		return 0
+	}
+	var closing string
+	for n, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if n == 0 {
+			switch line {
+			case "---", "+++":
+				closing = line
+				continue
+			case "{":
+				closing = "}"
+				continue
+			}
+		}
+		if line == closing || (closing == "" && line == "") {
+			return 0
+		}
+		// YAML and TOML keys, JSON keys, Org-mode keywords ("#+key:"), and AsciiDoc attributes (":key:")
+		name := strings.TrimPrefix(strings.TrimPrefix(line, "#+"), ":")
+		name = strings.TrimPrefix(name, `"`)
+		if len(name) > len(key) && strings.EqualFold(name[:len(key)], key) && strings.ContainsRune(`": =`, rune(name[len(key)])) {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+func parseFrontMatter(w io.Writer, r io.Reader, currentTime time.Time, opts ...ParseOption) (*FrontMatter, error) {
+	fm, _, err := ParseFrontMatterAndBody(w, r, currentTime, opts...)
+	return fm, err
 }
 
-func parseFrontMatter(w io.Writer, r io.Reader, currentTime time.Time) (*FrontMatter, error) {
+// ParseFrontMatterAndBody parses the front-matter of the Hugo content read from the reader, and returns it
+// with the content body. The body is the rest of the content after the front-matter and its delimiters, as is.
+// The front-matter format is detected from its delimiter as ParseFrontMatter does.
+func ParseFrontMatterAndBody(w io.Writer, r io.Reader, currentTime time.Time, opts ...ParseOption) (*FrontMatter, []byte, error) {
 	cfm, err := pageparser.ParseFrontMatterAndContent(r)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if isOrg(&cfm) {
+		cfm.FrontMatter = normalizeOrg(cfm.FrontMatter)
 	}
+	fm, err := newFrontMatter(w, cfm, currentTime, newParseOptions(opts...))
+	if err != nil {
+		return nil, nil, err
+	}
+	return fm, cfm.Content, nil
+}
 
-	fm := &FrontMatter{}
-	if fm.Title, err = getString(&cfm, fmTitle); err != nil {
+// newFrontMatter converts the front-matter values into FrontMatter.
+func newFrontMatter(w io.Writer, cfm pageparser.ContentFrontMatter, currentTime time.Time, po *parseOptions) (*FrontMatter, error) {
+	var err error
+	fm := &FrontMatter{WordCount: CountWords(string(cfm.Content))}
+	// the language is parsed first to truncate the strings at the boundaries of its words
+	if fm.Lang, err = getString(&cfm, fmLang, 0, "", po); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
+			return nil, err
+		}
+	}
+	lang := langOrDefault(fm.Lang, po)
+	if fm.Title, err = getString(&cfm, fmTitle, po.widths.Title, lang, po); err != nil {
+		if fm.Title, err = fallbackTitle(&cfm, err, lang, po); err != nil {
+			return nil, err
+		}
+	}
+	if fm.Description, err = getString(&cfm, fmDescription, po.widths.Description, lang, po); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
+			return nil, err
+		}
+		if po.excerptLength > 0 {
+			fm.Description = po.truncate(plainText(string(cfm.Content)), po.excerptLength, lang)
+		}
+	}
+	if fm.Authors, err = getAuthorsString(&cfm, fmAuthors, lang, po); err != nil {
 		return nil, err
 	}
-	if isArray := isArray(&cfm, fmAuthors); isArray {
-		if fm.Authors, err = getAuthorsString(&cfm, fmAuthors); err != nil {
+	if fm.Category, err = getConcatenatedStringItem(&cfm, fmCategories, po.categoryLimit); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) || !po.categoryFromFirstTag {
+			return nil, err
+		}
+		if err := promoteFirstTag(&cfm, fm); err != nil {
 			return nil, err
 		}
 	} else {
-		if fm.Authors, err = getString(&cfm, fmAuthors); err != nil {
+		if fm.Categories, err = getAllStringItems(&cfm, fmCategories); err != nil {
+			return nil, err
+		}
+		if fm.Tags, err = getTags(&cfm, fmTags); err != nil {
 			return nil, err
 		}
 	}
-	if fm.Category, err = getConcatenatedStringItem(&cfm, fmCategories, 2); err != nil {
-		return nil, err
+	truncateTaxonomies(fm, lang, po)
+	if fm.Draft, err = getBool(&cfm, fmDraft); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
+			return nil, err
+		}
 	}
-	if fm.Tags, err = getTags(&cfm, fmTags); err != nil {
-		return nil, err
+	if fm.Avatar, err = getRawString(&cfm, fmAvatar); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
+			return nil, err
+		}
+	}
+	if fm.Template, err = getRawString(&cfm, fmTemplate); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
+			return nil, err
+		}
 	}
-	if fm.Date, err = getContentDate(&cfm, currentTime); err != nil {
+	if fm.Slug, err = getRawString(&cfm, fmSlug); err != nil {
 		var fe *FMNotExistError
-		if errors.As(err, &fe) {
-			fmt.Fprintf(w, "WARN: %s\n", err.Error())
+		if !errors.As(err, &fe) {
+			return nil, err
+		}
+	}
+	if fm.URL, err = getRawString(&cfm, fmURL); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
+			return nil, err
+		}
+	}
+	fm.Image = getImage(w, &cfm)
+	if fm.Overrides, err = getOverrides(w, &cfm); err != nil {
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
+			return nil, err
+		}
+	}
+	if fm.Date, err = getContentDate(&cfm, po.dateKeys, currentTime, []string{lang, po.defaultLang}); err != nil {
+		var fe *FMNotExistError
+		if errors.As(err, &fe) && !po.requireDate {
+			if !po.defaultDate.IsZero() {
+				fm.Date = po.defaultDate
+				return fm, nil
+			}
+			fmt.Fprintf(w, "WARN: %s, the current time is used\n", err.Error())
 			return fm, nil
 		}
 		return nil, err
@@ -85,9 +287,18 @@ func parseFrontMatter(w io.Writer, r io.Reader, currentTime time.Time) (*FrontMa
 	return fm, nil
 }
 
-func getContentDate(cfm *pageparser.ContentFrontMatter, currentTime time.Time) (time.Time, error) {
-	for _, key := range []string{fmDate, fmLastmod, fmPublishDate} {
-		t, err := getTime(cfm, key, currentTime)
+func langOrDefault(lang string, po *parseOptions) string {
+	if lang == "" {
+		return po.defaultLang
+	}
+	return lang
+}
+
+// getContentDate returns the first date found in the keys, which are ordered by priority.
+// The languages are used for the localized dates and are also ordered by priority.
+func getContentDate(cfm *pageparser.ContentFrontMatter, keys []string, currentTime time.Time, langs []string) (time.Time, error) {
+	for _, key := range keys {
+		t, err := getTime(cfm, key, currentTime, langs)
 		if err != nil {
 			switch err.(type) {
 			case *FMNotExistError:
@@ -96,53 +307,158 @@ func getContentDate(cfm *pageparser.ContentFrontMatter, currentTime time.Time) (
 		}
 		return t, err
 	}
-	return currentTime, NewFMNotExistError(
-		strings.Join([]string{fmDate, fmLastmod, fmPublishDate}, ", "))
+	return currentTime, NewFMNotExistError(strings.Join(keys, ", "))
 }
 
-func getTime(cfm *pageparser.ContentFrontMatter, fmKey string, currentTIme time.Time) (t time.Time, err error) {
+func getTime(cfm *pageparser.ContentFrontMatter, fmKey string, currentTIme time.Time, langs []string) (t time.Time, err error) {
 	v, ok := cfm.FrontMatter[fmKey]
 	if !ok {
 		return currentTIme, NewFMNotExistError(fmKey)
 	}
+	return toTime(fmKey, v, currentTIme, langs)
+}
+
+func toTime(fmKey string, v interface{}, currentTIme time.Time, langs []string) (t time.Time, err error) {
 	switch tstr := v.(type) {
 	case string:
-		for _, layout := range timeFormats {
-			if t, err = time.Parse(layout, tstr); err == nil {
-				return t, nil
-			}
+		if t, err = ParseDate(tstr); err != nil {
+			return currentTIme, err
 		}
-		return currentTIme, fmt.Errorf("failed to parse time: %s, supported formats are %s", err, strings.Join(timeFormats, ", "))
+		return t, nil
 	case time.Time:
 		return tstr, nil
+	case localTime:
+		return tstr.AsTime(time.UTC), nil
+	case map[string]interface{}:
+		// localized dates are stored per language (e.g. {en: 2020-06-21, ja: 2020-06-22}).
+		// The date of the first language found is used, or the only one if none is found.
+		for _, lang := range langs {
+			if lv, ok := tstr[lang]; ok {
+				return toTime(fmKey, lv, currentTIme, langs)
+			}
+		}
+		if len(tstr) == 1 {
+			for _, lv := range tstr {
+				return toTime(fmKey, lv, currentTIme, langs)
+			}
+		}
+		return currentTIme, NewFMNotExistError(fmt.Sprintf("%s.%s", fmKey, langs[0]))
 	default:
-		return currentTIme, NewFMInvalidTypeError(fmKey, "time.Time or string", t)
+		return currentTIme, NewFMInvalidTypeError(fmKey, "time.Time or string", tstr)
 	}
 }
 
-func makeFixedWidthString(str string, length int) string {
+// makeFixedWidthString truncates the string to fit in the length (display width) and appends
+// the marker if it is truncated. The width of the marker is included in the length.
+// Grapheme clusters such as emoji sequences are kept or dropped as a whole.
+// The length less than 1 disables the truncation.
+func makeFixedWidthString(str string, length int, marker string) string {
+	if length < 1 || uniseg.StringWidth(str) <= length {
+		return str
+	}
 	var buffer bytes.Buffer
-	l := 0
-	for _, c := range str {
-		cl := runewidth.RuneWidth(c)
-		if l+cl > length {
-			for _, c = range "..." {
-				buffer.WriteRune(c)
-			}
+	l, budget := 0, length-uniseg.StringWidth(marker)
+	gr := uniseg.NewGraphemes(str)
+	for gr.Next() {
+		cl := gr.Width()
+		if l+cl > budget {
 			break
 		}
-		buffer.WriteRune(c)
+		buffer.WriteString(gr.Str())
 		l += cl
 	}
+	buffer.WriteString(marker)
+	return buffer.String()
+}
 
-	// for i := 0; i < length-l; i++ {
-	// 	buffer.WriteRune(' ')
-	// }
+// makeBoundedWidthString truncates the string as makeFixedWidthString does, but at the last boundary of
+// the words before the length, so that a word is not cut in the middle. Latin texts are split into words
+// by spaces, and the others are split with the line breaking rules of the language, which are close to
+// the hard cut for CJK texts. The string is cut hard if its first word does not fit.
+func makeBoundedWidthString(str string, length int, marker, lang string) string {
+	if length < 1 || uniseg.StringWidth(str) <= length {
+		return str
+	}
+	var buffer strings.Builder
+	l, budget := 0, length-uniseg.StringWidth(marker)
+	for _, seg := range text.SegmentForWrapping(str, lang) {
+		// the spaces following a word are dropped when it is the last one
+		if l+uniseg.StringWidth(strings.TrimRightFunc(seg, unicode.IsSpace)) > budget {
+			break
+		}
+		buffer.WriteString(seg)
+		l += uniseg.StringWidth(seg)
+	}
+	kept := strings.TrimRightFunc(buffer.String(), unicode.IsSpace)
+	if kept == "" {
+		return makeFixedWidthString(str, length, marker)
+	}
+	return kept + marker
+}
 
-	return buffer.String()
+// truncate truncates the string to fit in the length with the overflow marker, at the boundary of the words
+// in the language if TruncateAtBoundary is set.
+func (po *parseOptions) truncate(str string, length int, lang string) string {
+	if po.truncateAtBoundary {
+		return makeBoundedWidthString(str, length, po.overflowMarker, lang)
+	}
+	return makeFixedWidthString(str, length, po.overflowMarker)
+}
+
+// fallbackTitle returns the title from the fallback source for the error of the title which does not exist.
+// The error is returned as is if the title is required, or the slug of TitleSlug is unknown.
+func fallbackTitle(cfm *pageparser.ContentFrontMatter, err error, lang string, po *parseOptions) (string, error) {
+	var fe *FMNotExistError
+	if !errors.As(err, &fe) {
+		return "", err
+	}
+	var title string
+	switch po.titleFallback {
+	case TitleNone:
+		return "", nil
+	case TitleLiteral:
+		title = po.titleFallbackText
+	case TitleSlug:
+		slug, serr := getRawString(cfm, fmSlug)
+		if errors.As(serr, &fe) {
+			slug = po.slug
+		} else if serr != nil {
+			return "", serr
+		}
+		if title = slugTitle(slug); title == "" {
+			return "", err
+		}
+	default:
+		return "", err
+	}
+	return po.truncate(normalizeString(title, po), po.widths.Title, lang), nil
+}
+
+// slugTitle converts the slug into a title: the hyphens and underscores are replaced with spaces, and the
+// first letter is capitalized.
+func slugTitle(slug string) string {
+	words := strings.FieldsFunc(slug, func(r rune) bool {
+		return r == '-' || r == '_' || unicode.IsSpace(r)
+	})
+	title := strings.Join(words, " ")
+	r, n := utf8.DecodeRuneInString(title)
+	if n == 0 {
+		return ""
+	}
+	return string(unicode.ToTitle(r)) + title[n:]
 }
 
-func getString(cfm *pageparser.ContentFrontMatter, fmKey string) (string, error) {
+// getString returns the normalized string value truncated to the width.
+func getString(cfm *pageparser.ContentFrontMatter, fmKey string, width int, lang string, po *parseOptions) (string, error) {
+	s, err := getRawString(cfm, fmKey)
+	if err != nil {
+		return "", err
+	}
+	return po.truncate(normalizeString(s, po), width, lang), nil
+}
+
+// getRawString returns the string value as is, without truncating it.
+func getRawString(cfm *pageparser.ContentFrontMatter, fmKey string) (string, error) {
 	v, ok := cfm.FrontMatter[fmKey]
 	if !ok {
 		return "", NewFMNotExistError(fmKey)
@@ -153,57 +469,108 @@ func getString(cfm *pageparser.ContentFrontMatter, fmKey string) (string, error)
 		if s == "" {
 			return "", NewFMNotExistError(fmKey)
 		}
-		s = makeFixedWidthString(s, 89)
-
 		return s, nil
 	default:
 		return "", NewFMInvalidTypeError(fmKey, "string", s)
 	}
 }
 
+// getImage returns the featured image of the post, which is found in the front-matter first, then the body.
+// The image is used only by some configurations, so a value of an unexpected type, e.g. a map of a theme,
+// is warned and ignored instead of failing the post.
+func getImage(w io.Writer, cfm *pageparser.ContentFrontMatter) string {
+	for _, get := range []func() (string, error){
+		func() (string, error) { return getRawString(cfm, fmFeatured) },
+		func() (string, error) { return getFirstStringItem(cfm, fmImages) },
+	} {
+		img, err := get()
+		if err == nil {
+			return img
+		}
+		var fe *FMNotExistError
+		if !errors.As(err, &fe) {
+			fmt.Fprintf(w, "WARN: %v, which is ignored\n", err)
+		}
+	}
+	return FirstImage(string(cfm.Content))
+}
+
+func getBool(cfm *pageparser.ContentFrontMatter, fmKey string) (bool, error) {
+	v, ok := cfm.FrontMatter[fmKey]
+	if !ok {
+		return false, NewFMNotExistError(fmKey)
+	}
+
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		pb, err := strconv.ParseBool(b)
+		if err != nil {
+			return false, NewFMInvalidTypeError(fmKey, "bool", b)
+		}
+		return pb, nil
+	default:
+		return false, NewFMInvalidTypeError(fmKey, "bool", b)
+	}
+}
+
+// getAllStringItems returns the non-empty items of the list, which are trimmed. A string is regarded as
+// the list of itself, so that a single item can be written without brackets (e.g. "categories: Go").
 func getAllStringItems(cfm *pageparser.ContentFrontMatter, fmKey string) ([]string, error) {
 	v, ok := cfm.FrontMatter[fmKey]
 	if !ok {
 		return nil, NewFMNotExistError(fmKey)
 	}
 
+	var items []interface{}
 	switch arr := v.(type) {
 	case []interface{}:
-		var strarr []string
-		for _, item := range arr {
-			switch s := item.(type) {
-			case string:
-				if s != "" {
-					strarr = append(strarr, s)
-				}
-			default:
-				return nil, NewFMInvalidTypeError(fmKey, "string", s)
+		items = arr
+	case string:
+		items = []interface{}{arr}
+	default:
+		return nil, NewFMInvalidTypeError(fmKey, "[]interface{} or string", arr)
+	}
+
+	var strarr []string
+	for _, item := range items {
+		switch s := item.(type) {
+		case string:
+			if s = strings.TrimSpace(s); s != "" {
+				strarr = append(strarr, s)
 			}
+		default:
+			return nil, NewFMInvalidTypeError(fmKey, "string", s)
 		}
-		if len(strarr) < 1 {
-			return nil, NewFMNotExistError(fmKey)
-		}
-		return strarr, nil
-
-	default:
-		return nil, NewFMInvalidTypeError(fmKey, "[]interface{}", arr)
 	}
+	if len(strarr) < 1 {
+		return nil, NewFMNotExistError(fmKey)
+	}
+	return strarr, nil
 }
 
-func getAuthorsString(cfm *pageparser.ContentFrontMatter, fmKey string) (string, error) {
+// getAuthorsString returns the normalized authors concatenated. A single author can be written as a string,
+// which is truncated to the width as the other strings, while a list of authors is limited by authorsLimit.
+func getAuthorsString(cfm *pageparser.ContentFrontMatter, fmKey, lang string, po *parseOptions) (string, error) {
 	arr, err := getAllStringItems(cfm, fmKey)
 	if err != nil {
 		return "", err
 	}
-	if len(arr) > 1 {
-		if len(arr) > 2 {
-			return fmt.Sprintf("%s et al.", arr[0]), nil
-		} else {
-			return strings.Join(arr, ", "), nil
-		}
-	} else {
-		return arr[0], nil
+	for i, a := range arr {
+		arr[i] = normalizeString(a, po)
+	}
+	if _, ok := cfm.FrontMatter[fmKey].(string); ok {
+		return po.truncate(arr[0], po.widths.Authors, lang), nil
+	}
+	return concatAuthors(arr, po), nil
+}
+
+func concatAuthors(authors []string, po *parseOptions) string {
+	if po.authorsLimit > 0 && len(authors) > po.authorsLimit {
+		return strings.Join(authors[:po.authorsLimit], po.authorsSeparator) + po.authorsOverflowSuffix
 	}
+	return strings.Join(authors, po.authorsSeparator)
 }
 
 func getConcatenatedStringItem(cfm *pageparser.ContentFrontMatter, fmKey string, numItems int) (string, error) {
@@ -211,14 +578,10 @@ func getConcatenatedStringItem(cfm *pageparser.ContentFrontMatter, fmKey string,
 	if err != nil {
 		return "", err
 	}
-	if len(arr) > 2 {
-		if len(arr) > numItems {
-			arr = arr[:numItems]
-		}
-		return fmt.Sprintf("%s ...", strings.Join(arr, ", ")), nil
-	} else {
-		return strings.Join(arr, ", "), nil
+	if numItems > 0 && len(arr) > numItems {
+		return fmt.Sprintf("%s ...", strings.Join(arr[:numItems], ", ")), nil
 	}
+	return strings.Join(arr, ", "), nil
 }
 
 func getTags(cfm *pageparser.ContentFrontMatter, fmKey string) ([]string, error) {
@@ -226,13 +589,47 @@ func getTags(cfm *pageparser.ContentFrontMatter, fmKey string) ([]string, error)
 	if err != nil {
 		return nil, err
 	}
-	if len(arr) > 3 {
-		arr = arr[:3]
+	return truncateTags(arr), nil
+}
+
+// tagsLimit is the number of the tags drawn, which are followed by "..." if the post has more tags.
+const tagsLimit = 3
+
+func truncateTags(arr []string) []string {
+	if len(arr) > tagsLimit {
+		arr = arr[:tagsLimit]
 		arr = append(arr, "...")
-		return arr, nil
+		return arr
 	} else {
-		return arr, nil
+		return arr
+	}
+}
+
+// truncateTaxonomies truncates the category, each of the categories, and each tag to their widths.
+// The overflow item of the tags ("...") is kept as is.
+func truncateTaxonomies(fm *FrontMatter, lang string, po *parseOptions) {
+	fm.Category = po.truncate(fm.Category, po.widths.Category, lang)
+	for i, c := range fm.Categories {
+		fm.Categories[i] = po.truncate(c, po.widths.Category, lang)
+	}
+	for i, tag := range fm.Tags {
+		if i == tagsLimit {
+			break
+		}
+		fm.Tags[i] = po.truncate(tag, po.widths.Tag, lang)
+	}
+}
+
+// promoteFirstTag sets the first tag as the category, and the rest as the tags.
+func promoteFirstTag(cfm *pageparser.ContentFrontMatter, fm *FrontMatter) error {
+	tags, err := getAllStringItems(cfm, fmTags)
+	if err != nil {
+		return err
 	}
+	fm.Category = tags[0]
+	fm.Categories = tags[:1]
+	fm.Tags = truncateTags(tags[1:])
+	return nil
 }
 
 func getFirstStringItem(cfm *pageparser.ContentFrontMatter, fmKey string) (string, error) {
@@ -242,12 +639,3 @@ func getFirstStringItem(cfm *pageparser.ContentFrontMatter, fmKey string) (strin
 	}
 	return arr[0], nil
 }
-
-func isArray(cfm *pageparser.ContentFrontMatter, fmKey string) bool {
-	switch v := cfm.FrontMatter[fmKey]; v.(type) {
-	case []interface{}:
-		return true
-	default:
-		return false
-	}
-}
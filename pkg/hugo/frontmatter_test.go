@@ -2,11 +2,18 @@ package hugo
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/rivo/uniseg"
+
+	"github.com/shunk031/tcardgen/pkg/style"
 )
 
 func TestParseFrontMatterFromReader(t *testing.T) {
@@ -22,36 +29,194 @@ func TestParseFrontMatterFromReader(t *testing.T) {
 			desc: "Parse YAML front matter",
 			input: `---
 title: "HugoでもTwitterCardを自動生成したい"
-author: ["@shunk031"]
+authors: ["@shunk031"]
 date: 2020-06-21T03:56:24+09:00
 tags: ["hugo", "go", "OGP"]
 categories: ["program"]
 ---
 content`,
 			expectFM: &FrontMatter{
-				Title:    "HugoでもTwitterCardを自動生成したい",
-				Authors:  "@shunk031",
-				Category: "program",
-				Tags:     []string{"hugo", "go", "OGP"},
-				Date:     mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Title:      "HugoでもTwitterCardを自動生成したい",
+				Authors:    "@shunk031",
+				Category:   "program",
+				Categories: []string{"program"},
+				Tags:       []string{"hugo", "go", "OGP"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				WordCount:  1,
 			},
 		},
 		{
 			desc: "Parse TOML front matter",
 			input: `+++
 title = "HugoでもTwitterCardを自動生成したい"
-author = ["@shunk031"]
+authors = ["@shunk031"]
 date = "2020-06-21T03:56:24+09:00"
 tags = ["hugo", "go", "OGP"]
 categories = ["program"]
 +++
 content`,
 			expectFM: &FrontMatter{
-				Title:    "HugoでもTwitterCardを自動生成したい",
-				Authors:  "@shunk031",
-				Category: "program",
-				Tags:     []string{"hugo", "go", "OGP"},
-				Date:     mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Title:      "HugoでもTwitterCardを自動生成したい",
+				Authors:    "@shunk031",
+				Category:   "program",
+				Categories: []string{"program"},
+				Tags:       []string{"hugo", "go", "OGP"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				WordCount:  1,
+			},
+		},
+		{
+			desc: "Parse TOML front matter with native datetime",
+			input: `+++
+title = "HugoでもTwitterCardを自動生成したい"
+authors = ["@shunk031"]
+date = 2020-06-21T03:56:24+09:00
+tags = ["hugo", "go", "OGP"]
+categories = ["program"]
++++
+content`,
+			expectFM: &FrontMatter{
+				Title:      "HugoでもTwitterCardを自動生成したい",
+				Authors:    "@shunk031",
+				Category:   "program",
+				Categories: []string{"program"},
+				Tags:       []string{"hugo", "go", "OGP"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				WordCount:  1,
+			},
+		},
+		{
+			desc: "Parse JSON front matter",
+			input: `{
+  "title": "HugoでもTwitterCardを自動生成したい",
+  "authors": ["@shunk031"],
+  "date": "2020-06-21T03:56:24+09:00",
+  "tags": ["hugo", "go", "OGP"],
+  "categories": ["program"]
+}
+content`,
+			expectFM: &FrontMatter{
+				Title:      "HugoでもTwitterCardを自動生成したい",
+				Authors:    "@shunk031",
+				Category:   "program",
+				Categories: []string{"program"},
+				Tags:       []string{"hugo", "go", "OGP"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				WordCount:  1,
+			},
+		},
+		{
+			desc: "Parse TOML front matter with local date",
+			input: `+++
+title = "Title"
+authors = ["@shunk031"]
+date = 2020-06-21
+tags = ["tag1"]
+categories = ["cat1"]
++++`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "cat1",
+				Categories: []string{"cat1"},
+				Tags:       []string{"tag1"},
+				Date:       mustParseRFC3339(t, "2020-06-21T00:00:00Z"),
+			},
+		},
+		{
+			desc: "Parse TOML front matter with local datetime",
+			input: `+++
+title = "Title"
+authors = ["@shunk031"]
+date = 2020-06-21T03:56:24
+tags = ["tag1"]
+categories = ["cat1"]
++++`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "cat1",
+				Categories: []string{"cat1"},
+				Tags:       []string{"tag1"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24Z"),
+			},
+		},
+		{
+			desc: "Parse YAML front matter with space separated datetime",
+			input: `---
+title: "Title"
+authors: ["@shunk031"]
+date: 2020-06-21 03:56:24
+tags: ["tag1"]
+categories: ["cat1"]
+---`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "cat1",
+				Categories: []string{"cat1"},
+				Tags:       []string{"tag1"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24Z"),
+			},
+		},
+		{
+			desc: "Parse draft",
+			input: `---
+title: "Title"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+draft: true
+---`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "cat1",
+				Categories: []string{"cat1"},
+				Tags:       []string{"tag1"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Draft:      true,
+			},
+		},
+		{
+			desc: "Parse avatar",
+			input: `---
+title: "Title"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+avatar: "https://example.com/avatars/a-very-long-avatar-file-name-that-must-not-be-truncated-like-titles-are.png"
+---`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "cat1",
+				Categories: []string{"cat1"},
+				Tags:       []string{"tag1"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Avatar:     "https://example.com/avatars/a-very-long-avatar-file-name-that-must-not-be-truncated-like-titles-are.png",
+			},
+		},
+		{
+			desc: "Parse template override",
+			input: `---
+title: "Title"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+tcardTemplate: "templates/release.png"
+---`,
+			expectFM: &FrontMatter{
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "cat1",
+				Categories: []string{"cat1"},
+				Tags:       []string{"tag1"},
+				Date:       mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+				Template:   "templates/release.png",
 			},
 		},
 		{
@@ -63,12 +228,12 @@ content`,
 			input: `---
 title = "invalid format'
 ---`,
-			expectErr: errors.New("failed to unmarshal YAML: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `title =...` into map[string]interface {}"),
+			expectErr: errors.New("\"_stream.yaml:1:1\": failed to unmarshal YAML: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `title =...` into map[string]interface {}"),
 		},
 		{
 			desc: "Title is missing",
 			input: `+++
-author = ["@shunk031"]
+authors = ["@shunk031"]
 +++`,
 			expectErr: NewFMNotExistError(fmTitle),
 		},
@@ -90,7 +255,7 @@ title = "Title"
 			desc: "Category is empty",
 			input: `+++
 title = "Title"
-author = ["@shunk031"]
+authors = ["@shunk031"]
 categories = [""]
 +++`,
 			expectErr: NewFMNotExistError(fmCategories),
@@ -99,7 +264,7 @@ categories = [""]
 			desc: "Tag is missing",
 			input: `+++
 title = "Title"
-author = ["@shunk031"]
+authors = ["@shunk031"]
 categories = ["Program"]
 +++`,
 			expectErr: NewFMNotExistError(fmTags),
@@ -108,16 +273,17 @@ categories = ["Program"]
 			desc: "When time is missing, default time is now",
 			input: `+++
 title = "Title"
-author = ["@shunk031"]
+authors = ["@shunk031"]
 categories = ["cat11"]
 tags = ["tag1"]
 +++`,
 			expectFM: &FrontMatter{
-				Title:    "Title",
-				Author:   "@Ladicle",
-				Category: "cat11",
-				Tags:     []string{"tag1"},
-				Date:     currentTime,
+				Title:      "Title",
+				Authors:    "@shunk031",
+				Category:   "cat11",
+				Categories: []string{"cat11"},
+				Tags:       []string{"tag1"},
+				Date:       currentTime,
 			},
 		},
 	}
@@ -147,10 +313,1139 @@ tags = ["tag1"]
 	}
 }
 
-func mustParseRFC3339(t *testing.T, timeStr string) time.Time {
-	tt, err := time.Parse(time.RFC3339, timeStr)
-	if err != nil {
-		t.Fatal(err)
+func TestParseLocalizedDate(t *testing.T) {
+	currentTime := time.Now()
+	input := `+++
+title = "Title"
+authors = ["@shunk031"]
+tags = ["tag1"]
+categories = ["cat1"]
+%s
+[date]
+%s
++++`
+
+	testCases := []struct {
+		desc   string
+		lang   string
+		date   string
+		opts   []ParseOption
+		expect time.Time
+	}{
+		{
+			desc:   "Date for the post language",
+			lang:   `lang = "ja"`,
+			expect: mustParseRFC3339(t, "2020-06-22T03:56:24+09:00"),
+		},
+		{
+			desc:   "Date for the default language",
+			expect: mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+		},
+		{
+			desc:   "Date for the configured default language",
+			opts:   []ParseOption{DefaultLang("ja")},
+			expect: mustParseRFC3339(t, "2020-06-22T03:56:24+09:00"),
+		},
+		{
+			desc:   "Date for the default language when the post language is not defined in the map",
+			lang:   `lang = "fr"`,
+			expect: mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+		},
+		{
+			desc:   "Neither the post language nor the default language is defined in the map",
+			lang:   `lang = "fr"`,
+			opts:   []ParseOption{DefaultLang("de")},
+			expect: currentTime,
+		},
+		{
+			desc:   "Only date in the map",
+			lang:   `lang = "fr"`,
+			date:   `ja = "2020-06-22T03:56:24+09:00"`,
+			opts:   []ParseOption{DefaultLang("de")},
+			expect: mustParseRFC3339(t, "2020-06-22T03:56:24+09:00"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			date := tc.date
+			if date == "" {
+				date = "en = 2020-06-21T03:56:24+09:00\nja = \"2020-06-22T03:56:24+09:00\""
+			}
+			r := strings.NewReader(fmt.Sprintf(input, tc.lang, date))
+			fm, err := parseFrontMatter(io.Discard, r, currentTime, tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if !fm.Date.Equal(tc.expect) {
+				t.Fatalf("unexpected date: got=%v, want=%v", fm.Date, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseDateKeys(t *testing.T) {
+	currentTime := time.Now()
+	input := `---
+title: "Title"
+authors: ["@shunk031"]
+tags: ["tag1"]
+categories: ["cat1"]
+date: 2020-06-21T03:56:24+09:00
+created: 2019-01-02T03:04:05+09:00
+---`
+
+	testCases := []struct {
+		desc      string
+		opts      []ParseOption
+		expect    time.Time
+		expectErr error
+	}{
+		{
+			desc:   "Default keys",
+			expect: mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+		},
+		{
+			desc:   "Custom key has the highest priority",
+			opts:   []ParseOption{DateKeys("created", "date")},
+			expect: mustParseRFC3339(t, "2019-01-02T03:04:05+09:00"),
+		},
+		{
+			desc:   "Fallback to the current time",
+			opts:   []ParseOption{DateKeys("modified")},
+			expect: currentTime,
+		},
+		{
+			desc:   "Fallback to the default date",
+			opts:   []ParseOption{DateKeys("modified"), DefaultDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))},
+			expect: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:   "Default date is not used for the defined date",
+			opts:   []ParseOption{DefaultDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))},
+			expect: mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"),
+		},
+		{
+			desc:      "Missing date is an error when required",
+			opts:      []ParseOption{DateKeys("modified", "updated"), RequireDate(true)},
+			expectErr: NewFMNotExistError("modified, updated"),
+		},
+		{
+			desc:      "Required date is preferred to the default date",
+			opts:      []ParseOption{DateKeys("modified"), RequireDate(true), DefaultDate(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))},
+			expectErr: NewFMNotExistError("modified"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), currentTime, tc.opts...)
+			if tc.expectErr != nil {
+				if err == nil || err.Error() != tc.expectErr.Error() {
+					t.Fatalf("unexpected error: got=%v, want=%v", err, tc.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if !fm.Date.Equal(tc.expect) {
+				t.Fatalf("unexpected date: got=%v, want=%v", fm.Date, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseCategoryFromFirstTag(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		input            string
+		opts             []ParseOption
+		expectCategory   string
+		expectCategories []string
+		expectTags       []string
+		expectErr        error
+	}{
+		{
+			desc: "First tag is promoted when categories are missing",
+			input: `---
+title: "Title"
+authors: ["@shunk031"]
+tags: ["tag1", "tag2", "tag3", "tag4", "tag5"]
+date: 2020-06-21T03:56:24+09:00
+---`,
+			opts:             []ParseOption{CategoryFromFirstTag(true)},
+			expectCategory:   "tag1",
+			expectCategories: []string{"tag1"},
+			expectTags:       []string{"tag2", "tag3", "tag4", "..."},
+		},
+		{
+			desc: "Categories take precedence over the first tag",
+			input: `---
+title: "Title"
+authors: ["@shunk031"]
+tags: ["tag1", "tag2"]
+categories: ["cat1"]
+date: 2020-06-21T03:56:24+09:00
+---`,
+			opts:             []ParseOption{CategoryFromFirstTag(true)},
+			expectCategory:   "cat1",
+			expectCategories: []string{"cat1"},
+			expectTags:       []string{"tag1", "tag2"},
+		},
+		{
+			desc: "Missing categories is an error without the option",
+			input: `---
+title: "Title"
+authors: ["@shunk031"]
+tags: ["tag1"]
+date: 2020-06-21T03:56:24+09:00
+---`,
+			expectErr: NewFMNotExistError(fmCategories),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(tc.input), time.Now(), tc.opts...)
+			if tc.expectErr != nil {
+				if err == nil || err.Error() != tc.expectErr.Error() {
+					t.Fatalf("unexpected error: got=%v, want=%v", err, tc.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Category != tc.expectCategory || !reflect.DeepEqual(fm.Categories, tc.expectCategories) || !reflect.DeepEqual(fm.Tags, tc.expectTags) {
+				t.Fatalf("unexpected taxonomies: category=%q, categories=%q, tags=%q", fm.Category, fm.Categories, fm.Tags)
+			}
+		})
+	}
+}
+
+func TestParseCategoryLimit(t *testing.T) {
+	input := `---
+title: "Title"
+authors: ["alice"]
+tags: ["tag1"]
+categories: ["cat1", "cat2", "cat3", "cat4"]
+date: 2020-06-21T03:56:24+09:00
+---`
+	testCases := []struct {
+		desc   string
+		opts   []ParseOption
+		expect string
+	}{
+		{desc: "First two categories by default", expect: "cat1, cat2 ..."},
+		{desc: "Custom limit", opts: []ParseOption{CategoryLimit(3)}, expect: "cat1, cat2, cat3 ..."},
+		{desc: "Limit of all the categories", opts: []ParseOption{CategoryLimit(4)}, expect: "cat1, cat2, cat3, cat4"},
+		{desc: "Unlimited categories", opts: []ParseOption{CategoryLimit(0)}, expect: "cat1, cat2, cat3, cat4"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now(), tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Category != tc.expect {
+				t.Fatalf("unexpected category: got=%q, want=%q", fm.Category, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseAuthors(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		authors string
+		opts    []ParseOption
+		expect  string
+	}{
+		{
+			desc:    "Single author",
+			authors: `["alice"]`,
+			expect:  "alice",
+		},
+		{
+			desc:    "Two authors are both shown",
+			authors: `["alice", "bob"]`,
+			expect:  "alice, bob",
+		},
+		{
+			desc:    "Three authors exceed the default limit",
+			authors: `["alice", "bob", "carol"]`,
+			expect:  "alice, bob et al.",
+		},
+		{
+			desc:    "Four authors exceed the default limit",
+			authors: `["alice", "bob", "carol", "dave"]`,
+			expect:  "alice, bob et al.",
+		},
+		{
+			desc:    "Six authors exceed the default limit",
+			authors: `["alice", "bob", "carol", "dave", "eve", "frank"]`,
+			expect:  "alice, bob et al.",
+		},
+		{
+			desc:    "Four authors within a custom limit",
+			authors: `["alice", "bob", "carol", "dave"]`,
+			opts:    []ParseOption{AuthorsLimit(4), AuthorsSeparator(" & ")},
+			expect:  "alice & bob & carol & dave",
+		},
+		{
+			desc:    "Six authors with a custom suffix",
+			authors: `["alice", "bob", "carol", "dave", "eve", "frank"]`,
+			opts:    []ParseOption{AuthorsLimit(4), AuthorsOverflowSuffix(" ほか")},
+			expect:  "alice, bob, carol, dave ほか",
+		},
+		{
+			desc:    "Unlimited authors",
+			authors: `["alice", "bob", "carol", "dave", "eve", "frank"]`,
+			opts:    []ParseOption{AuthorsLimit(0)},
+			expect:  "alice, bob, carol, dave, eve, frank",
+		},
+		{
+			desc:    "Mixed Latin and Arabic authors are kept in the logical order",
+			authors: `["alice", "محمد علي"]`,
+			expect:  "alice, محمد علي",
+		},
+		{
+			desc:    "Author as a string",
+			authors: `"alice"`,
+			expect:  "alice",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			input := fmt.Sprintf(`---
+title: "Title"
+authors: %s
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+---`, tc.authors)
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now(), tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Authors != tc.expect {
+				t.Fatalf("unexpected authors: got=%q, want=%q", fm.Authors, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseScalarOrList(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		authors          string
+		categories       string
+		tags             string
+		expectAuthors    string
+		expectCategories []string
+		expectTags       []string
+	}{
+		{
+			desc:             "Lists",
+			authors:          `["alice"]`,
+			categories:       `["Go"]`,
+			tags:             `["hugo"]`,
+			expectAuthors:    "alice",
+			expectCategories: []string{"Go"},
+			expectTags:       []string{"hugo"},
+		},
+		{
+			desc:             "Strings as one-item lists",
+			authors:          `alice`,
+			categories:       `Go`,
+			tags:             `hugo`,
+			expectAuthors:    "alice",
+			expectCategories: []string{"Go"},
+			expectTags:       []string{"hugo"},
+		},
+		{
+			desc:             "Block lists with mixed whitespace",
+			authors:          "\n  - \" alice\"\n  - bob",
+			categories:       "\n  -   Go\n  - \"\\t\"",
+			tags:             "\n    - hugo \n    - \"  ogp\"",
+			expectAuthors:    "alice, bob",
+			expectCategories: []string{"Go"},
+			expectTags:       []string{"hugo", "ogp"},
+		},
+		{
+			desc:             "Strings with surrounding whitespace",
+			authors:          `"  alice "`,
+			categories:       `" Go"`,
+			tags:             `"hugo  "`,
+			expectAuthors:    "alice",
+			expectCategories: []string{"Go"},
+			expectTags:       []string{"hugo"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			input := fmt.Sprintf(`---
+title: "Title"
+authors: %s
+categories: %s
+tags: %s
+date: 2020-06-21T03:56:24+09:00
+---`, tc.authors, tc.categories, tc.tags)
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now())
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Authors != tc.expectAuthors {
+				t.Errorf("unexpected authors: got=%q, want=%q", fm.Authors, tc.expectAuthors)
+			}
+			if fm.Category != tc.expectCategories[0] || !reflect.DeepEqual(fm.Categories, tc.expectCategories) {
+				t.Errorf("unexpected categories: category=%q, categories=%q", fm.Category, fm.Categories)
+			}
+			if !reflect.DeepEqual(fm.Tags, tc.expectTags) {
+				t.Errorf("unexpected tags: got=%q, want=%q", fm.Tags, tc.expectTags)
+			}
+		})
+	}
+}
+
+func TestParseTitle(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		title  string
+		opts   []ParseOption
+		expect string
+	}{
+		{
+			desc:   "Leading and trailing whitespace is trimmed by default",
+			title:  `"  Title  "`,
+			expect: "Title",
+		},
+		{
+			desc:   "Whitespace is kept without trimming",
+			title:  `"  Title  "`,
+			opts:   []ParseOption{TrimSpace(false)},
+			expect: "  Title  ",
+		},
+		{
+			desc:   "Internal whitespace is kept by default",
+			title:  `"Hello   world"`,
+			expect: "Hello   world",
+		},
+		{
+			desc:   "Runs of whitespace are collapsed",
+			title:  `" Hello \t  world\n again "`,
+			opts:   []ParseOption{CollapseSpaces(true)},
+			expect: "Hello world again",
+		},
+		{
+			desc:   "Collapsing runs before the truncation",
+			title:  fmt.Sprintf("%q", strings.Repeat("a    ", 20)),
+			opts:   []ParseOption{CollapseSpaces(true)},
+			expect: strings.TrimSpace(strings.Repeat("a ", 20)),
+		},
+		{
+			desc:   "Straight quotes are converted into curly quotes",
+			title:  `"Don't say \"hello\" ('hi')"`,
+			opts:   []ParseOption{Quotes(QuoteCurly)},
+			expect: "Don’t say “hello” (‘hi’)",
+		},
+		{
+			desc:   "Curly quotes are converted into straight quotes",
+			title:  `"Don’t say “hello” (‘hi’)"`,
+			opts:   []ParseOption{Quotes(QuoteStraight)},
+			expect: `Don't say "hello" ('hi')`,
+		},
+		{
+			desc:   "Quotes are kept by default",
+			title:  `"Don’t say \"hello\""`,
+			expect: `Don’t say "hello"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			input := fmt.Sprintf(`---
+title: %s
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+---`, tc.title)
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now(), tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Title != tc.expect {
+				t.Fatalf("unexpected title: got=%q, want=%q", fm.Title, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseTitleFallback(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		filename  string
+		fields    string
+		opts      []ParseOption
+		expect    string
+		expectErr bool
+	}{
+		{
+			desc:      "Missing title is an error by default",
+			filename:  "my-first-post.md",
+			expectErr: true,
+		},
+		{
+			desc:     "Missing title is left empty",
+			filename: "my-first-post.md",
+			opts:     []ParseOption{TitleFallback(TitleNone, "")},
+			expect:   "",
+		},
+		{
+			desc:     "Empty title is replaced with the literal",
+			filename: "my-first-post.md",
+			fields:   `title: ""`,
+			opts:     []ParseOption{TitleFallback(TitleLiteral, "Untitled")},
+			expect:   "Untitled",
+		},
+		{
+			desc:     "Title is derived from the filename",
+			filename: "my-first_post.md",
+			opts:     []ParseOption{TitleFallback(TitleSlug, "")},
+			expect:   "My first post",
+		},
+		{
+			desc:     "Title is derived from the page bundle",
+			filename: filepath.Join("über-uns", "index.md"),
+			opts:     []ParseOption{TitleFallback(TitleSlug, "")},
+			expect:   "Über uns",
+		},
+		{
+			desc:     "Title is derived from the slug of the front-matter",
+			filename: "2020-06-21.md",
+			fields:   `slug: "hello-world"`,
+			opts:     []ParseOption{TitleFallback(TitleSlug, "")},
+			expect:   "Hello world",
+		},
+		{
+			desc:     "Fallback title is truncated",
+			filename: "my-first-post.md",
+			opts:     []ParseOption{TitleFallback(TitleSlug, ""), Widths(FieldWidths{Title: 8})},
+			expect:   "My fi...",
+		},
+		{
+			desc:     "Title is kept with the fallback",
+			filename: "my-first-post.md",
+			fields:   `title: "Title"`,
+			opts:     []ParseOption{TitleFallback(TitleSlug, "")},
+			expect:   "Title",
+		},
+		{
+			desc:      "Invalid title is an error with the fallback",
+			filename:  "my-first-post.md",
+			fields:    `title: 1`,
+			opts:      []ParseOption{TitleFallback(TitleNone, "")},
+			expectErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), tc.filename)
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				t.Fatal(err)
+			}
+			input := fmt.Sprintf(`---
+%s
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+categories: ["cat1"]
+tags: ["tag1"]
+---`, tc.fields)
+			if err := os.WriteFile(p, []byte(input), 0644); err != nil {
+				t.Fatal(err)
+			}
+			fm, err := ParseFrontMatter(io.Discard, p, time.Now(), tc.opts...)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("error is expected: title=%q", fm.Title)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Title != tc.expect {
+				t.Fatalf("unexpected title: got=%q, want=%q", fm.Title, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseFrontMatterErrorContext(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		filename   string
+		input      string
+		expectLine int
+		expectKind error
+	}{
+		{
+			desc:     "Invalid type in YAML",
+			filename: "post.md",
+			input: `---
+title: "Title"
+authors: 1
+---`,
+			expectLine: 3,
+			expectKind: &FMInvalidTypeError{},
+		},
+		{
+			desc:     "Invalid type in TOML",
+			filename: "post.md",
+			input: `+++
+authors = ["alice"]
+title = 1
++++`,
+			expectLine: 3,
+			expectKind: &FMInvalidTypeError{},
+		},
+		{
+			desc:     "Invalid type in JSON",
+			filename: "post.md",
+			input: `{
+  "title": ["Title"]
+}`,
+			expectLine: 2,
+			expectKind: &FMInvalidTypeError{},
+		},
+		{
+			desc:     "Empty value in Org-mode",
+			filename: "post.org",
+			input: `#+author: alice
+#+title:
+`,
+			expectLine: 2,
+			expectKind: &FMNotExistError{},
+		},
+		{
+			desc:     "Missing key has no line",
+			filename: "post.md",
+			input: `---
+authors: ["alice"]
+---
+title: in the body`,
+			expectKind: &FMNotExistError{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), tc.filename)
+			if err := os.WriteFile(p, []byte(tc.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ParseFrontMatter(io.Discard, p, time.Now())
+			var fe *FMError
+			if !errors.As(err, &fe) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fe.File != p || fe.Line != tc.expectLine {
+				t.Fatalf("unexpected context: got=%s:%d, want=%s:%d", fe.File, fe.Line, p, tc.expectLine)
+			}
+			switch tc.expectKind.(type) {
+			case *FMInvalidTypeError:
+				var ie *FMInvalidTypeError
+				if !errors.As(err, &ie) {
+					t.Fatalf("FMInvalidTypeError is not found: %v", err)
+				}
+			case *FMNotExistError:
+				var ne *FMNotExistError
+				if !errors.As(err, &ne) {
+					t.Fatalf("FMNotExistError is not found: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFrontMatterAndBody(t *testing.T) {
+	const body = "\nFirst paragraph.\n\n---\n\n```toml\n+++\ntitle = \"code\"\n```\n"
+	testCases := []struct {
+		desc  string
+		input string
+	}{
+		{
+			desc: "YAML",
+			input: `---
+title: "Title"
+authors: ["alice"]
+categories: ["cat1"]
+tags: ["tag1"]
+---
+` + body,
+		},
+		{
+			desc: "TOML",
+			input: `+++
+title = "Title"
+authors = ["alice"]
+categories = ["cat1"]
+tags = ["tag1"]
++++
+` + body,
+		},
+		{
+			desc: "JSON",
+			input: `{
+  "title": "Title",
+  "authors": ["alice"],
+  "categories": ["cat1"],
+  "tags": ["tag1"]
+}
+` + body,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, got, err := ParseFrontMatterAndBody(io.Discard, strings.NewReader(tc.input), time.Now())
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Title != "Title" {
+				t.Fatalf("unexpected title: %q", fm.Title)
+			}
+			if string(got) != body {
+				t.Fatalf("unexpected body: got=%q, want=%q", got, body)
+			}
+		})
+	}
+}
+
+func TestParseDescription(t *testing.T) {
+	const body = "\n## Introduction\n\nThis post explains **how** the cards are generated.\n"
+	testCases := []struct {
+		desc        string
+		description string
+		opts        []ParseOption
+		expect      string
+	}{
+		{
+			desc:        "Description is used as is",
+			description: `description: "Explicit description"`,
+			opts:        []ParseOption{ExcerptLength(20)},
+			expect:      "Explicit description",
+		},
+		{
+			desc:   "Excerpt is disabled by default",
+			expect: "",
+		},
+		{
+			desc:   "Excerpt of the body is used without description",
+			opts:   []ParseOption{ExcerptLength(30), OverflowMarker("…")},
+			expect: "This post explains how the ca…",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			input := fmt.Sprintf(`---
+title: "Title"
+%s
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+---
+%s`, tc.description, body)
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now(), tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Description != tc.expect {
+				t.Fatalf("unexpected description: got=%q, want=%q", fm.Description, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseImage(t *testing.T) {
+	const body = "\nThe hero of this post.\n\n![hero](images/hero.jpg \"Hero\")\n"
+	testCases := []struct {
+		desc   string
+		images string
+		body   string
+		expect string
+	}{
+		{
+			desc:   "featured_image is used first",
+			images: "featured_image: featured.png\nimages: [\"first.png\", \"second.png\"]",
+			body:   body,
+			expect: "featured.png",
+		},
+		{
+			desc:   "First of images is used without featured_image",
+			images: `images: ["", "first.png", "second.png"]`,
+			body:   body,
+			expect: "first.png",
+		},
+		{
+			desc:   "Images of unexpected types are ignored",
+			images: "featured_image: {src: featured.png}\nimages: [{src: first.png}]",
+			body:   body,
+			expect: "images/hero.jpg",
+		},
+		{
+			desc:   "First image in the body is used without the front-matter",
+			body:   body,
+			expect: "images/hero.jpg",
+		},
+		{
+			desc:   "No image",
+			body:   "No images.\n",
+			expect: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			input := fmt.Sprintf(`---
+title: "Title"
+%s
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+---
+%s`, tc.images, tc.body)
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now())
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Image != tc.expect {
+				t.Fatalf("unexpected image: got=%q, want=%q", fm.Image, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseSlugAndURL(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		fields     string
+		expectSlug string
+		expectURL  string
+		expectErr  bool
+	}{
+		{
+			desc: "Slug and url are optional",
+		},
+		{
+			desc:       "Slug and url are parsed",
+			fields:     "slug: hello-world\nurl: /posts/hello/",
+			expectSlug: "hello-world",
+			expectURL:  "/posts/hello/",
+		},
+		{
+			desc:      "Slug must be a string",
+			fields:    "slug: [\"hello\"]",
+			expectErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			input := fmt.Sprintf(`---
+title: "Title"
+%s
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+---`, tc.fields)
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(input), time.Now())
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("error is expected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Slug != tc.expectSlug || fm.URL != tc.expectURL {
+				t.Fatalf("unexpected slug and url: got=(%q, %q), want=(%q, %q)", fm.Slug, fm.URL, tc.expectSlug, tc.expectURL)
+			}
+		})
+	}
+}
+
+func TestParseWidths(t *testing.T) {
+	input := `---
+title: "A title wider than the width"
+authors: ["alice", "bob"]
+categories: ["programming", "go"]
+tags: ["golang", "hugo", "ogp", "tcardgen"]
+date: 2020-06-21T03:56:24+09:00
+---`
+	long := strings.Repeat("long ", 20)
+	testCases := []struct {
+		desc             string
+		input            string
+		opts             []ParseOption
+		expectTitle      string
+		expectAuthors    string
+		expectCategory   string
+		expectCategories []string
+		expectTags       []string
+	}{
+		{
+			desc:             "Only the long title is truncated by default",
+			input:            strings.Replace(input, "A title wider than the width", long, 1),
+			expectTitle:      makeFixedWidthString(strings.TrimSpace(long), 89, "..."),
+			expectAuthors:    "alice, bob",
+			expectCategory:   "programming, go",
+			expectCategories: []string{"programming", "go"},
+			expectTags:       []string{"golang", "hugo", "ogp", "..."},
+		},
+		{
+			desc:             "Each field is truncated to its width",
+			input:            strings.Replace(input, `["alice", "bob"]`, `"alice and bob"`, 1),
+			opts:             []ParseOption{Widths(FieldWidths{Title: 10, Authors: 8, Category: 8, Tag: 5})},
+			expectTitle:      "A title...",
+			expectAuthors:    "alice...",
+			expectCategory:   "progr...",
+			expectCategories: []string{"progr...", "go"},
+			expectTags:       []string{"go...", "hugo", "ogp", "..."},
+		},
+		{
+			desc:             "Each field is truncated at the boundary of the words",
+			input:            strings.Replace(input, `["alice", "bob"]`, `"alice and bob"`, 1),
+			opts:             []ParseOption{Widths(FieldWidths{Title: 14, Authors: 8, Category: 8, Tag: 5}), TruncateAtBoundary(true)},
+			expectTitle:      "A title...",
+			expectAuthors:    "alice...",
+			expectCategory:   "progr...",
+			expectCategories: []string{"progr...", "go"},
+			expectTags:       []string{"go...", "hugo", "ogp", "..."},
+		},
+		{
+			desc:             "List of authors is not truncated",
+			input:            input,
+			opts:             []ParseOption{Widths(FieldWidths{Authors: 8})},
+			expectTitle:      "A title wider than the width",
+			expectAuthors:    "alice, bob",
+			expectCategory:   "programming, go",
+			expectCategories: []string{"programming", "go"},
+			expectTags:       []string{"golang", "hugo", "ogp", "..."},
+		},
+		{
+			desc:             "Widths less than 1 disable the truncation",
+			input:            strings.Replace(input, "A title wider than the width", long, 1),
+			opts:             []ParseOption{Widths(FieldWidths{Title: 0, Authors: -1})},
+			expectTitle:      strings.TrimSpace(long),
+			expectAuthors:    "alice, bob",
+			expectCategory:   "programming, go",
+			expectCategories: []string{"programming", "go"},
+			expectTags:       []string{"golang", "hugo", "ogp", "..."},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm, err := parseFrontMatter(io.Discard, strings.NewReader(tc.input), time.Now(), tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if fm.Title != tc.expectTitle || fm.Authors != tc.expectAuthors {
+				t.Fatalf("unexpected strings: title=%q, authors=%q", fm.Title, fm.Authors)
+			}
+			if fm.Category != tc.expectCategory || !reflect.DeepEqual(fm.Categories, tc.expectCategories) || !reflect.DeepEqual(fm.Tags, tc.expectTags) {
+				t.Fatalf("unexpected taxonomies: category=%q, categories=%q, tags=%q", fm.Category, fm.Categories, fm.Tags)
+			}
+		})
+	}
+}
+
+func mustParseRFC3339(t *testing.T, timeStr string) time.Time {
+	tt, err := time.Parse(time.RFC3339, timeStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tt
+}
+
+func TestMakeFixedWidthString(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string
+		length int
+		marker string
+		expect string
+	}{
+		{desc: "Short string is not truncated", input: "hello", length: 10, marker: "...", expect: "hello"},
+		{desc: "String of the length is not truncated", input: "hello", length: 5, marker: "...", expect: "hello"},
+		{desc: "ASCII string is truncated with the marker", input: "hello world", length: 8, marker: "...", expect: "hello..."},
+		{desc: "Marker is included in the width", input: "hello world", length: 5, marker: "...", expect: "he..."},
+		{desc: "Wide characters are truncated by width", input: "こんにちは", length: 7, marker: "...", expect: "こん..."},
+		{desc: "Ellipsis character", input: "こんにちは", length: 7, marker: "…", expect: "こんに…"},
+		{desc: "Emoji sequence fitting the width is kept", input: "ab👨‍👩‍👧cdef", length: 7, marker: "...", expect: "ab👨‍👩‍👧..."},
+		{desc: "Emoji sequence at the boundary is dropped as a whole", input: "abc👨‍👩‍👧def", length: 7, marker: "...", expect: "abc..."},
+		{desc: "Flag is not split", input: "a🇯🇵b", length: 3, marker: "…", expect: "a…"},
+		{desc: "Combining sequence is not split", input: "e\u0301e\u0301e\u0301", length: 2, marker: "…", expect: "e\u0301…"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := makeFixedWidthString(tc.input, tc.length, tc.marker)
+			if got != tc.expect {
+				t.Fatalf("makeFixedWidthString() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+			if w := uniseg.StringWidth(got); w > tc.length {
+				t.Fatalf("truncated string exceeds the length: got=%d, want<=%d", w, tc.length)
+			}
+		})
+	}
+}
+
+func TestMakeBoundedWidthString(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string
+		length int
+		lang   string
+		expect string
+	}{
+		{desc: "Short string is not truncated", input: "Understanding Go", length: 20, expect: "Understanding Go"},
+		{desc: "Word is not cut", input: "Understanding Concurrency in Go", length: 20, expect: "Understanding…"},
+		{desc: "Trailing spaces are dropped", input: "Go  is   fun", length: 8, expect: "Go  is…"},
+		{desc: "Overlong first word is cut hard", input: "Internationalization matters", length: 10, expect: "Internati…"},
+		{desc: "Japanese is truncated at the segment boundaries", input: "こんにちは、世界。", length: 14, lang: "ja", expect: "こんにちは、…"},
+		{desc: "Chinese is truncated like the hard cut", input: "你好世界你好世界", length: 9, lang: "zh-hans", expect: "你好世界…"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := makeBoundedWidthString(tc.input, tc.length, "…", tc.lang)
+			if got != tc.expect {
+				t.Fatalf("makeBoundedWidthString() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+			if w := uniseg.StringWidth(got); w > tc.length {
+				t.Fatalf("truncated string exceeds the length: got=%d, want<=%d", w, tc.length)
+			}
+		})
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		input      string
+		expect     *style.Overrides
+		expectWarn string
+		expectErr  string
+	}{
+		{
+			desc: "Overrides are optional",
+			input: `---
+title: "Title"
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+---`,
+		},
+		{
+			desc: "YAML",
+			input: `---
+title: "Title"
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+tcard:
+  titleColor: "#E0245E"
+  titleSize: 64
+  tagsBgColor: "#000000"
+---`,
+			expect: &style.Overrides{TitleColor: "#E0245E", TitleSize: 64, TagsBgColor: "#000000"},
+		},
+		{
+			desc: "TOML",
+			input: `+++
+title = "Title"
+authors = ["alice"]
+date = 2020-06-21T03:56:24+09:00
+tags = ["tag1"]
+categories = ["cat1"]
+[tcard]
+descriptionSize = 30.5
+infoColor = "#FFFFFF"
++++`,
+			expect: &style.Overrides{DescriptionSize: 30.5, InfoColor: "#FFFFFF"},
+		},
+		{
+			desc: "Unknown keys are warned",
+			input: `---
+title: "Title"
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+tcard:
+  titleColour: "#E0245E"
+  titleSize: 64
+---`,
+			expect:     &style.Overrides{TitleSize: 64},
+			expectWarn: `WARN: unknown front-matter key "tcard.titleColour" is ignored`,
+		},
+		{
+			desc: "Size must be a positive number",
+			input: `---
+title: "Title"
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+tcard:
+  titleSize: large
+---`,
+			expectErr: `"tcard.titleSize"`,
+		},
+		{
+			desc: "Color must be a hex color",
+			input: `---
+title: "Title"
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+tcard:
+  titleColor: red
+---`,
+			expectErr: `"tcard.titleColor": invalid hex color "red"`,
+		},
+		{
+			desc: "Overrides must be a map",
+			input: `---
+title: "Title"
+authors: ["alice"]
+date: 2020-06-21T03:56:24+09:00
+tags: ["tag1"]
+categories: ["cat1"]
+tcard: "#E0245E"
+---`,
+			expectErr: `"tcard"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var w strings.Builder
+			fm, err := parseFrontMatter(&w, strings.NewReader(tc.input), time.Now())
+			if tc.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("error containing %s is expected, got=%v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse front matter: %v", err)
+			}
+			if !reflect.DeepEqual(fm.Overrides, tc.expect) {
+				t.Fatalf("unexpected overrides: got=%+v, want=%+v", fm.Overrides, tc.expect)
+			}
+			if !strings.Contains(w.String(), tc.expectWarn) {
+				t.Fatalf("unexpected warnings: %q", w.String())
+			}
+		})
 	}
-	return tt
 }
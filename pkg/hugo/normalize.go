@@ -0,0 +1,61 @@
+package hugo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// QuoteStyle is the style which the quotes of the strings are converted into.
+type QuoteStyle string
+
+const (
+	// QuoteAsIs keeps the quotes as they are written.
+	QuoteAsIs QuoteStyle = ""
+	// QuoteCurly converts the straight quotes into the typographic (curly) quotes.
+	QuoteCurly QuoteStyle = "Curly"
+	// QuoteStraight converts the typographic (curly) quotes into the straight quotes.
+	QuoteStraight QuoteStyle = "Straight"
+)
+
+var straightQuotes = strings.NewReplacer("“", `"`, "”", `"`, "„", `"`, "‘", "'", "’", "'", "‚", "'")
+
+// normalizeString normalizes the whitespace and the quotes of the front-matter string
+// before it is truncated.
+func normalizeString(s string, po *parseOptions) string {
+	if po.collapseSpaces {
+		s = strings.Join(strings.Fields(s), " ")
+	} else if po.trimSpace {
+		s = strings.TrimSpace(s)
+	}
+	switch po.quotes {
+	case QuoteCurly:
+		s = curlyQuotes(s)
+	case QuoteStraight:
+		s = straightQuotes.Replace(s)
+	}
+	return s
+}
+
+// curlyQuotes converts the straight quotes into the curly quotes. A quote is opening at the start of
+// the string or after a space or an opening bracket, and closing otherwise, so that the apostrophes
+// in words (e.g. "don't") become closing single quotes.
+func curlyQuotes(s string) string {
+	var b strings.Builder
+	prev := ' '
+	for _, r := range s {
+		opening := unicode.IsSpace(prev) || unicode.Is(unicode.Ps, prev) || unicode.Is(unicode.Pi, prev)
+		switch {
+		case r == '"' && opening:
+			r = '“'
+		case r == '"':
+			r = '”'
+		case r == '\'' && opening:
+			r = '‘'
+		case r == '\'':
+			r = '’'
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
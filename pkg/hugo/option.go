@@ -0,0 +1,239 @@
+package hugo
+
+import "time"
+
+const (
+	defaultAuthorsLimit          = 2
+	defaultAuthorsSeparator      = ", "
+	defaultAuthorsOverflowSuffix = " et al."
+	defaultCategoryLimit         = 2
+	defaultOverflowMarker        = "..."
+	defaultLang                  = "en"
+	defaultWidth                 = 89
+)
+
+// FieldWidths are the maximum display widths of the front-matter strings. The strings wider than them are
+// truncated with the overflow marker, and a width less than 1 disables the truncation.
+// Wide characters such as CJK count as two.
+type FieldWidths struct {
+	Title       int
+	Description int
+	// Authors is the width of the authors written as a string. A list of authors is not truncated.
+	Authors int
+	// Category is the width of the category, and each of the categories.
+	Category int
+	// Tag is the width of each tag.
+	Tag int
+}
+
+// ParseOption customizes how the front-matter values are converted into FrontMatter.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	authorsLimit          int
+	authorsSeparator      string
+	authorsOverflowSuffix string
+	defaultLang           string
+	dateKeys              []string
+	requireDate           bool
+	defaultDate           time.Time
+	categoryFromFirstTag  bool
+	categoryLimit         int
+	overflowMarker        string
+	trimSpace             bool
+	collapseSpaces        bool
+	quotes                QuoteStyle
+	excerptLength         int
+	widths                FieldWidths
+	truncateAtBoundary    bool
+	titleFallback         TitleSource
+	titleFallbackText     string
+	// slug is the name of the content, from which the title is derived by TitleSlug.
+	slug string
+}
+
+func newParseOptions(opts ...ParseOption) *parseOptions {
+	po := &parseOptions{
+		authorsLimit:          defaultAuthorsLimit,
+		authorsSeparator:      defaultAuthorsSeparator,
+		authorsOverflowSuffix: defaultAuthorsOverflowSuffix,
+		defaultLang:           defaultLang,
+		dateKeys:              []string{fmDate, fmLastmod, fmPublishDate},
+		categoryLimit:         defaultCategoryLimit,
+		overflowMarker:        defaultOverflowMarker,
+		trimSpace:             true,
+		widths: FieldWidths{
+			Title:       defaultWidth,
+			Description: defaultWidth,
+			Authors:     defaultWidth,
+		},
+	}
+	for _, f := range opts {
+		f(po)
+	}
+	return po
+}
+
+// AuthorsLimit sets the maximum number of authors listed in full.
+// If a post has more authors than the limit, only the first authors up to the limit are shown
+// followed by the overflow suffix. A limit less than 1 lists all authors.
+func AuthorsLimit(n int) ParseOption {
+	return func(po *parseOptions) {
+		po.authorsLimit = n
+	}
+}
+
+// AuthorsSeparator sets the separator used to join authors.
+func AuthorsSeparator(sep string) ParseOption {
+	return func(po *parseOptions) {
+		po.authorsSeparator = sep
+	}
+}
+
+// AuthorsOverflowSuffix sets the suffix appended to the authors up to the limit when the number of
+// authors exceeds it (e.g. " et al.").
+func AuthorsOverflowSuffix(suffix string) ParseOption {
+	return func(po *parseOptions) {
+		po.authorsOverflowSuffix = suffix
+	}
+}
+
+// DefaultLang sets the language used when the post does not define "lang".
+// It is used to pick a date from localized dates defined as a map.
+func DefaultLang(lang string) ParseOption {
+	return func(po *parseOptions) {
+		po.defaultLang = lang
+	}
+}
+
+// DateKeys sets the front-matter keys of the content date in priority order.
+// The default is "date", "lastmod", and "publishDate".
+func DateKeys(keys ...string) ParseOption {
+	return func(po *parseOptions) {
+		if len(keys) > 0 {
+			po.dateKeys = keys
+		}
+	}
+}
+
+// RequireDate makes parsing fail with FMNotExistError when none of the date keys exist.
+// Otherwise, the current time is used as the content date with a warning.
+func RequireDate(required bool) ParseOption {
+	return func(po *parseOptions) {
+		po.requireDate = required
+	}
+}
+
+// DefaultDate sets the date of the post which defines none of the date keys, instead of the current time
+// with a warning, so that the cards are identical across rebuilds. A zero time uses the current time.
+// It is ignored if the date is required by RequireDate.
+func DefaultDate(t time.Time) ParseOption {
+	return func(po *parseOptions) {
+		po.defaultDate = t
+	}
+}
+
+// CategoryFromFirstTag promotes the first tag to the category when the post has no categories.
+// The promoted tag is removed from the tags.
+func CategoryFromFirstTag(enabled bool) ParseOption {
+	return func(po *parseOptions) {
+		po.categoryFromFirstTag = enabled
+	}
+}
+
+// OverflowMarker sets the marker appended to the strings truncated to the maximum width, such as
+// the title. The default is "...", and "…" is narrower for Japanese texts.
+func OverflowMarker(marker string) ParseOption {
+	return func(po *parseOptions) {
+		po.overflowMarker = marker
+	}
+}
+
+// TrimSpace trims the leading and trailing whitespace of the strings such as the title.
+// It is enabled by default.
+func TrimSpace(enabled bool) ParseOption {
+	return func(po *parseOptions) {
+		po.trimSpace = enabled
+	}
+}
+
+// CollapseSpaces collapses the runs of whitespace, including newlines, in the strings such as
+// the title into single spaces. The leading and trailing whitespace is trimmed as well.
+func CollapseSpaces(enabled bool) ParseOption {
+	return func(po *parseOptions) {
+		po.collapseSpaces = enabled
+	}
+}
+
+// Quotes converts the quotes of the strings such as the title into the style.
+// The quotes are kept as they are by default.
+func Quotes(style QuoteStyle) ParseOption {
+	return func(po *parseOptions) {
+		po.quotes = style
+	}
+}
+
+// CategoryLimit sets the maximum number of the categories joined into the category, which are followed by
+// " ..." if the post has more. A limit less than 1 joins all the categories.
+func CategoryLimit(n int) ParseOption {
+	return func(po *parseOptions) {
+		po.categoryLimit = n
+	}
+}
+
+// ExcerptLength enables the excerpt of the content body as the description of the post which does not
+// define it. The excerpt is truncated to fit in the length (display width) with the overflow marker.
+// The length less than 1 disables the excerpt, which is the default.
+func ExcerptLength(n int) ParseOption {
+	return func(po *parseOptions) {
+		po.excerptLength = n
+	}
+}
+
+// TruncateAtBoundary truncates the front-matter strings at the last boundary of the words before their widths
+// instead of cutting a word in the middle (e.g. "Understanding…" instead of "Understanding Conc…").
+// A string whose first word does not fit is cut hard. It is disabled by default.
+func TruncateAtBoundary(enabled bool) ParseOption {
+	return func(po *parseOptions) {
+		po.truncateAtBoundary = enabled
+	}
+}
+
+// TitleSource is the source of the title of the post which does not define it.
+type TitleSource string
+
+const (
+	// TitleRequired makes parsing fail with FMNotExistError when the title does not exist.
+	TitleRequired TitleSource = ""
+	// TitleNone leaves the title empty, so that the card is generated without it.
+	TitleNone TitleSource = "None"
+	// TitleSlug derives the title from the "slug" of the post, or the name of its content file or record
+	// (e.g. "my-first-post" to "My first post").
+	TitleSlug TitleSource = "Slug"
+	// TitleLiteral uses the literal text as the title.
+	TitleLiteral TitleSource = "Literal"
+)
+
+// TitleFallback sets the source of the title of the post which does not define it or defines it empty.
+// The text is the title of TitleLiteral. The title is required by default.
+func TitleFallback(source TitleSource, text string) ParseOption {
+	return func(po *parseOptions) {
+		po.titleFallback = source
+		po.titleFallbackText = text
+	}
+}
+
+// contentSlug sets the name of the content to derive the title from.
+func contentSlug(slug string) ParseOption {
+	return func(po *parseOptions) {
+		po.slug = slug
+	}
+}
+
+// Widths sets the maximum display widths of the front-matter strings. By default, the title, description,
+// and authors are truncated to 89, and the category and tags are not truncated.
+func Widths(widths FieldWidths) ParseOption {
+	return func(po *parseOptions) {
+		po.widths = widths
+	}
+}
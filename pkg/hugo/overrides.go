@@ -0,0 +1,81 @@
+package hugo
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/gohugoio/hugo/parser/pageparser"
+
+	"github.com/shunk031/tcardgen/pkg/style"
+)
+
+// getOverrides returns the overrides of the "tcard" map. The unknown keys of the map are warned and ignored,
+// so that a typo does not fail the card.
+func getOverrides(w io.Writer, cfm *pageparser.ContentFrontMatter) (*style.Overrides, error) {
+	v, ok := cfm.FrontMatter[fmOverrides]
+	if !ok {
+		return nil, NewFMNotExistError(fmOverrides)
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, NewFMInvalidTypeError(fmOverrides, "map", v)
+	}
+
+	o := &style.Overrides{}
+	fields := map[string]any{
+		"titleColor":       &o.TitleColor,
+		"titleSize":        &o.TitleSize,
+		"descriptionColor": &o.DescriptionColor,
+		"descriptionSize":  &o.DescriptionSize,
+		"categoryColor":    &o.CategoryColor,
+		"categorySize":     &o.CategorySize,
+		"infoColor":        &o.InfoColor,
+		"infoSize":         &o.InfoSize,
+		"tagsColor":        &o.TagsColor,
+		"tagsSize":         &o.TagsSize,
+		"tagsBgColor":      &o.TagsBgColor,
+	}
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		key := fmOverrides + "." + k
+		switch f := fields[k].(type) {
+		case *string:
+			s, ok := m[k].(string)
+			if !ok {
+				return nil, NewFMInvalidTypeError(key, "string", m[k])
+			}
+			// All the strings are colors, which are checked here so that the file of an invalid one is reported
+			// instead of failing to draw its card.
+			if _, err := style.ParseHexColor(s); err != nil {
+				return nil, fmt.Errorf("%q: %w", key, err)
+			}
+			*f = s
+		case *float64:
+			n, ok := toFloat(m[k])
+			if !ok || n <= 0 {
+				return nil, NewFMInvalidTypeError(key, "positive number", m[k])
+			}
+			*f = n
+		default:
+			fmt.Fprintf(w, "WARN: unknown front-matter key %q is ignored\n", key)
+		}
+	}
+	return o, nil
+}
+
+// toFloat returns the number of the front-matter value, which is decoded as an integer or a float
+// depending on the format.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,154 @@
+package hugo
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/parser/pageparser"
+)
+
+// DefaultRecordNameColumn is the column used to name the card of a record.
+const DefaultRecordNameColumn = "name"
+
+// recordListSeparator separates the items of a list column (e.g. "alice;bob").
+const recordListSeparator = ";"
+
+// recordListColumns are the columns of which values are lists like in the front-matter.
+var recordListColumns = []string{fmAuthors, fmCategories, fmTags}
+
+// Record is a row of a CSV/TSV file, which is converted into FrontMatter as a Hugo content.
+type Record struct {
+	// Name is the value of the name column, or "<file>-<row>" if it is empty.
+	Name string
+	// Line is the line of the row in the file, which tells the rows of the same name apart.
+	Line        int
+	FrontMatter *FrontMatter
+	// Err is the error converting the row into FrontMatter.
+	Err error
+}
+
+// IsRecordFile reports whether the file is a CSV or TSV file.
+func IsRecordFile(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".tsv":
+		return true
+	}
+	return false
+}
+
+// ReadRecordFile reads the records of the CSV or TSV file. See ParseRecords for the format.
+func ReadRecordFile(w io.Writer, filename, nameColumn string, currentTime time.Time, opts ...ParseOption) ([]*Record, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	comma := ','
+	if strings.EqualFold(filepath.Ext(filename), ".tsv") {
+		comma = '\t'
+	}
+	name := trimExt(filepath.Base(filename))
+	records, err := ParseRecords(w, f, comma, name, nameColumn, currentTime, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		var fe *FMError
+		if errors.As(rec.Err, &fe) {
+			fe.File = filename
+		}
+	}
+	return records, nil
+}
+
+// ParseRecords parses the records separated by the comma. The header row names the columns after
+// the front-matter keys (e.g. title, authors, categories, tags, date), and the items of the authors,
+// categories, and tags columns are separated by ";". Unknown columns are ignored, and missing columns
+// and cells are treated as missing front-matter values. A row that cannot be read or converted into
+// FrontMatter has the error in the record, so that the other rows can still be used.
+func ParseRecords(w io.Writer, r io.Reader, comma rune, name, nameColumn string, currentTime time.Time, opts ...ParseOption) ([]*Record, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("header row is missing")
+		}
+		return nil, err
+	}
+	for i, h := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+	}
+
+	po := newParseOptions(opts...)
+	var records []*Record
+	for row := 1; ; row++ {
+		cells, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		rec := &Record{Name: fmt.Sprintf("%s-%d", name, row)}
+		if err != nil {
+			// a malformed row fails only itself, while the other errors stop reading the rest
+			rec.Err = &FMError{File: name, Err: err}
+			records = append(records, rec)
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				break
+			}
+			rec.Line = pe.Line
+			rec.Err = &FMError{File: name, Line: pe.Line, Err: pe.Err}
+			continue
+		}
+		rec.Line, _ = cr.FieldPos(0)
+
+		values := map[string]interface{}{}
+		for i, cell := range cells {
+			if i >= len(header) || cell == "" {
+				continue
+			}
+			key := header[i]
+			// the name column is a front-matter value as well, e.g. the title
+			if strings.EqualFold(key, nameColumn) {
+				rec.Name = filepath.Base(cell)
+			}
+			switch {
+			case slices.Contains(recordListColumns, key):
+				var items []interface{}
+				for _, item := range strings.Split(cell, recordListSeparator) {
+					items = append(items, strings.TrimSpace(item))
+				}
+				values[key] = items
+			default:
+				values[key] = cell
+			}
+		}
+
+		// the name of a card must be a file name, which "." or ".." of the cell is not
+		if rec.Name == "." || rec.Name == ".." || rec.Name == string(filepath.Separator) {
+			rec.Err = &FMError{File: name, Line: rec.Line, Err: fmt.Errorf("%q column %q cannot name a card", nameColumn, rec.Name)}
+			records = append(records, rec)
+			continue
+		}
+
+		rpo := *po
+		rpo.slug = rec.Name
+		rec.FrontMatter, rec.Err = newFrontMatter(w, pageparser.ContentFrontMatter{FrontMatter: values}, currentTime, &rpo)
+		if rec.Err != nil {
+			rec.Err = &FMError{File: name, Line: rec.Line, Err: rec.Err}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
@@ -0,0 +1,148 @@
+package hugo
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRecords(t *testing.T) {
+	currentTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	input := `name,title,authors,categories,tags,date,extra
+first,"Title, with a comma","alice; bob",program,"hugo;go",2020-06-21,ignored
+,"Missing ""name""",carol,misc,ogp
+third,,dave,misc,ogp,2021-01-02
+fourth,Short row
+fifth,Bare "quote",erin,misc,ogp
+sixth,After the malformed row,frank,misc,ogp,2022-03-04
+`
+	records, err := ParseRecords(io.Discard, strings.NewReader(input), ',', "cards", DefaultRecordNameColumn, currentTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 {
+		t.Fatalf("unexpected number of records: %d", len(records))
+	}
+
+	expect := []struct {
+		name string
+		fm   *FrontMatter
+		// errLine is the line of the row which has an error
+		errLine int
+	}{
+		{
+			name: "first",
+			fm: &FrontMatter{
+				Title:      "Title, with a comma",
+				Authors:    "alice, bob",
+				Category:   "program",
+				Categories: []string{"program"},
+				Tags:       []string{"hugo", "go"},
+				Date:       time.Date(2020, 6, 21, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "cards-2",
+			fm: &FrontMatter{
+				Title:      `Missing "name"`,
+				Authors:    "carol",
+				Category:   "misc",
+				Categories: []string{"misc"},
+				Tags:       []string{"ogp"},
+				Date:       currentTime,
+			},
+		},
+		{name: "third", errLine: 4}, // title is missing
+		{name: "fourth", errLine: 5},
+		{name: "cards-5", errLine: 6}, // the row cannot be read
+		{
+			name: "sixth",
+			fm: &FrontMatter{
+				Title:      "After the malformed row",
+				Authors:    "frank",
+				Category:   "misc",
+				Categories: []string{"misc"},
+				Tags:       []string{"ogp"},
+				Date:       time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for i, want := range expect {
+		rec := records[i]
+		if rec.Name != want.name {
+			t.Fatalf("record #%d: unexpected name: got=%q, want=%q", i, rec.Name, want.name)
+		}
+		if want.errLine > 0 {
+			var fe *FMError
+			if !errors.As(rec.Err, &fe) {
+				t.Fatalf("record #%d: expected an error: %v", i, rec.Err)
+			}
+			if fe.File != "cards" || fe.Line != want.errLine {
+				t.Fatalf("record #%d: unexpected context: got=%s:%d, want=cards:%d", i, fe.File, fe.Line, want.errLine)
+			}
+			continue
+		}
+		if rec.Err != nil {
+			t.Fatalf("record #%d: %v", i, rec.Err)
+		}
+		if !reflect.DeepEqual(rec.FrontMatter, want.fm) {
+			t.Fatalf("record #%d: unexpected front matter: got=%#+v, want=%#+v", i, *rec.FrontMatter, *want.fm)
+		}
+	}
+}
+
+func TestParseRecordsNameColumnIsValue(t *testing.T) {
+	input := `title,authors,categories,tags
+Hello world,alice,misc,ogp
+`
+	records, err := ParseRecords(io.Discard, strings.NewReader(input), ',', "cards", "title", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := records[0]; rec.Err != nil || rec.Name != "Hello world" || rec.FrontMatter.Title != "Hello world" {
+		t.Fatalf("name column is not the title: name=%q, err=%v, fm=%+v", rec.Name, rec.Err, rec.FrontMatter)
+	}
+}
+
+func TestParseRecordsRejectsDotNames(t *testing.T) {
+	input := `name,title,authors,categories,tags
+.,Dot,alice,misc,ogp
+..,Dot dot,alice,misc,ogp
+hello,Hello,alice,misc,ogp
+`
+	records, err := ParseRecords(io.Discard, strings.NewReader(input), ',', "cards", DefaultRecordNameColumn, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, rec := range records {
+		if rec.Line != i+2 {
+			t.Fatalf("record #%d: unexpected line: %d", i, rec.Line)
+		}
+		if failed := rec.Err != nil; failed != (rec.Name != "hello") {
+			t.Fatalf("record #%d: unexpected error of %q: %v", i, rec.Name, rec.Err)
+		}
+	}
+}
+
+func TestParseRecordsTitleFallback(t *testing.T) {
+	input := `name,title,authors,categories,tags
+hello-world,,alice,misc,ogp
+,,alice,misc,ogp
+`
+	records, err := ParseRecords(io.Discard, strings.NewReader(input), ',', "cards", DefaultRecordNameColumn, time.Now(),
+		TitleFallback(TitleSlug, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"Hello world", "Cards 2"} {
+		if records[i].Err != nil {
+			t.Fatalf("record #%d: %v", i, records[i].Err)
+		}
+		if records[i].FrontMatter.Title != want {
+			t.Fatalf("record #%d: unexpected title: got=%q, want=%q", i, records[i].FrontMatter.Title, want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package hugo
+
+import (
+	"unicode"
+)
+
+// cjkScripts are the scripts which are written without spaces between words.
+var cjkScripts = []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul}
+
+// CountWords counts the words of the content body. Each CJK character is counted as a word,
+// since they are not delimited by whitespace. Runs of punctuation such as the Markdown syntax
+// are not counted.
+func CountWords(s string) int {
+	var n int
+	var inWord bool
+	for _, r := range s {
+		switch {
+		case unicode.In(r, cjkScripts...):
+			n++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if !inWord {
+				n++
+				inWord = true
+			}
+		case unicode.IsSpace(r):
+			inWord = false
+		}
+	}
+	return n
+}
+
+// ReadingTime returns the minutes to read the content body at the words per minute, rounded up.
+// It returns 0 if the content has no words or the words per minute is not positive.
+func (fm *FrontMatter) ReadingTime(wpm int) int {
+	if fm.WordCount == 0 || wpm <= 0 {
+		return 0
+	}
+	return (fm.WordCount + wpm - 1) / wpm
+}
@@ -0,0 +1,70 @@
+package hugo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountWords(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string
+		expect int
+	}{
+		{desc: "Empty", input: "", expect: 0},
+		{desc: "Latin words", input: "Hello, world! It's a post.", expect: 5},
+		{desc: "Markdown syntax", input: "## Heading\n\n- item one\n\n---\n```go\nfmt.Println()\n```", expect: 5},
+		{desc: "CJK characters", input: "自動生成したい", expect: 7},
+		{desc: "Mixed scripts", input: "HugoでTwitter Cardを生成", expect: 7},
+		{desc: "Hangul", input: "안녕 하세요", expect: 5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := CountWords(tc.input); got != tc.expect {
+				t.Fatalf("CountWords() returns unexpected value: got=%d, want=%d", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		wordCount int
+		wpm       int
+		expect    int
+	}{
+		{desc: "No words", wordCount: 0, wpm: 200, expect: 0},
+		{desc: "Less than a minute", wordCount: 10, wpm: 200, expect: 1},
+		{desc: "Exact minutes", wordCount: 400, wpm: 200, expect: 2},
+		{desc: "Rounded up", wordCount: 401, wpm: 200, expect: 3},
+		{desc: "Invalid words per minute", wordCount: 400, wpm: 0, expect: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fm := &FrontMatter{WordCount: tc.wordCount}
+			if got := fm.ReadingTime(tc.wpm); got != tc.expect {
+				t.Fatalf("ReadingTime() returns unexpected value: got=%d, want=%d", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestParseWordCount(t *testing.T) {
+	input := `---
+title: "Title"
+authors: ["@shunk031"]
+date: 2020-06-21T03:56:24+09:00
+categories: ["program"]
+tags: ["hugo"]
+---
+Hugoで自動生成 of Twitter cards.
+`
+	fm, err := parseFrontMatter(&strings.Builder{}, strings.NewReader(input), mustParseRFC3339(t, "2020-06-21T03:56:24+09:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm.WordCount != 9 {
+		t.Fatalf("unexpected word count: got=%d, want=9", fm.WordCount)
+	}
+}
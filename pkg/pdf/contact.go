@@ -0,0 +1,78 @@
+package pdf
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pageMargin    = 10.0 // mm
+	cellSpacing   = 4.0  // mm
+	captionHeight = 5.0  // mm
+	captionSize   = 8.0  // pt
+)
+
+// ContactSheet places the generated cards on A4 pages in a grid for print review.
+type ContactSheet struct {
+	Columns int
+	Rows    int
+}
+
+// NewContactSheet returns a ContactSheet with the default 2x4 grid.
+func NewContactSheet() *ContactSheet {
+	return &ContactSheet{Columns: 2, Rows: 4}
+}
+
+// PerPage returns the number of cards placed on a page.
+func (cs *ContactSheet) PerPage() int {
+	return cs.Columns * cs.Rows
+}
+
+// Write writes a PDF document containing the specified PNG cards to w.
+// Each card is captioned with its filename.
+func (cs *ContactSheet) Write(w io.Writer, cards []string) error {
+	if cs.Columns < 1 || cs.Rows < 1 {
+		return errors.New("columns and rows of the contact sheet must be positive")
+	}
+	if len(cards) == 0 {
+		return errors.New("no cards to write into the contact sheet")
+	}
+
+	doc := fpdf.New("P", "mm", "A4", "")
+	doc.SetMargins(pageMargin, pageMargin, pageMargin)
+	doc.SetAutoPageBreak(false, pageMargin)
+	doc.SetFont("Helvetica", "", captionSize)
+
+	pw, ph := doc.GetPageSize()
+	cw := (pw - 2*pageMargin - cellSpacing*float64(cs.Columns-1)) / float64(cs.Columns)
+	ch := (ph - 2*pageMargin - cellSpacing*float64(cs.Rows-1)) / float64(cs.Rows)
+
+	for i, card := range cards {
+		if i%cs.PerPage() == 0 {
+			doc.AddPage()
+		}
+		opt := fpdf.ImageOptions{ImageType: "PNG"}
+		info := doc.RegisterImageOptions(card, opt)
+		if err := doc.Error(); err != nil {
+			return err
+		}
+
+		// fit the card into the cell keeping its aspect ratio
+		iw, ih := cw, cw*info.Height()/info.Width()
+		if ih > ch-captionHeight {
+			ih = ch - captionHeight
+			iw = ih * info.Width() / info.Height()
+		}
+
+		n := i % cs.PerPage()
+		x := pageMargin + float64(n%cs.Columns)*(cw+cellSpacing)
+		y := pageMargin + float64(n/cs.Columns)*(ch+cellSpacing)
+		doc.ImageOptions(card, x+(cw-iw)/2, y, iw, ih, false, opt, 0, "")
+		doc.SetXY(x, y+ih)
+		doc.CellFormat(cw, captionHeight, filepath.Base(card), "", 0, "C", false, 0, "")
+	}
+	return doc.Output(w)
+}
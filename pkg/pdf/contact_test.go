@@ -0,0 +1,58 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestContactSheetPageCount(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		numCards   int
+		sheet      *ContactSheet
+		expectPage int
+	}{
+		{desc: "One card per page", numCards: 3, sheet: &ContactSheet{Columns: 1, Rows: 1}, expectPage: 3},
+		{desc: "Cards fill a page", numCards: 8, sheet: NewContactSheet(), expectPage: 1},
+		{desc: "Cards overflow to the next page", numCards: 9, sheet: NewContactSheet(), expectPage: 2},
+	}
+	pageRe := regexp.MustCompile(`/Type /Page\b[^s]`)
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cards := mustCreateCards(t, tc.numCards)
+			var buf bytes.Buffer
+			if err := tc.sheet.Write(&buf, cards); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(pageRe.FindAll(buf.Bytes(), -1)); got != tc.expectPage {
+				t.Fatalf("unexpected page count: got=%d, want=%d", got, tc.expectPage)
+			}
+		})
+	}
+}
+
+func mustCreateCards(t *testing.T, n int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var cards []string
+	for i := 0; i < n; i++ {
+		fn := filepath.Join(dir, fmt.Sprintf("card%d.png", i))
+		f, err := os.Create(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 120, 63))); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		cards = append(cards, fn)
+	}
+	return cards
+}
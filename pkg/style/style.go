@@ -0,0 +1,46 @@
+// Package style defines the styles of a card which are shared by the drawing configuration and the
+// front-matter of the posts.
+package style
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+)
+
+// Overrides are the styles of the card of a post which override the drawing configuration, which are
+// parsed from the "tcard" map of the front-matter:
+//
+//	tcard:
+//	  titleColor: "#E0245E"
+//	  titleSize: 64
+//
+// The colors are hex colors, and the sizes are font sizes. The values which are not set are not overridden.
+type Overrides struct {
+	TitleColor       string  `json:"titleColor,omitempty"`
+	TitleSize        float64 `json:"titleSize,omitempty"`
+	DescriptionColor string  `json:"descriptionColor,omitempty"`
+	DescriptionSize  float64 `json:"descriptionSize,omitempty"`
+	// CategoryColor and CategorySize are of the category, or the categories drawn in boxes.
+	CategoryColor string  `json:"categoryColor,omitempty"`
+	CategorySize  float64 `json:"categorySize,omitempty"`
+	InfoColor     string  `json:"infoColor,omitempty"`
+	InfoSize      float64 `json:"infoSize,omitempty"`
+	TagsColor     string  `json:"tagsColor,omitempty"`
+	TagsSize      float64 `json:"tagsSize,omitempty"`
+	// TagsBgColor is the color of the boxes of the tags.
+	TagsBgColor string `json:"tagsBgColor,omitempty"`
+}
+
+// ParseHexColor parses the color hex (e.g. "#60BCE0") into an opaque color. The hex must be "#" followed by
+// exactly six hex digits.
+func ParseHexColor(hex string) (color.RGBA, error) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", hex)
+	}
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", hex)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+}
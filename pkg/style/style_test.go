@@ -0,0 +1,40 @@
+package style
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	testCases := []struct {
+		hex       string
+		expect    color.RGBA
+		expectErr bool
+	}{
+		{hex: "#60BCE0", expect: color.RGBA{0x60, 0xBC, 0xE0, 0xFF}},
+		{hex: "#ff8000", expect: color.RGBA{0xFF, 0x80, 0x00, 0xFF}},
+		{hex: "#12345", expectErr: true},
+		{hex: "#1234567", expectErr: true},
+		{hex: "60BCE0", expectErr: true},
+		{hex: "#GGGGGG", expectErr: true},
+		{hex: "#+12345", expectErr: true},
+		{hex: "green", expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.hex, func(t *testing.T) {
+			got, err := ParseHexColor(tc.hex)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got=%v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.expect {
+				t.Fatalf("unexpected color: got=%v, want=%v", got, tc.expect)
+			}
+		})
+	}
+}
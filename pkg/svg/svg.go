@@ -0,0 +1,208 @@
+// Package svg renders cards into SVG documents with the texts and the boxes as vector elements.
+package svg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+	"github.com/shunk031/tcardgen/pkg/text"
+)
+
+// Renderer renders a card into an SVG document. The pixels of the card are embedded as a PNG image, and
+// the texts and the boxes recorded on it are drawn over them as <text> and <rect> elements at the
+// positions of the raster layout, so that they are scaled without blur.
+type Renderer struct {
+	// Fonts are embedded into the document by @font-face rules if it is not nil. Otherwise the texts refer
+	// to the fonts by the family name, which must be installed where the document is viewed.
+	Fonts *fontfamily.FontFamily
+}
+
+// NewRenderer returns a Renderer which refers to the fonts by the family name.
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// Write writes the SVG document of the card to w. The background is the canvas on which the layout is
+// recorded by canvas.Canvas.Record, which has everything but the texts and the boxes drawn.
+func (r *Renderer) Write(w io.Writer, bg image.Image, l *canvas.Layout) error {
+	if bg == nil || l == nil {
+		return errors.New("background and layout are required")
+	}
+	b := bg.Bounds()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" xml:space="preserve" width="%d" height="%d" viewBox="%d %d %d %d">`+"\n",
+		b.Dx(), b.Dy(), b.Min.X, b.Min.Y, b.Dx(), b.Dy())
+
+	if r.Fonts != nil {
+		if err := r.writeFontFaces(&buf, l.Texts); err != nil {
+			return err
+		}
+	}
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, bg); err != nil {
+		return err
+	}
+	fmt.Fprintf(&buf, `<image x="%d" y="%d" width="%d" height="%d" href="data:image/png;base64,%s"/>`+"\n",
+		b.Min.X, b.Min.Y, b.Dx(), b.Dy(), base64.StdEncoding.EncodeToString(img.Bytes()))
+
+	for _, box := range l.Boxes {
+		writeBox(&buf, box)
+	}
+	for _, run := range l.Texts {
+		writeText(&buf, run)
+	}
+	buf.WriteString("</svg>\n")
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// writeFontFaces writes the @font-face rules of the font styles of the texts, which embed the font files.
+func (r *Renderer) writeFontFaces(buf *bytes.Buffer, runs []canvas.TextRun) error {
+	var styles []fontfamily.Style
+	for _, run := range runs {
+		if run.FontFamily == r.Fonts.Name && run.FontStyle != "" && !slices.Contains(styles, run.FontStyle) {
+			styles = append(styles, run.FontStyle)
+		}
+	}
+	if len(styles) == 0 {
+		return nil
+	}
+
+	buf.WriteString("<defs><style>\n")
+	for _, s := range styles {
+		fb, _, err := r.Fonts.FontFile(s)
+		if err != nil {
+			return err
+		}
+		weight, style := fontWeight(s)
+		fmt.Fprintf(buf, `@font-face { font-family: %s; font-weight: %s; font-style: %s; src: url(data:font/ttf;base64,%s); }`+"\n",
+			strconv.Quote(r.Fonts.Name), weight, style, base64.StdEncoding.EncodeToString(fb))
+	}
+	buf.WriteString("</style></defs>\n")
+	return nil
+}
+
+// writeBox writes the box as a <rect>. The border is drawn inside the box as the raster box does.
+func writeBox(buf *bytes.Buffer, box canvas.BoxRect) {
+	rect := box.Rect
+	x, y := float64(rect.Min.X), float64(rect.Min.Y)
+	w, h := float64(rect.Dx()), float64(rect.Dy())
+	radius := min(float64(box.Radius), w/2, h/2)
+
+	var stroke []string
+	if box.BorderColor != nil && box.BorderWidth > 0 {
+		// the stroke is centered on the outline, so the outline is inset by the half of the width
+		bw := float64(box.BorderWidth)
+		x, y, w, h = x+bw/2, y+bw/2, w-bw, h-bw
+		radius = max(radius-bw/2, 0)
+		stroke = append(paint("stroke", box.BorderColor), attr("stroke-width", num(bw)))
+	}
+	var attrs []string
+	if radius > 0 {
+		attrs = append(attrs, attr("rx", num(radius)))
+	}
+	attrs = append(attrs, paint("fill", box.Fill)...)
+	attrs = append(attrs, stroke...)
+	fmt.Fprintf(buf, `<rect x="%s" y="%s" width="%s" height="%s" %s/>`+"\n", num(x), num(y), num(w), num(h), strings.Join(attrs, " "))
+}
+
+// writeText writes the run as a <text> whose width is adjusted to the advance width of the raster layout,
+// so that a line laid out with the font does not overflow even if it is rendered with another font.
+func writeText(buf *bytes.Buffer, run canvas.TextRun) {
+	attrs := []string{attr("x", num(run.X)), attr("y", num(run.Y))}
+	if run.FontFamily != "" {
+		attrs = append(attrs, attr("font-family", run.FontFamily))
+	}
+	if run.FontSize > 0 {
+		attrs = append(attrs, attr("font-size", num(run.FontSize)))
+	}
+	if run.FontStyle != "" {
+		weight, style := fontWeight(run.FontStyle)
+		attrs = append(attrs, attr("font-weight", weight), attr("font-style", style))
+	}
+	attrs = append(attrs, paint("fill", run.Color)...)
+	if run.StrokeColor != nil && run.StrokeWidth > 0 {
+		// the stroke is drawn under the fill, so that only the outside half of it is visible
+		attrs = append(attrs, paint("stroke", run.StrokeColor)...)
+		attrs = append(attrs, attr("stroke-width", strconv.Itoa(run.StrokeWidth*2)), attr("stroke-linejoin", "round"), attr("paint-order", "stroke"))
+	}
+	if run.LetterSpacing != 0 {
+		attrs = append(attrs, attr("letter-spacing", strconv.Itoa(run.LetterSpacing)))
+	}
+	if run.NoKerning {
+		attrs = append(attrs, attr("font-kerning", "none"))
+	}
+	var decorations []string
+	if run.Underline {
+		decorations = append(decorations, "underline")
+	}
+	if run.Strikethrough {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		attrs = append(attrs, attr("text-decoration", strings.Join(decorations, " ")))
+	}
+	if text.BaseDirection(run.Text) == text.RightToLeft {
+		// the text is already in the visual order
+		attrs = append(attrs, attr("direction", "ltr"), attr("unicode-bidi", "bidi-override"))
+	}
+	if run.Width > 0 {
+		attrs = append(attrs, attr("textLength", num(run.Width)), attr("lengthAdjust", "spacing"))
+	}
+
+	fmt.Fprintf(buf, "<text %s>", strings.Join(attrs, " "))
+	xml.EscapeText(buf, []byte(run.Text))
+	buf.WriteString("</text>\n")
+}
+
+// fontWeight returns the CSS font-weight and font-style of the style. The styles without a weight are normal.
+func fontWeight(s fontfamily.Style) (weight, style string) {
+	w, italic, ok := s.Weight()
+	if !ok {
+		return "normal", "normal"
+	}
+	if italic {
+		return strconv.Itoa(w), "italic"
+	}
+	return strconv.Itoa(w), "normal"
+}
+
+// paint returns the attributes to paint the property (fill or stroke) in the color with its opacity.
+func paint(property string, c color.Color) []string {
+	if c == nil {
+		return nil
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	attrs := []string{attr(property, fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B))}
+	if n.A != 0xff {
+		attrs = append(attrs, attr(property+"-opacity", num(math.Round(float64(n.A)/0xff*1000)/1000)))
+	}
+	return attrs
+}
+
+// attr returns the attribute with the escaped value.
+func attr(name, value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return fmt.Sprintf(`%s="%s"`, name, b.String())
+}
+
+// num formats the number in the shortest form.
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
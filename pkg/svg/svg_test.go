@@ -0,0 +1,76 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/shunk031/tcardgen/internal/testfont"
+	"github.com/shunk031/tcardgen/pkg/canvas"
+	"github.com/shunk031/tcardgen/pkg/canvas/fontfamily"
+)
+
+func TestRendererWrite(t *testing.T) {
+	bg := image.NewRGBA(image.Rect(0, 0, 120, 60))
+	l := &canvas.Layout{
+		Texts: []canvas.TextRun{{
+			Text: "Tom & Jerry", X: 10, Y: 30.5, Width: 80,
+			FontFamily: "Go", FontStyle: fontfamily.Bold, FontSize: 20,
+			Color: color.RGBA{255, 0, 0, 255}, Underline: true,
+		}},
+		Boxes: []canvas.BoxRect{{
+			Rect: image.Rect(10, 40, 50, 56), Radius: 4,
+			Fill: color.NRGBA{0, 0, 255, 128}, BorderColor: color.Black, BorderWidth: 2,
+		}},
+	}
+
+	testCases := []struct {
+		desc   string
+		fonts  bool
+		expect []string
+	}{
+		{
+			desc:  "Texts and boxes are vector elements",
+			fonts: false,
+			expect: []string{
+				`width="120" height="60"`,
+				`href="data:image/png;base64,`,
+				`<text x="10" y="30.5" font-family="Go" font-size="20" font-weight="700" font-style="normal" fill="#ff0000" text-decoration="underline" textLength="80" lengthAdjust="spacing">Tom &amp; Jerry</text>`,
+				`<rect x="11" y="41" width="38" height="14" rx="3" fill="#0000ff" fill-opacity="0.502" stroke="#000000" stroke-width="2"/>`,
+			},
+		},
+		{
+			desc:   "Fonts are embedded",
+			fonts:  true,
+			expect: []string{`@font-face { font-family: "Go"; font-weight: 700; font-style: normal; src: url(data:font/ttf;base64,`},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			r := NewRenderer()
+			if tc.fonts {
+				r.Fonts = testfont.New(t)
+			}
+			var buf bytes.Buffer
+			if err := r.Write(&buf, bg, l); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			for _, s := range tc.expect {
+				if !strings.Contains(got, s) {
+					t.Fatalf("the document does not contain %q:\n%s", s, got)
+				}
+			}
+			if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+				t.Fatalf("the document is not well-formed: %v", err)
+			}
+		})
+	}
+
+	if err := NewRenderer().Write(&bytes.Buffer{}, bg, nil); err == nil {
+		t.Fatal("expected an error without layout")
+	}
+}
@@ -0,0 +1,166 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Direction is a text direction.
+type Direction int
+
+const (
+	LeftToRight Direction = iota
+	RightToLeft
+)
+
+type bidiClass int
+
+const (
+	neutral bidiClass = iota
+	strongLTR
+	strongRTL
+	number
+)
+
+func classOf(cluster string) bidiClass {
+	for _, r := range cluster {
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.L:
+			return strongLTR
+		case bidi.R, bidi.AL:
+			return strongRTL
+		case bidi.EN, bidi.AN:
+			return number
+		}
+	}
+	return neutral
+}
+
+// BaseDirection returns the direction of the first strong character in s (Unicode bidi rule P2).
+// It returns LeftToRight if s has no strong character.
+func BaseDirection(s string) Direction {
+	gr := uniseg.NewGraphemes(s)
+	for gr.Next() {
+		switch classOf(gr.Str()) {
+		case strongLTR:
+			return LeftToRight
+		case strongRTL:
+			return RightToLeft
+		}
+	}
+	return LeftToRight
+}
+
+// rtlScripts are the scripts written from right to left.
+var rtlScripts = map[string]bool{
+	"Adlm": true, "Arab": true, "Hebr": true, "Mand": true, "Nkoo": true,
+	"Rohg": true, "Samr": true, "Syrc": true, "Thaa": true,
+}
+
+// LangDirection returns the direction of the script in which the language is written.
+// It returns LeftToRight for unknown languages.
+func LangDirection(lang string) Direction {
+	if lang == "" {
+		return LeftToRight
+	}
+	script, _ := language.Make(lang).Script()
+	if rtlScripts[script.String()] {
+		return RightToLeft
+	}
+	return LeftToRight
+}
+
+// VisualOrder reorders the logically ordered string into the visual order for drawers
+// which place glyphs from left to right. Right-to-left runs are reversed by grapheme cluster
+// and, for a right-to-left base direction, the order of runs is reversed as well.
+// Neutral characters between runs of the same direction take that direction,
+// otherwise the base direction.
+//
+// This is a simplified subset of the Unicode bidi algorithm: it does not handle explicit
+// embeddings, mirrored brackets, nor glyph shaping of cursive scripts such as Arabic.
+func VisualOrder(s string) string {
+	return VisualOrderWithBase(s, BaseDirection(s))
+}
+
+// VisualOrderWithBase is like VisualOrder, but it lays out the runs in the given base direction
+// instead of the direction of the first strong character.
+func VisualOrderWithBase(s string, base Direction) string {
+	var (
+		clusters []string
+		dirs     []Direction
+		strong   []bool
+		hasRTL   bool
+	)
+	gr := uniseg.NewGraphemes(s)
+	for gr.Next() {
+		// numbers are always read from left to right
+		cls := classOf(gr.Str())
+		d := LeftToRight
+		if cls == strongRTL {
+			d = RightToLeft
+			hasRTL = true
+		}
+		clusters = append(clusters, gr.Str())
+		dirs = append(dirs, d)
+		strong = append(strong, cls != neutral)
+	}
+	if !hasRTL {
+		return s
+	}
+
+	// resolve neutrals
+	for i := 0; i < len(clusters); {
+		if strong[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(clusters) && !strong[j] {
+			j++
+		}
+		d := base
+		if i > 0 && j < len(clusters) && dirs[i-1] == dirs[j] {
+			d = dirs[j]
+		}
+		for k := i; k < j; k++ {
+			dirs[k] = d
+		}
+		i = j
+	}
+
+	// split into runs and reorder them
+	type run struct {
+		dir      Direction
+		clusters []string
+	}
+	var runs []run
+	for i, c := range clusters {
+		if len(runs) == 0 || runs[len(runs)-1].dir != dirs[i] {
+			runs = append(runs, run{dir: dirs[i]})
+		}
+		runs[len(runs)-1].clusters = append(runs[len(runs)-1].clusters, c)
+	}
+	if base == RightToLeft {
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+	}
+
+	var sb strings.Builder
+	for _, r := range runs {
+		if r.dir == RightToLeft {
+			for i := len(r.clusters) - 1; i >= 0; i-- {
+				sb.WriteString(r.clusters[i])
+			}
+			continue
+		}
+		for _, c := range r.clusters {
+			sb.WriteString(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,86 @@
+package text
+
+import "testing"
+
+func TestVisualOrder(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string
+		expect string
+	}{
+		{desc: "Latin text is not changed", input: "Hello, world", expect: "Hello, world"},
+		{desc: "Hebrew word is reversed", input: "שלום", expect: "םולש"},
+		{desc: "Arabic words are reversed as a whole", input: "محمد علي", expect: "يلع دمحم"},
+		{desc: "Latin run in RTL text keeps its order", input: "שלום abc", expect: "abc םולש"},
+		{desc: "RTL run in Latin text is reversed", input: "abc שלום def", expect: "abc םולש def"},
+		{desc: "Numbers are left to right", input: "שלום 123", expect: "123 םולש"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := VisualOrder(tc.input); got != tc.expect {
+				t.Fatalf("VisualOrder() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestBaseDirection(t *testing.T) {
+	testCases := []struct {
+		input  string
+		expect Direction
+	}{
+		{input: "Hello", expect: LeftToRight},
+		{input: "123 שלום", expect: RightToLeft},
+		{input: "مرحبا Hello", expect: RightToLeft},
+		{input: "", expect: LeftToRight},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			if got := BaseDirection(tc.input); got != tc.expect {
+				t.Fatalf("BaseDirection() returns unexpected value: got=%v, want=%v", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestVisualOrderWithBase(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string
+		base   Direction
+		expect string
+	}{
+		{desc: "Latin text is not changed in RTL", input: "Hello", base: RightToLeft, expect: "Hello"},
+		{desc: "Leading Latin run is placed on the right in RTL", input: "Go שלום", base: RightToLeft, expect: "םולש Go"},
+		{desc: "Leading Latin run is placed on the left in LTR", input: "Go שלום", base: LeftToRight, expect: "Go םולש"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := VisualOrderWithBase(tc.input, tc.base); got != tc.expect {
+				t.Fatalf("VisualOrderWithBase() returns unexpected value: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestLangDirection(t *testing.T) {
+	testCases := []struct {
+		input  string
+		expect Direction
+	}{
+		{input: "ar", expect: RightToLeft},
+		{input: "he", expect: RightToLeft},
+		{input: "fa-IR", expect: RightToLeft},
+		{input: "ur", expect: RightToLeft},
+		{input: "en", expect: LeftToRight},
+		{input: "ja", expect: LeftToRight},
+		{input: "", expect: LeftToRight},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			if got := LangDirection(tc.input); got != tc.expect {
+				t.Fatalf("LangDirection() returns unexpected value: got=%v, want=%v", got, tc.expect)
+			}
+		})
+	}
+}
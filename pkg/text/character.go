@@ -1,4 +1,4 @@
-package canvas
+package text
 
 // This code is inspired by https://gist.github.com/bumcru/729632c7587f16c69d40a878c0bde750.
 
@@ -27,6 +27,22 @@ var (
 		'：': nil, '；': nil, '／': nil, '/': nil,
 		'ゝ': nil, '々': nil, '！': nil, '？': nil, '!': nil, '?': nil,
 	}
+
+	// Chinese punctuation shared by Simplified and Traditional Chinese.
+	zhStartBracketTable = map[rune]interface{}{
+		'(': nil, '{': nil, '[': nil, '<': nil,
+		'（': nil, '｛': nil, '［': nil, '【': nil, '〔': nil, '《': nil, '〈': nil,
+		'「': nil, '『': nil, '“': nil, '‘': nil,
+	}
+	zhEndCharTable = map[rune]interface{}{
+		'.': nil, ',': nil, ';': nil, ':': nil, '!': nil, '?': nil, '-': nil, '/': nil,
+		')': nil, '}': nil, ']': nil, '>': nil,
+
+		'，': nil, '。': nil, '、': nil, '；': nil, '：': nil, '！': nil, '？': nil, '．': nil,
+		'）': nil, '｝': nil, '］': nil, '】': nil, '〕': nil, '》': nil, '〉': nil,
+		'」': nil, '』': nil, '”': nil, '’': nil,
+		'…': nil, '—': nil, '·': nil, '・': nil,
+	}
 )
 
 func spaceChar(r rune) bool {
@@ -37,13 +53,3 @@ func spaceChar(r rune) bool {
 func oneByteChar(r rune) bool {
 	return len(string(r)) == 1
 }
-
-func startBracket(r rune) bool {
-	_, ok := startBracketTable[r]
-	return ok
-}
-
-func endChar(r rune) bool {
-	_, ok := endCharTable[r]
-	return ok
-}
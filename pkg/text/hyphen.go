@@ -0,0 +1,73 @@
+package text
+
+import (
+	"strings"
+)
+
+const (
+	// minimum number of letters before and after a hyphen
+	hyphenMinPrefix = 2
+	hyphenMinSuffix = 3
+)
+
+// digraphs are consonant pairs that are not split by hyphenation.
+var digraphs = []string{"ch", "ck", "gh", "ng", "ph", "qu", "sh", "th", "wh"}
+
+// HyphenationPoints returns the byte offsets in the word where a hyphen can be inserted.
+// It is a simple syllable heuristic for English rather than a dictionary-based hyphenation:
+// a word is broken before a single consonant between vowels (VC-V → V-CV) and between two
+// consonants between vowels (VC-CV), but not inside digraphs such as "th".
+// Only runs of ASCII letters are hyphenated.
+func HyphenationPoints(word string) []int {
+	var points []int
+	for start := 0; start < len(word); {
+		if !isASCIILetter(word[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(word) && isASCIILetter(word[end]) {
+			end++
+		}
+		for _, p := range syllablePoints(strings.ToLower(word[start:end])) {
+			points = append(points, start+p)
+		}
+		start = end
+	}
+	return points
+}
+
+func syllablePoints(w string) []int {
+	var points []int
+	for i := hyphenMinPrefix; i <= len(w)-hyphenMinSuffix; i++ {
+		// break before w[i]
+		switch {
+		case isVowel(w[i-1]) && !isVowel(w[i]) && i+1 < len(w) && isVowel(w[i+1]):
+			// V-CV
+			points = append(points, i)
+		case !isVowel(w[i-1]) && !isVowel(w[i]) && i >= 2 && isVowel(w[i-2]) && i+1 < len(w) && isVowel(w[i+1]):
+			// VC-CV
+			if !isDigraph(w[i-1 : i+1]) {
+				points = append(points, i)
+			}
+		}
+	}
+	return points
+}
+
+func isASCIILetter(b byte) bool {
+	return ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiouy", b) >= 0
+}
+
+func isDigraph(s string) bool {
+	for _, d := range digraphs {
+		if s == d {
+			return true
+		}
+	}
+	return false
+}
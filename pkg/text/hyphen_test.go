@@ -0,0 +1,27 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHyphenationPoints(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		word   string
+		expect []int
+	}{
+		{desc: "Long word", word: "internationalization", expect: []int{2, 5, 7, 10, 12, 14, 16}}, // in-ter-na-tio-na-li-za-tion
+		{desc: "Digraph is not split", word: "together", expect: []int{2}},                        // to-gether
+		{desc: "Short word is not hyphenated", word: "code", expect: nil},
+		{desc: "Trailing punctuation is skipped", word: "Kubernetes,", expect: []int{2, 5, 7}}, // Ku-ber-ne-tes,
+		{desc: "Non-ASCII letters are not hyphenated", word: "日本語テキスト", expect: nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := HyphenationPoints(tc.word); !reflect.DeepEqual(got, tc.expect) {
+				t.Fatalf("unexpected points: got=%v, want=%v", got, tc.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package text
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/rivo/uniseg"
+)
+
+const (
+	LangJapanese           = "ja"
+	LangSimplifiedChinese  = "zh-hans"
+	LangTraditionalChinese = "zh-hant"
+	LangThai               = "th"
+)
+
+// segmenter splits a text into the segments between which a line can be broken.
+type segmenter interface {
+	segment(text string) []string
+}
+
+var (
+	segmentersMu sync.Mutex
+	segmenters   = map[string]segmenter{}
+)
+
+// SegmentForLineBreaks splits the text into segments for line breaking with the Japanese rules.
+func SegmentForLineBreaks(text string) []string {
+	return SegmentForLineBreaksLang(text, LangJapanese)
+}
+
+// SegmentForLineBreaksLang splits the text into segments for line breaking with the rules of the language
+// (ja, zh-hans, zh-hant, or th). Unknown languages fall back to the Japanese rules.
+// Joining the segments always results in the original text.
+func SegmentForLineBreaksLang(text, lang string) []string {
+	return segmenterFor(lang).segment(text)
+}
+
+// segmenterFor returns the cached segmenter of the language.
+func segmenterFor(lang string) segmenter {
+	lang = normalizeLang(lang)
+
+	segmentersMu.Lock()
+	defer segmentersMu.Unlock()
+	if s, ok := segmenters[lang]; ok {
+		return s
+	}
+	var s segmenter
+	switch lang {
+	case LangSimplifiedChinese, LangTraditionalChinese:
+		s = &kinsokuSegmenter{startBracket: zhStartBracketTable, endChar: zhEndCharTable}
+	case LangThai:
+		s = &graphemeSegmenter{}
+	default:
+		s = &kinsokuSegmenter{startBracket: startBracketTable, endChar: endCharTable}
+	}
+	segmenters[lang] = s
+	return s
+}
+
+// normalizeLang maps the language tag (e.g. "zh-TW", "ja_JP") to one of the supported languages.
+func normalizeLang(lang string) string {
+	lang = strings.ReplaceAll(strings.ToLower(lang), "_", "-")
+	primary, region, _ := strings.Cut(lang, "-")
+	switch primary {
+	case "zh":
+		switch region {
+		case "hant", "tw", "hk", "mo":
+			return LangTraditionalChinese
+		}
+		return LangSimplifiedChinese
+	case "th":
+		return LangThai
+	}
+	return LangJapanese
+}
+
+// kinsokuSegmenter breaks a line between any characters, except that it does not break
+// inside a run of one-byte characters (e.g. a Latin word), after a start bracket,
+// or before a character that must not start a line.
+type kinsokuSegmenter struct {
+	startBracket map[rune]interface{}
+	endChar      map[rune]interface{}
+}
+
+func (s *kinsokuSegmenter) segment(text string) []string {
+	var (
+		rtext  = []rune(text)
+		length = len(rtext)
+		segs   []string
+		buf    strings.Builder
+	)
+	for i := 0; i < length; i++ {
+		r := rtext[i]
+
+		buf.WriteRune(r)
+
+		switch {
+		case spaceChar(r):
+			// noop
+		case oneByteChar(r) || s.isStartBracket(r):
+			if (i + 1) < length {
+				continue
+			}
+		case (i+1) < length && s.isEndChar(rtext[i+1]):
+			buf.WriteRune(rtext[i+1])
+			i++
+		}
+
+		segs = append(segs, buf.String())
+		buf.Reset()
+	}
+	return segs
+}
+
+func (s *kinsokuSegmenter) isStartBracket(r rune) bool {
+	_, ok := s.startBracket[r]
+	return ok
+}
+
+func (s *kinsokuSegmenter) isEndChar(r rune) bool {
+	_, ok := s.endChar[r]
+	return ok
+}
+
+// graphemeSegmenter breaks a line between grapheme clusters, so that combining vowels and
+// tone marks stay with their base characters. Runs of one-byte characters are kept together.
+// Thai words are not separated by spaces, and without a dictionary a line may be broken inside a word.
+type graphemeSegmenter struct{}
+
+func (s *graphemeSegmenter) segment(text string) []string {
+	var (
+		segs []string
+		buf  strings.Builder
+	)
+	gr := uniseg.NewGraphemes(text)
+	for gr.Next() {
+		c := gr.Str()
+		buf.WriteString(c)
+		if len(c) == 1 && !spaceChar(rune(c[0])) {
+			continue
+		}
+		segs = append(segs, buf.String())
+		buf.Reset()
+	}
+	if buf.Len() > 0 {
+		segs = append(segs, buf.String())
+	}
+	return segs
+}
@@ -0,0 +1,79 @@
+package text
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSegmentForLineBreaksLang(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		text   string
+		lang   string
+		expect []string
+	}{
+		{
+			desc:   "Japanese keeps small kana and punctuation with the previous character",
+			text:   "ちょっと、テスト",
+			lang:   "ja",
+			expect: []string{"ちょ", "っ", "と、", "テ", "ス", "ト"},
+		},
+		{
+			desc:   "Latin word is kept together",
+			text:   "Go言語",
+			lang:   "ja",
+			expect: []string{"Go言", "語"},
+		},
+		{
+			desc:   "Simplified Chinese keeps the full-width comma with the previous character",
+			text:   "你好，世界。",
+			lang:   "zh-hans",
+			expect: []string{"你", "好，", "世", "界。"},
+		},
+		{
+			desc:   "Traditional Chinese keeps the opening quote with the next character",
+			text:   "他說「你好」",
+			lang:   "zh-TW",
+			expect: []string{"他", "說", "「你", "好」"},
+		},
+		{
+			desc:   "Japanese rules do not apply the Chinese comma",
+			text:   "你好，世界",
+			lang:   "ja",
+			expect: []string{"你", "好", "，", "世", "界"},
+		},
+		{
+			desc:   "Thai keeps combining marks with the base character",
+			text:   "ที่นี่",
+			lang:   "th",
+			expect: []string{"ที่", "นี่"},
+		},
+		{
+			desc:   "Unknown language falls back to Japanese",
+			text:   "ちょっと",
+			lang:   "xx",
+			expect: []string{"ちょ", "っ", "と"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := SegmentForLineBreaksLang(tc.text, tc.lang)
+			if !reflect.DeepEqual(got, tc.expect) {
+				t.Fatalf("unexpected segments: got=%q, want=%q", got, tc.expect)
+			}
+			if strings.Join(got, "") != tc.text {
+				t.Fatalf("segments do not restore the text: %q", got)
+			}
+		})
+	}
+}
+
+func TestSegmenterIsCached(t *testing.T) {
+	if segmenterFor("zh-TW") != segmenterFor("zh-hant") {
+		t.Fatal("segmenter is not cached per language")
+	}
+	if !reflect.DeepEqual(SegmentForLineBreaks("ちょっと"), SegmentForLineBreaksLang("ちょっと", "ja")) {
+		t.Fatal("SegmentForLineBreaks does not default to Japanese")
+	}
+}
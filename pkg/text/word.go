@@ -0,0 +1,73 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// latinThreshold is the minimum ratio of Latin letters to all letters for a text to be treated as Latin.
+const latinThreshold = 0.8
+
+// IsLatin reports whether the text is predominantly written in the Latin script (e.g. English).
+// Digits, punctuation, and spaces are not counted. A text without letters is not Latin.
+func IsLatin(text string) bool {
+	var letters, latin int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+		}
+	}
+	return letters > 0 && float64(latin)/float64(letters) >= latinThreshold
+}
+
+// SegmentWords splits the text into words for line breaking. Spaces following a word are
+// kept in the segment of the word, so that joining the segments results in the original text.
+func SegmentWords(text string) []string {
+	var (
+		segs    []string
+		buf     strings.Builder
+		inWord  bool
+		inSpace bool
+	)
+	for _, r := range text {
+		sp := unicode.IsSpace(r)
+		if !sp && inSpace && inWord {
+			segs = append(segs, buf.String())
+			buf.Reset()
+		}
+		buf.WriteRune(r)
+		inWord = inWord || !sp
+		inSpace = sp
+	}
+	if buf.Len() > 0 {
+		segs = append(segs, buf.String())
+	}
+	return segs
+}
+
+// SegmentFunc splits a text into the segments between which a line can be broken.
+// Joining the segments must result in the original text.
+type SegmentFunc func(text string) []string
+
+// SegmentForWrapping splits the text into segments for line breaking. Latin texts are split
+// into words, and the others are split with the line breaking rules of the language.
+func SegmentForWrapping(text, lang string) []string {
+	return SegmentForWrappingFunc(text, lang, nil)
+}
+
+// SegmentForWrappingFunc is like SegmentForWrapping, but the texts which are not Latin are split by
+// the function instead of the rules of the language if it is not nil, e.g. the Parse method of
+// a BudouX parser with a custom model.
+func SegmentForWrappingFunc(text, lang string, segment SegmentFunc) []string {
+	if IsLatin(text) {
+		return SegmentWords(text)
+	}
+	if segment != nil {
+		return segment(text)
+	}
+	return SegmentForLineBreaksLang(text, lang)
+}
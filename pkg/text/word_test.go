@@ -0,0 +1,67 @@
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsLatin(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		text   string
+		expect bool
+	}{
+		{desc: "English", text: "Generate a TwitterCard image", expect: true},
+		{desc: "Accented Latin letters", text: "Café crème brûlée", expect: true},
+		{desc: "Digits and punctuation are ignored", text: "Go 1.23: what's new?", expect: true},
+		{desc: "Japanese", text: "日本語のタイトル", expect: false},
+		{desc: "Japanese with an English word", text: "Go言語でOGP画像を生成する", expect: false},
+		{desc: "English with a Japanese word", text: "How to write 日本 in a modern web application", expect: true},
+		{desc: "No letters", text: "2024 !!", expect: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := IsLatin(tc.text); got != tc.expect {
+				t.Fatalf("IsLatin(%q) = %v, want %v", tc.text, got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestSegmentForWrapping(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		text   string
+		expect []string
+	}{
+		{desc: "Latin text is split into words", text: "Crème brûlée  recipe", expect: []string{"Crème ", "brûlée  ", "recipe"}},
+		{desc: "Leading spaces stay with the first word", text: " Hello world", expect: []string{" Hello ", "world"}},
+		{desc: "Japanese text is split by the line breaking rules", text: "日本語です", expect: []string{"日", "本", "語", "で", "す"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := SegmentForWrapping(tc.text, LangJapanese); !reflect.DeepEqual(got, tc.expect) {
+				t.Fatalf("unexpected segments: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestSegmentForWrappingFunc(t *testing.T) {
+	phrases := func(string) []string { return []string{"今日は", "晴れです"} }
+	testCases := []struct {
+		desc   string
+		text   string
+		expect []string
+	}{
+		{desc: "Non-Latin text is split by the function", text: "今日は晴れです", expect: []string{"今日は", "晴れです"}},
+		{desc: "Latin text is still split into words", text: "Hello world", expect: []string{"Hello ", "world"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := SegmentForWrappingFunc(tc.text, LangJapanese, phrases); !reflect.DeepEqual(got, tc.expect) {
+				t.Fatalf("unexpected segments: got=%q, want=%q", got, tc.expect)
+			}
+		})
+	}
+}